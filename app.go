@@ -0,0 +1,59 @@
+package WdaGo
+
+import (
+	"fmt"
+	"time"
+)
+
+// WaitForAppState 等待app进入指定状态
+func (session *WdaSession) WaitForAppState(bundleId string, state AppState, timeout time.Duration) error {
+	session, end := session.begin("WdaSession.WaitForAppState")
+	defer end()
+
+	var current AppState
+	err := WaitUntil(timeout, DefaultPollInterval, func() (bool, error) {
+		var err error
+		current, err = session.GetAppState(bundleId)
+		if err != nil {
+			return false, err
+		}
+		return current == state, nil
+	})
+	if err != nil {
+		return fmt.Errorf(" Wait app %v state %v failed, current state is %v :%w", bundleId, state, current, err)
+	}
+	return nil
+}
+
+// RelaunchApp 关闭app后重新启动，并等待app进入前台
+func (session *WdaSession) RelaunchApp(option AppLaunchOption, timeout time.Duration) error {
+	session, end := session.begin("WdaSession.RelaunchApp")
+	defer end()
+
+	state, err := session.GetAppState(option.BundleId)
+	if err != nil {
+		return err
+	}
+
+	if state != AppStateNotRunning && state != AppStateUnknown {
+		if err = session.TerminateApp(option.BundleId); err != nil {
+			return err
+		}
+		if err = session.WaitForAppState(option.BundleId, AppStateNotRunning, timeout); err != nil {
+			return err
+		}
+	}
+
+	if err = session.LaunchAppWithOption(option); err != nil {
+		return err
+	}
+	return session.WaitForAppState(option.BundleId, AppStateRunningForeground, timeout)
+}
+
+// RestartApp 使用bundleId重启app，不带启动参数
+func (session *WdaSession) RestartApp(bundleId string, timeout time.Duration) error {
+	session, end := session.begin("WdaSession.RestartApp")
+	defer end()
+
+	return session.RelaunchApp(AppLaunchOption{BundleId: bundleId}, timeout)
+}
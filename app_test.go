@@ -0,0 +1,143 @@
+package WdaGo
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeApp 模拟app状态，关闭和启动后前lag次查询仍返回原来的状态
+type fakeApp struct {
+	mu      sync.Mutex
+	state   AppState
+	next    AppState
+	lag     int
+	pending int
+	launch  AppLaunchOption
+	calls   []string
+}
+
+func (fake *fakeApp) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	endpoint := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+	fake.calls = append(fake.calls, endpoint)
+	switch endpoint {
+	case "state":
+		if fake.pending > 0 {
+			fake.pending--
+		} else {
+			fake.state = fake.next
+		}
+		writeValue(w, strconv.Itoa(int(fake.state)))
+	case "terminate":
+		fake.next, fake.pending = AppStateNotRunning, fake.lag
+		writeValue(w, "true")
+	case "launch":
+		json.NewDecoder(r.Body).Decode(&fake.launch)
+		fake.next, fake.pending = AppStateRunningForeground, fake.lag
+		writeValue(w, "null")
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// actions 去掉重复的状态查询后的请求顺序
+func (fake *fakeApp) actions() []string {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	return slices.Compact(slices.Clone(fake.calls))
+}
+
+func TestRelaunchApp(t *testing.T) {
+	wait := false
+	option := AppLaunchOption{
+		BundleId:                "com.demo",
+		Arguments:               []string{"-reset"},
+		Environment:             map[string]string{"ENV": "test"},
+		ShouldWaitForQuiescence: &wait,
+	}
+	tests := []struct {
+		name  string
+		state AppState
+		want  []string
+	}{
+		{"running", AppStateRunningForeground, []string{"state", "terminate", "state", "launch", "state"}},
+		{"background", AppStateRunningBackground, []string{"state", "terminate", "state", "launch", "state"}},
+		{"not running", AppStateNotRunning, []string{"state", "launch", "state"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeApp{state: tt.state, next: tt.state, lag: 1}
+			session := newTestSession(t, fake)
+
+			if err := session.RelaunchApp(option, 5*time.Second); err != nil {
+				t.Fatalf("RelaunchApp() error = %v", err)
+			}
+			if got := fake.actions(); !slices.Equal(got, tt.want) {
+				t.Errorf("calls = %v, want %v", got, tt.want)
+			}
+			if fake.state != AppStateRunningForeground {
+				t.Errorf("state = %v, want running foreground", fake.state)
+			}
+			launch := fake.launch
+			if launch.BundleId != option.BundleId || !slices.Equal(launch.Arguments, option.Arguments) ||
+				launch.Environment["ENV"] != "test" || launch.ShouldWaitForQuiescence == nil || *launch.ShouldWaitForQuiescence {
+				t.Errorf("launch option = %+v, want %+v", launch, option)
+			}
+		})
+	}
+}
+
+func TestWaitForAppState(t *testing.T) {
+	fake := &fakeApp{state: AppStateRunningBackground, next: AppStateRunningForeground, pending: 2}
+	session := newTestSession(t, fake)
+
+	// 状态变化前持续轮询
+	if err := session.WaitForAppState("com.demo", AppStateRunningForeground, 5*time.Second); err != nil {
+		t.Fatalf("WaitForAppState() error = %v", err)
+	}
+	if got := len(fake.calls); got != 3 {
+		t.Errorf("state queried %d times, want 3", got)
+	}
+
+	// 超时时返回的错误中包含当前状态
+	err := session.WaitForAppState("com.demo", AppStateNotRunning, 100*time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "com.demo") || !strings.Contains(err.Error(), "current state is "+AppStateRunningForeground.String()) {
+		t.Errorf("WaitForAppState() error = %v, want timeout with current state", err)
+	}
+}
+
+func TestJudgeResponseError(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"null", `{"value":null,"sessionId":"s1"}`, ""},
+		{"true", `{"value":true,"sessionId":"s1"}`, ""},
+		{"object", `{"value":{"state":4},"sessionId":"s1"}`, ""},
+		{"false", `{"value":false,"sessionId":"s1"}`, "Wda returned false"},
+		{"error object", `{"value":{"error":"invalid argument","message":"app com.demo is not installed"},"sessionId":"s1"}`,
+			"invalid argument: app com.demo is not installed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := JudgeResponseError([]byte(tt.body))
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("JudgeResponseError() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("JudgeResponseError() = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
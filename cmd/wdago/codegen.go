@@ -0,0 +1,136 @@
+package main
+
+import (
+	"fmt"
+	"go/format"
+	"strconv"
+	"strings"
+)
+
+// elementRef 元素id在导出代码中的引用，id由第step个find命令的第index个结果得到
+type elementRef struct {
+	step  int
+	index int
+}
+
+// generateGoTest 将shell中执行过的命令导出为go test代码
+// 元素id只在当前session有效，因此导出时将元素id替换为产生该id的find命令
+func generateGoTest(steps []shellStep, wdaUrl, testName string) ([]byte, error) {
+	refs := make(map[string]elementRef)
+	used := make(map[int]bool)
+	for i, step := range steps {
+		if step.args[0] == "find" {
+			for index, element := range step.elements {
+				if _, ok := refs[element]; !ok {
+					refs[element] = elementRef{step: i, index: index}
+				}
+			}
+			continue
+		}
+		if len(step.args) > 1 {
+			if ref, ok := refs[step.args[1]]; ok {
+				used[ref.step] = true
+			}
+		}
+	}
+
+	elementExpr := func(id string) string {
+		if ref, ok := refs[id]; ok {
+			return fmt.Sprintf("elements%d[%d]", ref.step, ref.index)
+		}
+		return strconv.Quote(id)
+	}
+
+	var body strings.Builder
+	for i, step := range steps {
+		args := step.args
+		body.WriteString(fmt.Sprintf("\n// %s\n", joinArgs(args)))
+
+		switch args[0] {
+		case "find":
+			strategy := strconv.Quote(resolveStrategy(args[1]))
+			value := strconv.Quote(args[2])
+			if used[i] {
+				maxIndex := 0
+				for _, ref := range refs {
+					if ref.step == i && ref.index > maxIndex {
+						maxIndex = ref.index
+					}
+				}
+				fmt.Fprintf(&body, "elements%d, err := session.FindElements(%s, %s)\n", i, strategy, value)
+				fmt.Fprintf(&body, "if err != nil {\nt.Fatal(err)\n}\n")
+				fmt.Fprintf(&body, "if len(elements%d) <= %d {\nt.Fatalf(\"element not found by %%s %%q\", %s, %s)\n}\n",
+					i, maxIndex, strategy, value)
+			} else {
+				fmt.Fprintf(&body, "if elements, err := session.FindElements(%s, %s); err != nil || len(elements) == 0 {\n", strategy, value)
+				fmt.Fprintf(&body, "t.Fatalf(\"element not found by %%s %%q: %%v\", %s, %s, err)\n}\n", strategy, value)
+			}
+		case "click":
+			writeCall(&body, fmt.Sprintf("session.ClickElement(%s)", elementExpr(args[1])))
+		case "type":
+			writeCall(&body, fmt.Sprintf("session.TypingText(%s, %s)", elementExpr(args[1]), strconv.Quote(args[2])))
+		case "clear":
+			writeCall(&body, fmt.Sprintf("session.ClearText(%s)", elementExpr(args[1])))
+		case "keys":
+			writeCall(&body, fmt.Sprintf("session.SendKeys(%s)", strconv.Quote(args[1])))
+		case "tap":
+			writeCall(&body, fmt.Sprintf("session.TapWithLocation(WdaGo.ElementLocation{X: %s, Y: %s})", args[1], args[2]))
+		case "swipe":
+			writeCall(&body, fmt.Sprintf("session.SwipeWithLocation(%s, %s, %s, %s, %s)", args[1], args[2], args[3], args[4], args[5]))
+		case "screenshot":
+			fmt.Fprintf(&body, "if _, err := session.CurrentScreenShot(\".\", %s); err != nil {\nt.Fatal(err)\n}\n", strconv.Quote(args[1]))
+		case "home":
+			writeCall(&body, "session.BackToHomePage()")
+		case "lock":
+			writeCall(&body, "session.LockedDevice()")
+		case "unlock":
+			writeCall(&body, "session.UnlockedDevice()")
+		case "alert":
+			if len(args) > 1 && (args[1] == "accept" || args[1] == "dismiss") {
+				method := "AlertAccept"
+				if args[1] == "dismiss" {
+					method = "AlertDismiss"
+				}
+				buttons := make([]string, 0, len(args)-2)
+				for _, button := range args[2:] {
+					buttons = append(buttons, strconv.Quote(button))
+				}
+				writeCall(&body, fmt.Sprintf("session.%s(%s)", method, strings.Join(buttons, ", ")))
+			}
+		case "apps":
+			if len(args) == 3 && args[1] == "launch" {
+				writeCall(&body, fmt.Sprintf("session.LaunchApp(%s)", strconv.Quote(args[2])))
+			} else if len(args) == 3 && args[1] == "terminate" {
+				writeCall(&body, fmt.Sprintf("session.TerminateApp(%s)", strconv.Quote(args[2])))
+			}
+		}
+	}
+
+	source := fmt.Sprintf(`package wdatest
+
+import (
+	"testing"
+
+	WdaGo "github.com/Ning9527fff/WdaGo"
+)
+
+// %s generated by wdago shell
+func %s(t *testing.T) {
+	session := WdaGo.GetWdaSession(%s)
+	if err := session.GetSession(""); err != nil {
+		t.Fatal(err)
+	}
+	defer session.DeleteSession()
+%s}
+`, testName, testName, strconv.Quote(wdaUrl), body.String())
+
+	code, err := format.Source([]byte(source))
+	if err != nil {
+		return nil, fmt.Errorf("format generated code failed: %v", err)
+	}
+	return code, nil
+}
+
+func writeCall(body *strings.Builder, call string) {
+	fmt.Fprintf(body, "if err := %s; err != nil {\nt.Fatal(err)\n}\n", call)
+}
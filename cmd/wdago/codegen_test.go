@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bytes"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+func TestGenerateGoTest(t *testing.T) {
+	steps := []shellStep{
+		{args: []string{"find", "aid", "login"}, elements: []string{"E1", "E2"}},
+		{args: []string{"find", "xpath", "//XCUIElementTypeButton"}, elements: []string{"E3"}},
+		{args: []string{"click", "E2"}},
+		{args: []string{"type", "E1", `say "hi"`}},
+		{args: []string{"clear", "stale"}},
+		{args: []string{"tap", "100", "200.5"}},
+		{args: []string{"swipe", "1", "2", "3", "4", "0"}},
+		{args: []string{"alert", "accept", "Allow"}},
+		{args: []string{"apps", "launch", "com.demo"}},
+		{args: []string{"status"}},
+	}
+	code, err := generateGoTest(steps, "http://127.0.0.1:8100", "TestLogin")
+	if err != nil {
+		t.Fatalf("generateGoTest() error = %v", err)
+	}
+
+	// 生成的代码可以解析，并且已经gofmt
+	if _, err := parser.ParseFile(token.NewFileSet(), "login_test.go", code, parser.AllErrors); err != nil {
+		t.Fatalf("generated code does not parse: %v\n%s", err, code)
+	}
+	formatted, err := format.Source(code)
+	if err != nil || !bytes.Equal(formatted, code) {
+		t.Errorf("generated code is not gofmt formatted: %v\n%s", err, code)
+	}
+
+	source := string(code)
+	for _, want := range []string{
+		"package wdatest",
+		"func TestLogin(t *testing.T) {",
+		`session := WdaGo.GetWdaSession("http://127.0.0.1:8100")`,
+		// 后续命令用到的find结果保存为变量，并检查数量
+		`elements0, err := session.FindElements("accessibility id", "login")`,
+		"if len(elements0) <= 1 {",
+		// 未被引用的find只检查是否找到
+		`if elements, err := session.FindElements("xpath", "//XCUIElementTypeButton"); err != nil || len(elements) == 0 {`,
+		"session.ClickElement(elements0[1])",
+		`session.TypingText(elements0[0], "say \"hi\"")`,
+		// 不是find得到的元素id保持原样
+		`session.ClearText("stale")`,
+		"session.TapWithLocation(WdaGo.ElementLocation{X: 100, Y: 200.5})",
+		"session.SwipeWithLocation(1, 2, 3, 4, 0)",
+		`session.AlertAccept("Allow")`,
+		`session.LaunchApp("com.demo")`,
+		`// type E1 "say \"hi\""`,
+	} {
+		if !strings.Contains(source, want) {
+			t.Errorf("generated code does not contain %q\n%s", want, source)
+		}
+	}
+	if strings.Contains(source, "elements1") {
+		t.Errorf("unused find result assigned to a variable\n%s", source)
+	}
+}
@@ -0,0 +1,370 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	WdaGo "github.com/Ning9527fff/WdaGo"
+)
+
+// strategyAliases 命令行中可使用的查找策略简写
+var strategyAliases = map[string]string{
+	"accessibility-id": WdaGo.StrategyAccessibilityId,
+	"aid":              WdaGo.StrategyAccessibilityId,
+	"class-name":       WdaGo.StrategyClassName,
+	"class":            WdaGo.StrategyClassName,
+	"class-chain":      WdaGo.StrategyClassChain,
+	"chain":            WdaGo.StrategyClassChain,
+	"predicate":        WdaGo.StrategyPredicate,
+	"link-text":        WdaGo.StrategyLinkText,
+	"partial-link":     WdaGo.StrategyPartialLinkText,
+}
+
+func init() {
+	commands = map[string]command{
+		"status":     {"status                                   wda status", cmdStatus},
+		"info":       {"info                                     device info", cmdInfo},
+		"battery":    {"battery                                  battery level and state", cmdBattery},
+		"screenshot": {"screenshot [-o file.png]                 save screenshot", cmdScreenshot},
+		"source":     {"source [--format xml|json|description]   page source", cmdSource},
+		"apps":       {"apps list|launch|terminate|state [bundleId]", cmdApps},
+		"tap":        {"tap x y                                  tap at point", cmdTap},
+		"swipe":      {"swipe x1 y1 x2 y2 [--duration s]         swipe between points", cmdSwipe},
+		"find":       {"find <strategy> <value>                  find elements, print element ids", cmdFind},
+		"locate":     {"locate x y                               suggest ranked locators for element at point", cmdLocate},
+		"alert":      {"alert text|buttons|accept|dismiss [button]", cmdAlert},
+		"lock":       {"lock                                     lock device", cmdLock},
+		"unlock":     {"unlock                                   unlock device", cmdUnlock},
+		"home":       {"home                                     go to home screen", cmdHome},
+		"session":    {"session create [bundleId]|check|delete   manage wda session", cmdSession},
+		"pageobject": {"pageobject [--source page.xml] [--config c.yaml] [--package p] [--name N] [-o file.go]", cmdPageObject},
+		"shell":      {"shell                                    interactive shell", cmdShell},
+		"inspect":    {"inspect [--addr 127.0.0.1:8200]          web inspector for screenshot and element tree", cmdInspect},
+		"monitor":    {"monitor [--interval 10s] [--threshold 3] [--hook cmd] watch wda health and recover", cmdMonitor},
+		"metrics":    {"metrics [--addr 127.0.0.1:9200] [--device name] prometheus metrics endpoint", cmdMetrics},
+		"telemetry":  {"telemetry [--interval 30s] [--duration 1h] [-o file.csv|json] sample battery, thermal and app state", cmdTelemetry},
+		"gateway":    {"gateway [--config gateway.yaml] [--addr 127.0.0.1:8300] serve device pool over http", cmdGateway},
+		"grpc":       {"grpc [--addr 127.0.0.1:8400] [--screenshot-interval 1s] serve wda operations over grpc", cmdGrpc},
+		"run":        {"run [--var K=V] [--output dir] [--junit file] [--html file] file.yaml run yaml/json scenario", cmdRun},
+	}
+}
+
+func cmdStatus(c *cli, args []string) (interface{}, error) {
+	return c.client().GetStatus()
+}
+
+func cmdInfo(c *cli, args []string) (interface{}, error) {
+	session, err := c.getSession()
+	if err != nil {
+		return nil, err
+	}
+	return session.GetDeviceInfo()
+}
+
+func cmdBattery(c *cli, args []string) (interface{}, error) {
+	session, err := c.getSession()
+	if err != nil {
+		return nil, err
+	}
+	return session.GetBatteryInfo()
+}
+
+func cmdScreenshot(c *cli, args []string) (interface{}, error) {
+	flags := flag.NewFlagSet("screenshot", flag.ContinueOnError)
+	output := flags.String("o", "screenshot.png", "output file")
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	data, err := c.client().ScreenShotData()
+	if err != nil {
+		return nil, err
+	}
+	if err = os.WriteFile(*output, data, 0644); err != nil {
+		return nil, err
+	}
+	return map[string]string{"path": *output}, nil
+}
+
+func cmdSource(c *cli, args []string) (interface{}, error) {
+	flags := flag.NewFlagSet("source", flag.ContinueOnError)
+	format := flags.String("format", WdaGo.SourceFormatXml, "xml, json or description")
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	source, err := c.client().GetSource(*format)
+	if err != nil {
+		return nil, err
+	}
+	return rawOutput(source), nil
+}
+
+func cmdApps(c *cli, args []string) (interface{}, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("usage: apps list|launch|terminate|state [bundleId]")
+	}
+
+	session, err := c.getSession()
+	if err != nil {
+		return nil, err
+	}
+
+	if args[0] == "list" {
+		return session.GetAppList()
+	}
+
+	flags := flag.NewFlagSet("apps "+args[0], flag.ContinueOnError)
+	var arguments, environment stringList
+	flags.Var(&arguments, "arg", "launch argument, can be repeated")
+	flags.Var(&environment, "env", "launch environment KEY=VALUE, can be repeated")
+	if err = flags.Parse(args[1:]); err != nil {
+		return nil, err
+	}
+	if flags.NArg() != 1 {
+		return nil, fmt.Errorf("usage: apps %s <bundleId>", args[0])
+	}
+	bundleId := flags.Arg(0)
+
+	switch args[0] {
+	case "launch":
+		option := WdaGo.AppLaunchOption{BundleId: bundleId, Arguments: arguments}
+		if len(environment) > 0 {
+			option.Environment = make(map[string]string, len(environment))
+			for _, env := range environment {
+				key, value, _ := strings.Cut(env, "=")
+				option.Environment[key] = value
+			}
+		}
+		return nil, session.LaunchAppWithOption(option)
+	case "terminate":
+		return nil, session.TerminateApp(bundleId)
+	case "state":
+		state, err := session.GetAppState(bundleId)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"bundleId": bundleId, "state": state}, nil
+	default:
+		return nil, fmt.Errorf("unknown apps command %q", args[0])
+	}
+}
+
+func cmdTap(c *cli, args []string) (interface{}, error) {
+	points, err := parseFloats(args, 2)
+	if err != nil {
+		return nil, fmt.Errorf("usage: tap x y: %v", err)
+	}
+
+	session, err := c.getSession()
+	if err != nil {
+		return nil, err
+	}
+	return nil, session.TapWithLocation(WdaGo.ElementLocation{X: points[0], Y: points[1]})
+}
+
+// cmdLocate 为坐标处的元素生成定位建议，按推荐程度排序
+func cmdLocate(c *cli, args []string) (interface{}, error) {
+	points, err := parseFloats(args, 2)
+	if err != nil {
+		return nil, fmt.Errorf("usage: locate x y: %v", err)
+	}
+
+	session, err := c.getSession()
+	if err != nil {
+		return nil, err
+	}
+
+	node, suggestions, err := session.SuggestLocatorsAt(points[0], points[1])
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"element":  node.Title(),
+		"rect":     node.Rect,
+		"locators": suggestions,
+	}, nil
+}
+
+func cmdSwipe(c *cli, args []string) (interface{}, error) {
+	flags := flag.NewFlagSet("swipe", flag.ContinueOnError)
+	duration := flags.Float64("duration", 0, "press duration before moving, in seconds")
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	points, err := parseFloats(flags.Args(), 4)
+	if err != nil {
+		return nil, fmt.Errorf("usage: swipe x1 y1 x2 y2: %v", err)
+	}
+
+	session, err := c.getSession()
+	if err != nil {
+		return nil, err
+	}
+	return nil, session.SwipeWithLocation(points[0], points[1], points[2], points[3], *duration)
+}
+
+func cmdFind(c *cli, args []string) (interface{}, error) {
+	if len(args) != 2 {
+		return nil, fmt.Errorf("usage: find <strategy> <value>, strategy is one of %v", WdaGo.Strategies)
+	}
+
+	session, err := c.getSession()
+	if err != nil {
+		return nil, err
+	}
+
+	elements, err := session.FindElements(resolveStrategy(args[0]), args[1])
+	if err != nil {
+		return nil, err
+	}
+	if elements == nil {
+		elements = []string{}
+	}
+	return map[string]interface{}{"elements": elements}, nil
+}
+
+func cmdAlert(c *cli, args []string) (interface{}, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("usage: alert text|buttons|accept|dismiss [button]")
+	}
+
+	session, err := c.getSession()
+	if err != nil {
+		return nil, err
+	}
+
+	switch args[0] {
+	case "text":
+		text, err := session.AlertGet()
+		if err != nil {
+			return nil, err
+		}
+		return map[string]string{"text": text}, nil
+	case "buttons":
+		return session.AlertButtons()
+	case "accept":
+		return nil, session.AlertAccept(args[1:]...)
+	case "dismiss":
+		return nil, session.AlertDismiss(args[1:]...)
+	default:
+		return nil, fmt.Errorf("unknown alert command %q", args[0])
+	}
+}
+
+func cmdLock(c *cli, args []string) (interface{}, error) {
+	session, err := c.getSession()
+	if err != nil {
+		return nil, err
+	}
+	return nil, session.LockedDevice()
+}
+
+func cmdUnlock(c *cli, args []string) (interface{}, error) {
+	session, err := c.getSession()
+	if err != nil {
+		return nil, err
+	}
+	return nil, session.UnlockedDevice()
+}
+
+func cmdHome(c *cli, args []string) (interface{}, error) {
+	session, err := c.getSession()
+	if err != nil {
+		return nil, err
+	}
+	return nil, session.BackToHomePage()
+}
+
+func cmdSession(c *cli, args []string) (interface{}, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("usage: session create [bundleId]|check|delete")
+	}
+
+	switch args[0] {
+	case "create":
+		bundleId := ""
+		if len(args) > 1 {
+			bundleId = args[1]
+		}
+		session := c.client()
+		if err := session.GetSession(bundleId); err != nil {
+			return nil, err
+		}
+		c.sessionId = session.SessionId()
+		return map[string]string{"sessionId": session.SessionId()}, nil
+	case "check":
+		session, err := c.getSession()
+		if err != nil {
+			return nil, err
+		}
+		valid, err := session.CheckSession()
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"sessionId": session.SessionId(), "valid": valid}, nil
+	case "delete":
+		if c.sessionId == "" {
+			sessionId, err := c.activeSessionId()
+			if err != nil {
+				return nil, err
+			}
+			if sessionId == "" {
+				return nil, fmt.Errorf("no session to delete")
+			}
+			c.sessionId = sessionId
+		}
+		session, err := c.getSession()
+		if err != nil {
+			return nil, err
+		}
+		if err = session.DeleteSession(); err != nil {
+			return nil, err
+		}
+		c.sessionId = ""
+		c.session = nil
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unknown session command %q", args[0])
+	}
+}
+
+// resolveStrategy 将命令行简写转为wda查找策略
+func resolveStrategy(name string) string {
+	if strategy, ok := strategyAliases[strings.ToLower(name)]; ok {
+		return strategy
+	}
+	return strings.ReplaceAll(name, "-", " ")
+}
+
+func parseFloats(args []string, count int) ([]float64, error) {
+	if len(args) != count {
+		return nil, fmt.Errorf("need %d numbers, got %d", count, len(args))
+	}
+
+	values := make([]float64, count)
+	for i, arg := range args {
+		value, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+// stringList 可重复的字符串参数
+type stringList []string
+
+func (list *stringList) String() string {
+	return strings.Join(*list, ",")
+}
+
+func (list *stringList) Set(value string) error {
+	*list = append(*list, value)
+	return nil
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/Ning9527fff/WdaGo/gateway"
+)
+
+func cmdGateway(c *cli, args []string) (interface{}, error) {
+	flags := flag.NewFlagSet("gateway", flag.ContinueOnError)
+	addr := flags.String("addr", "127.0.0.1:8300", "listen address of the gateway")
+	configPath := flags.String("config", "gateway.yaml", "gateway config with devices and tokens")
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	config, err := gateway.LoadConfig(*configPath)
+	if err != nil {
+		return nil, err
+	}
+	server, err := gateway.NewServer(*config)
+	if err != nil {
+		return nil, err
+	}
+
+	listener, err := net.Listen("tcp", *addr)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(c.out, "gateway for %d devices is running at http://%s\n", len(config.Devices), listener.Addr())
+
+	return nil, http.Serve(listener, server)
+}
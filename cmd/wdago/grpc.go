@@ -0,0 +1,35 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net"
+
+	"github.com/Ning9527fff/WdaGo/grpcwda"
+	"github.com/Ning9527fff/WdaGo/grpcwda/wdapb"
+	"google.golang.org/grpc"
+)
+
+func cmdGrpc(c *cli, args []string) (interface{}, error) {
+	flags := flag.NewFlagSet("grpc", flag.ContinueOnError)
+	addr := flags.String("addr", "127.0.0.1:8400", "listen address of the grpc service")
+	interval := flags.Duration("screenshot-interval", grpcwda.DefaultScreenshotInterval, "default interval of screenshot streams")
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	session, err := c.getSession()
+	if err != nil {
+		return nil, err
+	}
+
+	listener, err := net.Listen("tcp", *addr)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(c.out, "grpc service is running at %s\n", listener.Addr())
+
+	server := grpc.NewServer()
+	wdapb.RegisterWdaServiceServer(server, grpcwda.NewServer(session, grpcwda.ServerOption{ScreenshotInterval: *interval}))
+	return nil, server.Serve(listener)
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	_ "embed"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	WdaGo "github.com/Ning9527fff/WdaGo"
+)
+
+//go:embed inspect.html
+var inspectPage []byte
+
+// inspector 网页版元素查看器，页面树在刷新时缓存，定位建议和点击都基于最近一次的页面树
+type inspector struct {
+	session *WdaGo.WdaSession
+
+	mu   sync.Mutex
+	tree *WdaGo.SourceNode
+}
+
+type tapRequest struct {
+	Path string   `json:"path"`
+	X    *float64 `json:"x"`
+	Y    *float64 `json:"y"`
+}
+
+func cmdInspect(c *cli, args []string) (interface{}, error) {
+	flags := flag.NewFlagSet("inspect", flag.ContinueOnError)
+	addr := flags.String("addr", "127.0.0.1:8200", "listen address of the inspector web ui")
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	session, err := c.getSession()
+	if err != nil {
+		return nil, err
+	}
+
+	listener, err := net.Listen("tcp", *addr)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(c.out, "inspector is running at http://%s\n", listener.Addr())
+
+	return nil, serveInspector(session, listener)
+}
+
+// inspectReadHeaderTimeout 读取请求头的超时时间，避免慢连接一直占用
+const inspectReadHeaderTimeout = 10 * time.Second
+
+// serveInspector 在listener上运行查看器，shell中在后台调用
+func serveInspector(session *WdaGo.WdaSession, listener net.Listener) error {
+	ins := &inspector{session: session}
+	server := &http.Server{
+		Handler:           ins.handler(),
+		ReadHeaderTimeout: inspectReadHeaderTimeout,
+	}
+	return server.Serve(listener)
+}
+
+func (ins *inspector) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(inspectPage)
+	})
+	mux.HandleFunc("/api/screenshot", ins.handleScreenshot)
+	mux.HandleFunc("/api/source", ins.handleSource)
+	mux.HandleFunc("/api/locators", ins.handleLocators)
+	mux.HandleFunc("/api/tap", ins.handleTap)
+	return mux
+}
+
+func (ins *inspector) handleScreenshot(w http.ResponseWriter, r *http.Request) {
+	data, err := ins.session.ScreenShotData()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Write(data)
+}
+
+func (ins *inspector) handleSource(w http.ResponseWriter, r *http.Request) {
+	tree, err := ins.session.GetSourceTree()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	ins.mu.Lock()
+	ins.tree = tree
+	ins.mu.Unlock()
+	writeJson(w, tree)
+}
+
+func (ins *inspector) handleLocators(w http.ResponseWriter, r *http.Request) {
+	ins.mu.Lock()
+	tree := ins.tree
+	ins.mu.Unlock()
+	if tree == nil {
+		writeError(w, fmt.Errorf("page source is not loaded"))
+		return
+	}
+
+	node := tree.FindByPath(r.URL.Query().Get("path"))
+	if node == nil {
+		writeError(w, fmt.Errorf("element %s not found", r.URL.Query().Get("path")))
+		return
+	}
+	writeJson(w, WdaGo.SuggestLocators(tree, node))
+}
+
+// handleTap 点击元素中心点或者指定坐标，坐标单位为点
+// 只接受同源的json请求，防止用户打开的其他网页通过跨站请求操作设备
+func (ins *inspector) handleTap(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := checkSameOriginJson(r); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	var req tapRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	var point WdaGo.ElementLocation
+	if req.X != nil && req.Y != nil {
+		point = WdaGo.ElementLocation{X: *req.X, Y: *req.Y}
+	} else {
+		ins.mu.Lock()
+		tree := ins.tree
+		ins.mu.Unlock()
+		var node *WdaGo.SourceNode
+		if tree != nil {
+			node = tree.FindByPath(req.Path)
+		}
+		if node == nil {
+			writeError(w, fmt.Errorf("element %s not found", req.Path))
+			return
+		}
+		point = node.Rect.Center()
+	}
+
+	if err := ins.session.TapWithLocation(point); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJson(w, map[string]bool{"ok": true})
+}
+
+// checkSameOriginJson 要求Content-Type为application/json，有Origin时必须与请求的Host一致
+// 跨站的表单请求无法设置json类型，设置了json类型的跨站请求需要CORS预检，查看器不会通过预检
+func checkSameOriginJson(r *http.Request) error {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || mediaType != "application/json" {
+		return fmt.Errorf("content type must be application/json")
+	}
+	if origin := r.Header.Get("Origin"); origin != "" {
+		parsed, err := url.Parse(origin)
+		if err != nil || parsed.Host != r.Host {
+			return fmt.Errorf("cross origin request from %s is not allowed", origin)
+		}
+	}
+	return nil
+}
+
+func writeJson(w http.ResponseWriter, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(value)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusInternalServerError)
+	json.NewEncoder(w).Encode(map[string]string{"error": strings.TrimSpace(err.Error())})
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	WdaGo "github.com/Ning9527fff/WdaGo"
+)
+
+func TestInspectorTapRejectsCrossSiteRequests(t *testing.T) {
+	var taps atomic.Int32
+	wda := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/wda/tap") {
+			taps.Add(1)
+		}
+		io.WriteString(w, `{"value":null,"sessionId":"s1"}`)
+	}))
+	defer wda.Close()
+
+	session := WdaGo.GetWdaSession(wda.URL)
+	session.AttachSession("s1")
+	ins := &inspector{session: session}
+	server := httptest.NewServer(ins.handler())
+	defer server.Close()
+
+	tests := []struct {
+		name        string
+		contentType string
+		origin      string
+		wantStatus  int
+	}{
+		{"same origin json", "application/json", server.URL, http.StatusOK},
+		{"json without origin", "application/json; charset=utf-8", "", http.StatusOK},
+		{"form post", "application/x-www-form-urlencoded", "", http.StatusForbidden},
+		{"text plain", "text/plain", server.URL, http.StatusForbidden},
+		{"cross origin json", "application/json", "http://evil.example", http.StatusForbidden},
+	}
+	want := int32(0)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodPost, server.URL+"/api/tap", strings.NewReader(`{"x":1,"y":2}`))
+			req.Header.Set("Content-Type", tt.contentType)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if tt.wantStatus == http.StatusOK {
+				want++
+			}
+			if taps.Load() != want {
+				t.Errorf("wda received %d taps, want %d", taps.Load(), want)
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+// wdago 命令行工具，封装WdaGo库的常用设备操作，输出为json便于脚本使用
+//
+//	wdago [--url http://127.0.0.1:8100] [--session id] <command> [args...]
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	WdaGo "github.com/Ning9527fff/WdaGo"
+)
+
+const (
+	DefaultWdaUrl = "http://127.0.0.1:8100"
+	EnvWdaUrl     = "WDA_URL"
+	EnvWdaSession = "WDA_SESSION"
+)
+
+// rawOutput 命令的返回值为rawOutput时直接输出，不做json格式化
+type rawOutput string
+
+type command struct {
+	usage string
+	run   func(c *cli, args []string) (interface{}, error)
+}
+
+// cli 命令执行上下文，session在第一次使用时创建
+type cli struct {
+	url       string
+	sessionId string
+	debug     bool
+	session   *WdaGo.WdaSession
+	out       io.Writer
+}
+
+var commands map[string]command
+
+func main() {
+	c := &cli{out: os.Stdout}
+
+	flags := flag.NewFlagSet("wdago", flag.ContinueOnError)
+	flags.StringVar(&c.url, "url", envOrDefault(EnvWdaUrl, DefaultWdaUrl), "wda url")
+	flags.StringVar(&c.sessionId, "session", os.Getenv(EnvWdaSession), "existing wda session id, use the active session or create a new one when empty")
+	flags.BoolVar(&c.debug, "debug", false, "print debug log")
+	flags.Usage = func() { printUsage(flags) }
+
+	if err := flags.Parse(os.Args[1:]); err != nil {
+		os.Exit(2)
+	}
+	if flags.NArg() == 0 {
+		printUsage(flags)
+		os.Exit(2)
+	}
+	if c.debug {
+		WdaGo.SetDebugLog()
+	}
+
+	if err := c.execute(flags.Args()); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", strings.TrimSpace(err.Error()))
+		os.Exit(1)
+	}
+}
+
+// execute 执行一条命令并输出结果
+func (c *cli) execute(args []string) error {
+	cmd, ok := commands[args[0]]
+	if !ok {
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+
+	result, err := cmd.run(c, args[1:])
+	if err != nil {
+		return err
+	}
+	return c.print(result)
+}
+
+func (c *cli) print(result interface{}) error {
+	switch v := result.(type) {
+	case rawOutput:
+		if v == "" {
+			return nil
+		}
+		_, err := fmt.Fprintln(c.out, string(v))
+		return err
+	case nil:
+		result = map[string]bool{"ok": true}
+	}
+
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(c.out, string(data))
+	return err
+}
+
+// getSession 获取wda session，指定了--session时直接使用，否则使用wda当前活动的session，
+// 都没有时才创建新的session，并在stderr输出sessionId以便之后通过--session复用
+func (c *cli) getSession() (*WdaGo.WdaSession, error) {
+	if c.session != nil && c.session.SessionId() != "" {
+		return c.session, nil
+	}
+
+	session := c.client()
+	if c.sessionId == "" {
+		sessionId, err := c.activeSessionId()
+		if err != nil {
+			return nil, err
+		}
+		c.sessionId = sessionId
+	}
+
+	if c.sessionId != "" {
+		session.AttachSession(c.sessionId)
+	} else {
+		if err := session.GetSession(""); err != nil {
+			return nil, err
+		}
+		c.sessionId = session.SessionId()
+		fmt.Fprintf(os.Stderr, "created wda session %v, reuse it with --session %v\n", c.sessionId, c.sessionId)
+	}
+	return session, nil
+}
+
+// activeSessionId 从wda的 /status 获取当前活动的sessionId，没有时返回空
+func (c *cli) activeSessionId() (string, error) {
+	status, err := c.client().GetStatus()
+	if err != nil {
+		return "", err
+	}
+	return status.SessionId, nil
+}
+
+// client 获取不需要session的wda客户端
+func (c *cli) client() *WdaGo.WdaSession {
+	if c.session == nil {
+		c.session = WdaGo.GetWdaSession(strings.TrimRight(c.url, "/"))
+	}
+	return c.session
+}
+
+func printUsage(flags *flag.FlagSet) {
+	fmt.Fprintln(os.Stderr, "usage: wdago [flags] <command> [args...]")
+	fmt.Fprintln(os.Stderr, "\nflags:")
+	flags.PrintDefaults()
+	fmt.Fprintln(os.Stderr, "\ncommands:")
+
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %s\n", commands[name].usage)
+	}
+}
+
+func envOrDefault(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestGetSession(t *testing.T) {
+	tests := []struct {
+		name        string
+		flagSession string
+		active      string
+		wantSession string
+		wantCreated bool
+	}{
+		{"flag session is used", "from-flag", "active", "from-flag", false},
+		{"active session is reused", "", "active", "active", false},
+		{"new session when none active", "", "", "created", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var created atomic.Int32
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /status", func(w http.ResponseWriter, r *http.Request) {
+				io.WriteString(w, `{"value":{"ready":true},"sessionId":"`+tt.active+`"}`)
+			})
+			mux.HandleFunc("POST /session", func(w http.ResponseWriter, r *http.Request) {
+				created.Add(1)
+				io.WriteString(w, `{"value":{"sessionId":"created"},"sessionId":"created"}`)
+			})
+			server := httptest.NewServer(mux)
+			defer server.Close()
+
+			c := &cli{url: server.URL, sessionId: tt.flagSession, out: io.Discard}
+			session, err := c.getSession()
+			if err != nil {
+				t.Fatalf("getSession() error = %v", err)
+			}
+			if session.SessionId() != tt.wantSession {
+				t.Errorf("SessionId() = %q, want %q", session.SessionId(), tt.wantSession)
+			}
+			if (created.Load() > 0) != tt.wantCreated {
+				t.Errorf("created %d sessions, want created %v", created.Load(), tt.wantCreated)
+			}
+
+			// 同一次执行中再次获取不会重复创建
+			if _, err = c.getSession(); err != nil || created.Load() > 1 {
+				t.Errorf("second getSession() error = %v, created %d", err, created.Load())
+			}
+		})
+	}
+}
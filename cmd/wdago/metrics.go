@@ -0,0 +1,37 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/Ning9527fff/WdaGo/promwda"
+)
+
+func cmdMetrics(c *cli, args []string) (interface{}, error) {
+	flags := flag.NewFlagSet("metrics", flag.ContinueOnError)
+	addr := flags.String("addr", "127.0.0.1:9200", "listen address of the prometheus metrics endpoint")
+	name := flags.String("device", "", "device label of the metrics, default is wda address")
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	session, err := c.getSession()
+	if err != nil {
+		return nil, err
+	}
+
+	collector := promwda.NewCollector()
+	collector.AddDevice(*name, session)
+
+	listener, err := net.Listen("tcp", *addr)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(c.out, "metrics are served at http://%s/metrics\n", listener.Addr())
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", collector.Handler())
+	return nil, http.Serve(listener, mux)
+}
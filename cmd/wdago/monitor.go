@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+
+	WdaGo "github.com/Ning9527fff/WdaGo"
+)
+
+// healthEventOutput 输出的健康事件，附带错误信息
+type healthEventOutput struct {
+	WdaGo.HealthEvent
+	Error string `json:"error,omitempty"`
+}
+
+func cmdMonitor(c *cli, args []string) (interface{}, error) {
+	flags := flag.NewFlagSet("monitor", flag.ContinueOnError)
+	interval := flags.Duration("interval", WdaGo.DefaultHealthInterval, "health check interval")
+	threshold := flags.Int("threshold", WdaGo.DefaultFailureThreshold, "consecutive failures before recovering")
+	checkSession := flags.Bool("session", true, "check and recreate the wda session")
+	hook := flags.String("hook", "", "shell command to restart wda, e.g. xcodebuild or iproxy")
+	timeout := flags.Duration("timeout", WdaGo.DefaultRecoveryTimeout, "time to wait for wda ready after hook")
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	session := c.client()
+	if *checkSession {
+		if _, err := c.getSession(); err != nil {
+			return nil, err
+		}
+	}
+
+	encoder := json.NewEncoder(c.out)
+	option := WdaGo.HealthMonitorOption{
+		Interval:         *interval,
+		FailureThreshold: *threshold,
+		CheckSession:     *checkSession,
+		RecoveryTimeout:  *timeout,
+		OnEvent: func(event WdaGo.HealthEvent) {
+			output := healthEventOutput{HealthEvent: event}
+			if event.Err != nil {
+				output.Error = event.Err.Error()
+			}
+			encoder.Encode(output)
+		},
+	}
+	if *hook != "" {
+		option.Hooks = []WdaGo.RecoveryHook{shellHook(*hook)}
+	}
+
+	monitor := session.StartHealthMonitor(option)
+	defer monitor.Stop()
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+	<-interrupt
+
+	return rawOutput(""), nil
+}
+
+// shellHook 通过sh执行恢复命令，环境变量中传入故障状态和尝试次数
+func shellHook(command string) WdaGo.RecoveryHook {
+	return func(ctx context.Context, event WdaGo.HealthEvent) error {
+		cmd := exec.CommandContext(ctx, "sh", "-c", command)
+		cmd.Env = append(os.Environ(),
+			"WDA_HEALTH_STATE="+event.State.String(),
+			"WDA_RECOVERY_ATTEMPT="+strconv.Itoa(event.Attempt),
+			"WDA_SESSION_ID="+event.SessionId,
+		)
+		cmd.Stdout = os.Stderr
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("hook %q failed: %v", command, err)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	WdaGo "github.com/Ning9527fff/WdaGo"
+	"github.com/Ning9527fff/WdaGo/pageobject"
+)
+
+// cmdPageObject 根据当前页面或者保存的页面树生成page object代码
+func cmdPageObject(c *cli, args []string) (interface{}, error) {
+	flags := flag.NewFlagSet("pageobject", flag.ContinueOnError)
+	configPath := flags.String("config", "", "yaml config for naming and filtering elements")
+	sourcePath := flags.String("source", "", "page source xml file, use current page when empty")
+	pkg := flags.String("package", "", "package name of generated code")
+	name := flags.String("name", "", "page object struct name")
+	output := flags.String("o", "", "output file, print to stdout when empty")
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	config := &pageobject.Config{}
+	if *configPath != "" {
+		var err error
+		if config, err = pageobject.LoadConfig(*configPath); err != nil {
+			return nil, err
+		}
+	}
+	if *pkg != "" {
+		config.Package = *pkg
+	}
+	if *name != "" {
+		config.Name = *name
+	}
+
+	var source string
+	if *sourcePath != "" {
+		data, err := os.ReadFile(*sourcePath)
+		if err != nil {
+			return nil, err
+		}
+		source = string(data)
+	} else {
+		session, err := c.getSession()
+		if err != nil {
+			return nil, err
+		}
+		if source, err = session.GetSource(WdaGo.SourceFormatXml); err != nil {
+			return nil, err
+		}
+	}
+
+	code, err := pageobject.GenerateFromSource(source, *config)
+	if err != nil {
+		return nil, err
+	}
+	if *output == "" {
+		return rawOutput(code), nil
+	}
+	if err = os.WriteFile(*output, code, 0644); err != nil {
+		return nil, err
+	}
+	return rawOutput(fmt.Sprintf("page object written to %s", *output)), nil
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/Ning9527fff/WdaGo/report"
+	"github.com/Ning9527fff/WdaGo/scenario"
+)
+
+func cmdRun(c *cli, args []string) (interface{}, error) {
+	flags := flag.NewFlagSet("run", flag.ContinueOnError)
+	var vars stringList
+	flags.Var(&vars, "var", "scenario variable KEY=VALUE, can be repeated")
+	output := flags.String("output", "", "directory for screenshots")
+	junit := flags.String("junit", "", "write JUnit XML report to file")
+	html := flags.String("html", "", "write HTML report to file")
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+	if flags.NArg() != 1 {
+		return nil, fmt.Errorf("usage: run [--var KEY=VALUE] [--output dir] [--junit file] [--html file] scenario.yaml")
+	}
+
+	sc, err := scenario.Load(flags.Arg(0))
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := c.getSession()
+	if err != nil {
+		return nil, err
+	}
+
+	runner := scenario.NewRunner(session)
+	runner.OutputDir = *output
+	runner.Variables = make(map[string]string, len(vars))
+	for _, v := range vars {
+		key, value, _ := strings.Cut(v, "=")
+		runner.Variables[key] = value
+	}
+
+	var reporter *report.Reporter
+	if *junit != "" || *html != "" {
+		reporter = report.NewReporter(sc.Name)
+		reporter.Attach(session)
+		reporter.StartCase(sc.Name)
+		runner.OnStepStart = func(index int, name string) {
+			reporter.SetGroup(fmt.Sprintf("%d %s", index, name))
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	result := runner.Run(ctx, sc)
+	if reporter != nil {
+		if err = writeReports(reporter, result, *junit, *html); err != nil {
+			return nil, err
+		}
+	}
+	if err = c.print(result); err != nil {
+		return nil, err
+	}
+	if !result.Passed {
+		return nil, fmt.Errorf("scenario %s failed: %s", result.Name, result.Error)
+	}
+	return rawOutput(""), nil
+}
+
+// writeReports 结束报告用例并输出JUnit和HTML报告
+func writeReports(reporter *report.Reporter, result *scenario.Result, junit, html string) error {
+	var err error
+	if !result.Passed {
+		err = fmt.Errorf("%s", result.Error)
+	}
+	reporter.EndCase(err)
+
+	if junit != "" {
+		if err = reporter.WriteJUnit(junit); err != nil {
+			return err
+		}
+	}
+	if html != "" {
+		if err = reporter.WriteHTML(html); err != nil {
+			return err
+		}
+	}
+	return nil
+}
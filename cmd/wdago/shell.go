@@ -0,0 +1,565 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	WdaGo "github.com/Ning9527fff/WdaGo"
+	"github.com/Ning9527fff/WdaGo/recorder"
+	"golang.org/x/term"
+)
+
+const (
+	shellPrompt     = "wda> "
+	historyFileName = ".wdago_history"
+	maxHistory      = 1000
+)
+
+// shellStep shell中执行成功的一条命令，find命令会记录找到的元素
+type shellStep struct {
+	args     []string
+	elements []string
+}
+
+// shell 交互式命令行，维护一个WdaSession以及执行过的命令
+type shell struct {
+	cli      *cli
+	session  *WdaGo.WdaSession
+	steps    []shellStep
+	elements []string
+	history  *fileHistory
+	recorder *recorder.Recorder
+	out      io.Writer
+}
+
+var shellCommands = map[string]string{
+	"find":       "find <strategy> <value>        find elements, ids can be completed with tab",
+	"click":      "click <elementId>              click element",
+	"type":       "type <elementId> <text>        type text into element",
+	"keys":       "keys <text>                    type text into focused element",
+	"clear":      "clear <elementId>              clear element text",
+	"tap":        "tap x y                        tap at point",
+	"swipe":      "swipe x1 y1 x2 y2 [duration]   swipe between points",
+	"screenshot": "screenshot [file]              save screenshot",
+	"source":     "source [xml|json|description]  print page source",
+	"inspect":    "inspect [addr]                 start web inspector in background",
+	"record":     "record start|stop|status|save <file.go|file.yaml> [TestName]",
+	"history":    "history                        print executed commands",
+	"export":     "export <file.go> [TestName]    export executed commands as go test",
+	"help":       "help                           print this help",
+	"exit":       "exit                           quit shell",
+}
+
+func cmdShell(c *cli, args []string) (interface{}, error) {
+	session, err := c.getSession()
+	if err != nil {
+		return nil, err
+	}
+
+	sh := &shell{cli: c, session: session, out: c.out}
+	sh.history = loadHistory(historyPath())
+
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return nil, sh.runScanner(os.Stdin)
+	}
+
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return nil, err
+	}
+	defer term.Restore(fd, state)
+
+	terminal := term.NewTerminal(struct {
+		io.Reader
+		io.Writer
+	}{os.Stdin, os.Stdout}, shellPrompt)
+	terminal.History = sh.history
+	terminal.AutoCompleteCallback = func(line string, pos int, key rune) (string, int, bool) {
+		if key != '\t' {
+			return "", 0, false
+		}
+		return sh.complete(terminal, line, pos)
+	}
+	sh.out = terminal
+	c.out = terminal
+
+	fmt.Fprintf(sh.out, "connected to %s, session %s, type help for commands\n", c.url, session.SessionId())
+	for {
+		line, err := terminal.ReadLine()
+		if err == io.EOF {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if sh.handle(line) {
+			return nil, nil
+		}
+	}
+}
+
+func (sh *shell) runScanner(reader io.Reader) error {
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		if sh.handle(scanner.Text()) {
+			return nil
+		}
+	}
+	return scanner.Err()
+}
+
+// handle 执行一行输入，返回true表示退出shell
+func (sh *shell) handle(line string) bool {
+	args, err := splitArgs(line)
+	if err != nil {
+		fmt.Fprintf(sh.out, "error: %v\n", err)
+		return false
+	}
+	if len(args) == 0 {
+		return false
+	}
+
+	switch args[0] {
+	case "exit", "quit":
+		return true
+	case "help":
+		sh.printHelp()
+		return false
+	case "history":
+		for i, step := range sh.steps {
+			fmt.Fprintf(sh.out, "%3d  %s\n", i+1, joinArgs(step.args))
+		}
+		return false
+	case "export":
+		if err = sh.export(args[1:]); err != nil {
+			fmt.Fprintf(sh.out, "error: %v\n", err)
+		}
+		return false
+	case "record":
+		if err = sh.record(args[1:]); err != nil {
+			fmt.Fprintf(sh.out, "error: %v\n", err)
+		}
+		return false
+	}
+
+	step, err := sh.run(args)
+	if err != nil {
+		fmt.Fprintf(sh.out, "error: %v\n", strings.TrimSpace(err.Error()))
+		return false
+	}
+	sh.steps = append(sh.steps, step)
+	return false
+}
+
+// run 执行shell命令，未在shell中定义的命令交给cli执行
+func (sh *shell) run(args []string) (shellStep, error) {
+	step := shellStep{args: args}
+	session := sh.session
+
+	switch args[0] {
+	case "find":
+		if len(args) < 3 {
+			return step, fmt.Errorf("usage: %s", shellCommands["find"])
+		}
+		elements, err := session.FindElements(resolveStrategy(args[1]), strings.Join(args[2:], " "))
+		if err != nil {
+			return step, err
+		}
+		step.args = []string{args[0], args[1], strings.Join(args[2:], " ")}
+		step.elements = elements
+		sh.rememberElements(elements)
+		if len(elements) == 0 {
+			fmt.Fprintln(sh.out, "no element found")
+		}
+		for i, element := range elements {
+			fmt.Fprintf(sh.out, "[%d] %s\n", i, element)
+		}
+		return step, nil
+	case "click":
+		if len(args) != 2 {
+			return step, fmt.Errorf("usage: %s", shellCommands["click"])
+		}
+		return step, session.ClickElement(args[1])
+	case "type":
+		if len(args) < 3 {
+			return step, fmt.Errorf("usage: %s", shellCommands["type"])
+		}
+		step.args = []string{args[0], args[1], strings.Join(args[2:], " ")}
+		return step, session.TypingText(args[1], step.args[2])
+	case "keys":
+		if len(args) < 2 {
+			return step, fmt.Errorf("usage: %s", shellCommands["keys"])
+		}
+		step.args = []string{args[0], strings.Join(args[1:], " ")}
+		return step, session.SendKeys(step.args[1])
+	case "clear":
+		if len(args) != 2 {
+			return step, fmt.Errorf("usage: %s", shellCommands["clear"])
+		}
+		return step, session.ClearText(args[1])
+	case "swipe":
+		if len(args) == 5 {
+			args = append(args, "0")
+		}
+		points, err := parseFloats(args[1:], 5)
+		if err != nil {
+			return step, fmt.Errorf("usage: %s", shellCommands["swipe"])
+		}
+		step.args = args
+		return step, session.SwipeWithLocation(points[0], points[1], points[2], points[3], points[4])
+	case "screenshot":
+		file := "screenshot.png"
+		if len(args) > 1 {
+			file = args[1]
+		}
+		step.args = []string{args[0], file}
+		data, err := session.ScreenShotData()
+		if err != nil {
+			return step, err
+		}
+		if err = os.WriteFile(file, data, 0644); err != nil {
+			return step, err
+		}
+		fmt.Fprintf(sh.out, "saved to %s\n", file)
+		return step, nil
+	case "source":
+		format := WdaGo.SourceFormatXml
+		if len(args) > 1 {
+			format = args[1]
+		}
+		source, err := session.GetSource(format)
+		if err != nil {
+			return step, err
+		}
+		fmt.Fprintln(sh.out, source)
+		return step, nil
+	}
+
+	if args[0] == "inspect" {
+		return step, sh.inspect(args[1:])
+	}
+
+	// 录制时点击前先获取页面树，用于将坐标点击转换为元素定位
+	if args[0] == "tap" && sh.recorder != nil && sh.recorder.Recording() {
+		if err := sh.recorder.Snapshot(); err != nil {
+			fmt.Fprintf(sh.out, "snapshot failed, tap will be recorded by point: %v\n", err)
+		}
+	}
+
+	if _, ok := commands[args[0]]; !ok || args[0] == "shell" {
+		return step, fmt.Errorf("unknown command %q, type help for commands", args[0])
+	}
+	return step, sh.cli.execute(args)
+}
+
+// complete tab补全：第一个词补全命令，find的第二个词补全查找策略，元素相关命令补全元素id
+func (sh *shell) complete(terminal *term.Terminal, line string, pos int) (string, int, bool) {
+	prefixLine := line[:pos]
+	words := strings.Fields(prefixLine)
+	current := ""
+	if len(words) > 0 && !strings.HasSuffix(prefixLine, " ") {
+		current = words[len(words)-1]
+		words = words[:len(words)-1]
+	}
+
+	var candidates []string
+	switch {
+	case len(words) == 0:
+		for name := range shellCommands {
+			candidates = append(candidates, name)
+		}
+		for name := range commands {
+			if name != "shell" {
+				candidates = append(candidates, name)
+			}
+		}
+	case words[0] == "find" && len(words) == 1:
+		for alias := range strategyAliases {
+			candidates = append(candidates, alias)
+		}
+		for _, strategy := range WdaGo.Strategies {
+			candidates = append(candidates, strings.ReplaceAll(strategy, " ", "-"))
+		}
+	case (words[0] == "click" || words[0] == "type" || words[0] == "clear") && len(words) == 1:
+		candidates = sh.elements
+	}
+
+	var matched []string
+	seen := make(map[string]bool)
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, current) && !seen[candidate] {
+			matched = append(matched, candidate)
+			seen[candidate] = true
+		}
+	}
+	if len(matched) == 0 {
+		return "", 0, false
+	}
+	sort.Strings(matched)
+
+	completion := matched[0]
+	if len(matched) > 1 {
+		completion = commonPrefix(matched)
+		fmt.Fprintln(terminal, strings.Join(matched, "  "))
+	} else {
+		completion += " "
+	}
+
+	newPrefix := prefixLine[:len(prefixLine)-len(current)] + completion
+	return newPrefix + line[pos:], len(newPrefix), true
+}
+
+func (sh *shell) rememberElements(elements []string) {
+	for _, element := range elements {
+		found := false
+		for _, known := range sh.elements {
+			if known == element {
+				found = true
+				break
+			}
+		}
+		if !found {
+			sh.elements = append(sh.elements, element)
+		}
+	}
+}
+
+func (sh *shell) export(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: %s", shellCommands["export"])
+	}
+
+	testName := "TestRecorded"
+	if len(args) > 1 {
+		testName = args[1]
+	}
+
+	code, err := generateGoTest(sh.steps, sh.cli.url, testName)
+	if err != nil {
+		return err
+	}
+	if err = os.WriteFile(args[0], code, 0644); err != nil {
+		return err
+	}
+	fmt.Fprintf(sh.out, "exported %d steps to %s\n", len(sh.steps), args[0])
+	return nil
+}
+
+// inspect 在后台启动网页查看器，与shell共用同一个session
+func (sh *shell) inspect(args []string) error {
+	addr := "127.0.0.1:8200"
+	if len(args) > 0 {
+		addr = args[0]
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	go func() {
+		if err := serveInspector(sh.session, listener); err != nil {
+			fmt.Fprintf(sh.out, "inspector stopped: %v\n", err)
+		}
+	}()
+	fmt.Fprintf(sh.out, "inspector is running at http://%s\n", listener.Addr())
+	return nil
+}
+
+// record 录制shell和查看器中执行的操作，保存为go测试代码或者yaml场景
+func (sh *shell) record(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: %s", shellCommands["record"])
+	}
+	if sh.recorder == nil {
+		sh.recorder = recorder.New(sh.session)
+	}
+
+	switch args[0] {
+	case "start":
+		sh.recorder.Start()
+		fmt.Fprintln(sh.out, "recording started")
+	case "stop":
+		sh.recorder.Stop()
+		fmt.Fprintf(sh.out, "recording stopped, %d actions recorded\n", len(sh.recorder.Actions()))
+	case "status":
+		for i, action := range sh.recorder.Actions() {
+			target := action.Element
+			if action.Locator != nil {
+				target = action.Locator.String()
+			} else if action.Point != nil {
+				target = fmt.Sprintf("%v,%v", action.Point.X, action.Point.Y)
+			}
+			fmt.Fprintf(sh.out, "%3d  %s %s\n", i+1, action.Action, target)
+		}
+	case "save":
+		if len(args) < 2 {
+			return fmt.Errorf("usage: %s", shellCommands["record"])
+		}
+		name := "TestRecorded"
+		if len(args) > 2 {
+			name = args[2]
+		}
+
+		var data []byte
+		var err error
+		switch strings.ToLower(filepath.Ext(args[1])) {
+		case ".yaml", ".yml":
+			data, err = sh.recorder.YAML(name)
+		default:
+			data, err = sh.recorder.GoTest(name, sh.cli.url)
+		}
+		if err != nil {
+			return err
+		}
+		if err = os.WriteFile(args[1], data, 0644); err != nil {
+			return err
+		}
+		fmt.Fprintf(sh.out, "saved %d actions to %s\n", len(sh.recorder.Actions()), args[1])
+	default:
+		return fmt.Errorf("usage: %s", shellCommands["record"])
+	}
+	return nil
+}
+
+func (sh *shell) printHelp() {
+	names := make([]string, 0, len(shellCommands))
+	for name := range shellCommands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(sh.out, "  %s\n", shellCommands[name])
+	}
+	fmt.Fprintln(sh.out, "  other wdago commands (status, info, apps, alert, home ...) can also be used")
+}
+
+// fileHistory 命令历史，同时追加写入文件，下次启动时读取
+type fileHistory struct {
+	path    string
+	entries []string
+}
+
+func historyPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, historyFileName)
+}
+
+func loadHistory(path string) *fileHistory {
+	history := &fileHistory{path: path}
+	if path == "" {
+		return history
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return history
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if line != "" {
+			history.entries = append(history.entries, line)
+		}
+	}
+	if len(history.entries) > maxHistory {
+		history.entries = history.entries[len(history.entries)-maxHistory:]
+	}
+	return history
+}
+
+func (history *fileHistory) Add(entry string) {
+	if entry == "" {
+		return
+	}
+	history.entries = append(history.entries, entry)
+	if len(history.entries) > maxHistory {
+		history.entries = history.entries[1:]
+	}
+
+	if history.path == "" {
+		return
+	}
+	file, err := os.OpenFile(history.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+	fmt.Fprintln(file, entry)
+}
+
+func (history *fileHistory) Len() int {
+	return len(history.entries)
+}
+
+func (history *fileHistory) At(idx int) string {
+	return history.entries[len(history.entries)-1-idx]
+}
+
+// splitArgs 按空格拆分命令行，支持单引号和双引号
+func splitArgs(line string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	var quote rune
+	inArg := false
+
+	for _, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			inArg = true
+		case r == ' ' || r == '\t':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote")
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
+
+func joinArgs(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		if arg == "" || strings.ContainsAny(arg, " \t\"'") {
+			quoted[i] = strconv.Quote(arg)
+		} else {
+			quoted[i] = arg
+		}
+	}
+	return strings.Join(quoted, " ")
+}
+
+func commonPrefix(values []string) string {
+	prefix := values[0]
+	for _, value := range values[1:] {
+		for !strings.HasPrefix(value, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+
+	"golang.org/x/term"
+)
+
+func TestSplitArgs(t *testing.T) {
+	tests := []struct {
+		line string
+		want []string
+	}{
+		{"click E1", []string{"click", "E1"}},
+		{"  tap\t1   2 ", []string{"tap", "1", "2"}},
+		{`find aid "Sign in"`, []string{"find", "aid", "Sign in"}},
+		{`type E1 'say "hi"'`, []string{"type", "E1", `say "hi"`}},
+		{`keys ""`, []string{"keys", ""}},
+		{`find predicate name=="a b"c`, []string{"find", "predicate", "name==a bc"}},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		got, err := splitArgs(tt.line)
+		if err != nil {
+			t.Errorf("splitArgs(%q) error = %v", tt.line, err)
+			continue
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("splitArgs(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+
+	if _, err := splitArgs(`find aid "Sign in`); err == nil {
+		t.Error("splitArgs() with unterminated quote want error")
+	}
+}
+
+func TestJoinArgs(t *testing.T) {
+	// history中保存joinArgs的结果，重新拆分后参数不变
+	args := []string{"type", "E1", "hello world", "", "a'b"}
+	if got := joinArgs(args); got != `type E1 "hello world" "" "a'b"` {
+		t.Errorf("joinArgs() = %s", got)
+	}
+	got, err := splitArgs(joinArgs(args))
+	if err != nil || !slices.Equal(got, args) {
+		t.Errorf("splitArgs(joinArgs()) = %q, %v, want %q", got, err, args)
+	}
+}
+
+func TestComplete(t *testing.T) {
+	sh := &shell{elements: []string{"E1", "E12", "F3"}}
+	tests := []struct {
+		name    string
+		line    string
+		pos     int
+		want    string
+		wantPos int
+		ok      bool
+		listed  string
+	}{
+		{"shell command", "histo", 5, "history ", 8, true, ""},
+		{"cli command", "scre", 4, "screenshot ", 11, true, ""},
+		{"common prefix", "ex", 2, "ex", 2, true, "exit  export"},
+		{"shell is not nested", "she", 3, "", 0, false, ""},
+		{"strategy", "find acc", 8, "find accessibility-id ", 22, true, ""},
+		{"strategy with space", "find xp", 7, "find xpath ", 11, true, ""},
+		{"element id", "click F", 7, "click F3 ", 9, true, ""},
+		{"element prefix", "type E", 6, "type E1", 7, true, "E1  E12"},
+		{"cursor in the middle", "clear E text", 7, "clear E1 text", 8, true, "E1  E12"},
+		{"no completion for text", "keys E", 6, "", 0, false, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output bytes.Buffer
+			terminal := term.NewTerminal(struct {
+				io.Reader
+				io.Writer
+			}{strings.NewReader(""), &output}, "")
+
+			line, pos, ok := sh.complete(terminal, tt.line, tt.pos)
+			if line != tt.want || pos != tt.wantPos || ok != tt.ok {
+				t.Errorf("complete(%q, %d) = %q, %d, %v, want %q, %d, %v", tt.line, tt.pos, line, pos, ok, tt.want, tt.wantPos, tt.ok)
+			}
+			// 多个候选时列出全部候选
+			if listed := strings.TrimSpace(output.String()); listed != tt.listed {
+				t.Errorf("listed %q, want %q", listed, tt.listed)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	WdaGo "github.com/Ning9527fff/WdaGo"
+)
+
+func cmdTelemetry(c *cli, args []string) (interface{}, error) {
+	flags := flag.NewFlagSet("telemetry", flag.ContinueOnError)
+	interval := flags.Duration("interval", WdaGo.DefaultTelemetryInterval, "sampling interval")
+	duration := flags.Duration("duration", 0, "stop after duration, default runs until interrupted")
+	output := flags.String("o", "", "save samples to file, .csv or .json")
+	thermal := flags.String("thermal", "serious", "log when thermal state reaches nominal|fair|serious|critical")
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	thermalState, err := WdaGo.ParseThermalState(*thermal)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := c.getSession()
+	if err != nil {
+		return nil, err
+	}
+
+	encoder := json.NewEncoder(c.out)
+	sampler := session.StartTelemetry(WdaGo.TelemetryOption{
+		Interval: *interval,
+		Thresholds: []WdaGo.TelemetryThreshold{{
+			Name:      "thermal",
+			Condition: WdaGo.ThermalAtLeast(thermalState),
+			Requires:  []string{WdaGo.TelemetryDevice},
+			OnTrigger: func(sample WdaGo.TelemetrySample) {
+				fmt.Fprintf(os.Stderr, "thermal state is %v\n", sample.ThermalState)
+			},
+			OnRecover: func(sample WdaGo.TelemetrySample) {
+				fmt.Fprintf(os.Stderr, "thermal state recovered to %v\n", sample.ThermalState)
+			},
+		}},
+		OnSample: func(sample WdaGo.TelemetrySample) {
+			encoder.Encode(sample)
+		},
+	})
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+	var timeout <-chan time.Time
+	if *duration > 0 {
+		timeout = time.After(*duration)
+	}
+	select {
+	case <-interrupt:
+	case <-timeout:
+	}
+	sampler.Stop()
+
+	if *output == "" {
+		return rawOutput(""), nil
+	}
+	file, err := os.Create(*output)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	if strings.EqualFold(filepath.Ext(*output), ".json") {
+		err = sampler.WriteJSON(file)
+	} else {
+		err = sampler.WriteCSV(file)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return rawOutput("saved " + *output), nil
+}
@@ -1,10 +1,25 @@
 package WdaGo
 
+import "sync"
+
 type WdaSession struct {
-	url       string
+	*sessionState
+	// operation 正在执行的WdaSession或者Element方法，只在方法内使用的副本中不为空，见 begin
+	operation *Operation
+}
+
+// sessionState session的状态，方法内使用的副本与原session共享
+type sessionState struct {
+	url string
+	// mu 保护sessionId和capabilities，健康检查恢复时会在其他goroutine中替换
+	mu        sync.RWMutex
 	sessionId string
 	headers   map[string]string
 	client    *HTTPClient
+	recorder  *MjpegRecorder
+	// capabilities 创建session时使用的参数，恢复session时使用
+	capabilities *Capabilities
+	events       *EventBus
 }
 
 type PhoneStatus struct {
@@ -16,6 +31,8 @@ type PhoneStatus struct {
 	SdkVersion   string
 	State        string
 	IsReady      bool
+	// SessionId wda当前活动的session，没有时为空
+	SessionId string
 }
 
 const (
@@ -28,7 +45,7 @@ const (
 )
 
 type Capabilities struct {
-	BundleId string `json:"bundleId"`
+	BundleId string `json:"bundleId,omitempty"`
 }
 type SessionRequest struct {
 	Capabilities Capabilities `json:"capabilities"`
@@ -43,32 +60,47 @@ type ElementSearchRequest struct {
 	Value string `json:"value"`
 }
 
+// TypingRequest wda要求value为字符数组，Frequency为每分钟输入的字符数，0表示使用默认值
 type TypingRequest struct {
-	Value []byte `json:"value"`
+	Value     []string `json:"value"`
+	Frequency int      `json:"frequency,omitempty"`
 }
 
-type DeviceInfo struct {
-	TimeZone           string `json:"timeZone"`
-	CurrentLocale      string `json:"currentLocale"`
-	Model              string `json:"model"`
-	Uuid               string `json:"uuid"`
-	ThermalState       string `json:"thermalState"`
-	UserInterfaceIdiom int64  `json:"userInterfaceIdiom"`
-	UserInterfaceStyle string `json:"userInterfaceStyle"`
-	Name               string `json:"name"`
-	IsSimulator        bool   `json:"isSimulator"`
+type KeyboardDismissRequest struct {
+	KeyNames []string `json:"keyNames,omitempty"`
 }
 
+type DeviceInfo struct {
+	TimeZone           string             `json:"timeZone"`
+	CurrentLocale      string             `json:"currentLocale"`
+	Model              string             `json:"model"`
+	Uuid               string             `json:"uuid"`
+	ThermalState       ThermalState       `json:"thermalState"`
+	UserInterfaceIdiom UserInterfaceIdiom `json:"userInterfaceIdiom"`
+	UserInterfaceStyle UserInterfaceStyle `json:"userInterfaceStyle"`
+	Name               string             `json:"name"`
+	IsSimulator        bool               `json:"isSimulator"`
+}
+
+// Location 设备位置，Accuracy为水平精度(米)，wda未返回时为0
 type Location struct {
-	Latitude            int64 `json:"latitude"`
-	AuthorizationStatus int64 `json:"authorizationStatus"`
-	Longitude           int64 `json:"longitude"`
-	Altitude            int64 `json:"altitude"`
+	Latitude            float64                     `json:"latitude"`
+	AuthorizationStatus LocationAuthorizationStatus `json:"authorizationStatus"`
+	Longitude           float64                     `json:"longitude"`
+	Altitude            float64                     `json:"altitude"`
+	Accuracy            float64                     `json:"accuracy"`
+}
+
+// SimulatedLocation 模拟定位请求
+type SimulatedLocation struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
 }
 
+// BatteryInfo 电池信息，Level为0-1的电量，无法获取时为-1
 type BatteryInfo struct {
-	Level int64 `json:"level"`
-	State int64 `json:"state"`
+	Level float64      `json:"level"`
+	State BatteryState `json:"state"`
 }
 
 type WindowSize struct {
@@ -114,6 +146,14 @@ type BundleIdRequest struct {
 	BundleId string `json:"bundleId"`
 }
 
+// AppLaunchOption 启动app参数，ShouldWaitForQuiescence为nil时使用wda默认值
+type AppLaunchOption struct {
+	BundleId                string            `json:"bundleId"`
+	Arguments               []string          `json:"arguments,omitempty"`
+	Environment             map[string]string `json:"environment,omitempty"`
+	ShouldWaitForQuiescence *bool             `json:"shouldWaitForQuiescence,omitempty"`
+}
+
 type SourceRequest struct {
 	Resource string `json:"resource"`
 }
@@ -124,15 +164,16 @@ type ElementLocation struct {
 }
 
 type HoldRequest struct {
-	elementLocation ElementLocation
-	Duration        float64 `json:"duration"`
+	ElementLocation
+	Duration float64 `json:"duration"`
 }
 
 type DragOption struct {
-	FromX float64 `json:"fromX"`
-	FromY float64 `json:"fromY"`
-	ToX   float64 `json:"toX"`
-	ToY   float64 `json:"toY"`
+	FromX    float64 `json:"fromX"`
+	FromY    float64 `json:"fromY"`
+	ToX      float64 `json:"toX"`
+	ToY      float64 `json:"toY"`
+	Duration float64 `json:"duration"`
 }
 
 type ButtonName struct {
@@ -157,3 +198,30 @@ type Scale struct {
 	ScaleX float64
 	ScaleY float64
 }
+
+type SettingsRequest struct {
+	Settings interface{} `json:"settings"`
+}
+
+// MjpegSettings mjpeg视频流相关设置，零值表示不修改
+type MjpegSettings struct {
+	Framerate         int     `json:"mjpegServerFramerate,omitempty"`
+	ScreenshotQuality int     `json:"mjpegServerScreenshotQuality,omitempty"`
+	ScalingFactor     float64 `json:"mjpegScalingFactor,omitempty"`
+}
+
+type OrientationRequest struct {
+	Orientation string `json:"orientation"`
+}
+
+// Rotation 设备旋转角度，单位为度
+type Rotation struct {
+	X int64 `json:"x"`
+	Y int64 `json:"y"`
+	Z int64 `json:"z"`
+}
+
+type PasteboardRequest struct {
+	Content     string `json:"content,omitempty"`
+	ContentType string `json:"contentType"`
+}
@@ -0,0 +1,344 @@
+package WdaGo
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/tidwall/gjson"
+)
+
+// Locator 元素定位方式，Using为查找策略(见Strategies)，Index为匹配到多个元素时使用第几个
+type Locator struct {
+	Using string `json:"using" yaml:"using"`
+	Value string `json:"value" yaml:"value"`
+	Index int    `json:"index,omitempty" yaml:"index,omitempty"`
+}
+
+// ElementRect 元素在屏幕上的位置和大小，单位为点
+type ElementRect struct {
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+func (locator Locator) String() string {
+	if locator.Index > 0 {
+		return fmt.Sprintf("%s=%q[%d]", locator.Using, locator.Value, locator.Index)
+	}
+	return fmt.Sprintf("%s=%q", locator.Using, locator.Value)
+}
+
+// Center 元素中心点
+func (rect ElementRect) Center() ElementLocation {
+	return ElementLocation{
+		X: rect.X + rect.Width/2,
+		Y: rect.Y + rect.Height/2,
+	}
+}
+
+// Contains 点是否在元素范围内
+func (rect ElementRect) Contains(x, y float64) bool {
+	return x >= rect.X && x < rect.X+rect.Width && y >= rect.Y && y < rect.Y+rect.Height
+}
+
+// ErrElementNotFound 按Locator没有找到元素，可以用 errors.Is(err, ErrElementNotFound) 判断
+var ErrElementNotFound = errors.New("Element not found")
+
+// FindElement 使用Locator查找元素，没有找到时返回错误
+func (session *WdaSession) FindElement(locator Locator) (string, error) {
+	session, end := session.begin("WdaSession.FindElement")
+	defer end()
+
+	elements, err := session.FindElements(locator.Using, locator.Value)
+	if err != nil {
+		return StringNull, err
+	}
+	if len(elements) <= locator.Index {
+		return StringNull, fmt.Errorf(" %w by %v ", ErrElementNotFound, locator)
+	}
+	return elements[locator.Index], nil
+}
+
+// WaitForElement 等待元素出现，返回元素id
+func (session *WdaSession) WaitForElement(locator Locator, timeout time.Duration) (string, error) {
+	session, end := session.begin("WdaSession.WaitForElement")
+	defer end()
+
+	var elementId string
+	err := WaitUntil(timeout, DefaultPollInterval, func() (bool, error) {
+		elements, err := session.FindElements(locator.Using, locator.Value)
+		if err != nil {
+			return false, err
+		}
+		if len(elements) > locator.Index {
+			elementId = elements[locator.Index]
+			return true, nil
+		}
+		return false, nil
+	})
+	if err != nil {
+		return StringNull, fmt.Errorf(" Wait for element %v failed :%w", locator, err)
+	}
+	return elementId, nil
+}
+
+// GetElementText 获取元素文本
+func (session *WdaSession) GetElementText(elementId string) (string, error) {
+	session, end := session.begin("WdaSession.GetElementText")
+	defer end()
+
+	body, err := session.getElementProperty(elementId, "/text")
+	if err != nil {
+		return StringNull, fmt.Errorf(" Get element text failed :%w", err)
+	}
+	return gjson.Get(string(body), "value").String(), nil
+}
+
+// GetElementAttribute 获取元素属性，如value、label、name、enabled，属性不存在时返回空字符串
+func (session *WdaSession) GetElementAttribute(elementId string, name string) (string, error) {
+	session, end := session.begin("WdaSession.GetElementAttribute")
+	defer end()
+
+	body, err := session.getElementProperty(elementId, "/attribute/"+url.PathEscape(name))
+	if err != nil {
+		return StringNull, fmt.Errorf(" Get element attribute %v failed :%w", name, err)
+	}
+	return gjson.Get(string(body), "value").String(), nil
+}
+
+// IsElementDisplayed 元素是否可见
+func (session *WdaSession) IsElementDisplayed(elementId string) (bool, error) {
+	session, end := session.begin("WdaSession.IsElementDisplayed")
+	defer end()
+
+	body, err := session.getElementProperty(elementId, "/displayed")
+	if err != nil {
+		return false, fmt.Errorf(" Get element displayed failed :%w", err)
+	}
+	return gjson.Get(string(body), "value").Bool(), nil
+}
+
+// IsElementEnabled 元素是否可用
+func (session *WdaSession) IsElementEnabled(elementId string) (bool, error) {
+	session, end := session.begin("WdaSession.IsElementEnabled")
+	defer end()
+
+	body, err := session.getElementProperty(elementId, "/enabled")
+	if err != nil {
+		return false, fmt.Errorf(" Get element enabled failed :%w", err)
+	}
+	return gjson.Get(string(body), "value").Bool(), nil
+}
+
+// GetElementRect 获取元素位置和大小
+func (session *WdaSession) GetElementRect(elementId string) (*ElementRect, error) {
+	session, end := session.begin("WdaSession.GetElementRect")
+	defer end()
+
+	body, err := session.getElementProperty(elementId, "/rect")
+	if err != nil {
+		return nil, fmt.Errorf(" Get element rect failed :%w", err)
+	}
+
+	data, err := GetDataFromRespBody(body)
+	if err != nil {
+		return nil, fmt.Errorf(" Get element rect failed :%w", err)
+	}
+
+	return &ElementRect{
+		X:      GetFloatFromValueInterface(data, "x"),
+		Y:      GetFloatFromValueInterface(data, "y"),
+		Width:  GetFloatFromValueInterface(data, "width"),
+		Height: GetFloatFromValueInterface(data, "height"),
+	}, nil
+}
+
+func (session *WdaSession) getElementProperty(elementId string, property string) ([]byte, error) {
+	api := session.url + "/session/" + session.SessionId() + "/element/" + elementId + property
+
+	body, err := session.get(api)
+	if err != nil {
+		return nil, err
+	}
+	// displayed、enabled等属性的值可能为false，这里只判断是否包含error
+	if value := gjson.Get(string(body), "value"); value.Get("error").Exists() {
+		return nil, newWdaError(fmt.Sprintf(" %v: %v ", value.Get("error").String(), value.Get("message").String()), http.StatusOK, body)
+	}
+	return body, nil
+}
+
+// Element 元素句柄，每次操作时按Locator重新查找元素，页面刷新后依然可用，适合在page object中使用
+// Timeout大于0时查找元素会等待元素出现
+type Element struct {
+	session *WdaSession
+	Locator Locator
+	Timeout time.Duration
+}
+
+// Element 根据Locator创建元素句柄，不会立即查找元素
+func (session *WdaSession) Element(locator Locator) *Element {
+	return &Element{
+		session: session,
+		Locator: locator,
+	}
+}
+
+// WithTimeout 返回查找时等待timeout的元素句柄
+func (element *Element) WithTimeout(timeout time.Duration) *Element {
+	return &Element{
+		session: element.session,
+		Locator: element.Locator,
+		Timeout: timeout,
+	}
+}
+
+// Id 查找元素，返回元素id
+func (element *Element) Id() (string, error) {
+	element, end := element.begin("Element.Id")
+	defer end()
+
+	if element.Timeout > 0 {
+		return element.session.WaitForElement(element.Locator, element.Timeout)
+	}
+	return element.session.FindElement(element.Locator)
+}
+
+// Exists 元素当前是否存在，不等待
+func (element *Element) Exists() (bool, error) {
+	element, end := element.begin("Element.Exists")
+	defer end()
+
+	elements, err := element.session.FindElements(element.Locator.Using, element.Locator.Value)
+	if err != nil {
+		return false, err
+	}
+	return len(elements) > element.Locator.Index, nil
+}
+
+// WaitFor 等待元素出现
+func (element *Element) WaitFor(timeout time.Duration) error {
+	element, end := element.begin("Element.WaitFor")
+	defer end()
+
+	_, err := element.session.WaitForElement(element.Locator, timeout)
+	return err
+}
+
+// Click 点击元素
+func (element *Element) Click() error {
+	element, end := element.begin("Element.Click")
+	defer end()
+
+	elementId, err := element.Id()
+	if err != nil {
+		return err
+	}
+	return element.session.ClickElement(elementId)
+}
+
+// TypeText 向元素输入文本
+func (element *Element) TypeText(text string) error {
+	element, end := element.begin("Element.TypeText")
+	defer end()
+
+	elementId, err := element.Id()
+	if err != nil {
+		return err
+	}
+	return element.session.TypingText(elementId, text)
+}
+
+// Clear 清空元素文本
+func (element *Element) Clear() error {
+	element, end := element.begin("Element.Clear")
+	defer end()
+
+	elementId, err := element.Id()
+	if err != nil {
+		return err
+	}
+	return element.session.ClearText(elementId)
+}
+
+// SetText 清空元素文本后输入text
+func (element *Element) SetText(text string) error {
+	element, end := element.begin("Element.SetText")
+	defer end()
+
+	elementId, err := element.Id()
+	if err != nil {
+		return err
+	}
+	if err = element.session.ClearText(elementId); err != nil {
+		return err
+	}
+	return element.session.TypingText(elementId, text)
+}
+
+// Text 获取元素文本
+func (element *Element) Text() (string, error) {
+	element, end := element.begin("Element.Text")
+	defer end()
+
+	elementId, err := element.Id()
+	if err != nil {
+		return StringNull, err
+	}
+	return element.session.GetElementText(elementId)
+}
+
+// Attribute 获取元素属性
+func (element *Element) Attribute(name string) (string, error) {
+	element, end := element.begin("Element.Attribute")
+	defer end()
+
+	elementId, err := element.Id()
+	if err != nil {
+		return StringNull, err
+	}
+	return element.session.GetElementAttribute(elementId, name)
+}
+
+// IsDisplayed 元素是否可见
+func (element *Element) IsDisplayed() (bool, error) {
+	element, end := element.begin("Element.IsDisplayed")
+	defer end()
+
+	elementId, err := element.Id()
+	if err != nil {
+		return false, err
+	}
+	return element.session.IsElementDisplayed(elementId)
+}
+
+// IsEnabled 元素是否可用
+func (element *Element) IsEnabled() (bool, error) {
+	element, end := element.begin("Element.IsEnabled")
+	defer end()
+
+	elementId, err := element.Id()
+	if err != nil {
+		return false, err
+	}
+	return element.session.IsElementEnabled(elementId)
+}
+
+// Rect 获取元素位置和大小
+func (element *Element) Rect() (*ElementRect, error) {
+	element, end := element.begin("Element.Rect")
+	defer end()
+
+	elementId, err := element.Id()
+	if err != nil {
+		return nil, err
+	}
+	return element.session.GetElementRect(elementId)
+}
+
+func (element *Element) String() string {
+	return element.Locator.String()
+}
@@ -0,0 +1,315 @@
+package WdaGo
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// AppState 对应XCUIApplicationState
+type AppState int64
+
+const (
+	AppStateUnknown                    AppState = 0
+	AppStateNotRunning                 AppState = 1
+	AppStateRunningBackgroundSuspended AppState = 2
+	AppStateRunningBackground          AppState = 3
+	AppStateRunningForeground          AppState = 4
+)
+
+// BatteryState 对应UIDeviceBatteryState
+type BatteryState int64
+
+const (
+	BatteryStateUnknown   BatteryState = 0
+	BatteryStateUnplugged BatteryState = 1
+	BatteryStateCharging  BatteryState = 2
+	BatteryStateFull      BatteryState = 3
+)
+
+// Orientation 对应UIDeviceOrientation，String()返回wda接口使用的方向名
+// 注意UIInterfaceOrientation的LandscapeLeft和LandscapeRight与UIDeviceOrientation相反，从UIKit的界面方向转换时需要交换
+type Orientation int64
+
+const (
+	OrientationUnknown            Orientation = 0
+	OrientationPortrait           Orientation = 1
+	OrientationPortraitUpsideDown Orientation = 2
+	OrientationLandscapeLeft      Orientation = 3
+	OrientationLandscapeRight     Orientation = 4
+	// OrientationFaceUp、OrientationFaceDown 只能由设备上报，wda的 /orientation 接口没有对应的方向名
+	OrientationFaceUp   Orientation = 5
+	OrientationFaceDown Orientation = 6
+)
+
+// ThermalState 对应NSProcessInfoThermalState，wda没有返回温度状态时为ThermalStateUnknown
+type ThermalState int64
+
+const (
+	ThermalStateUnknown  ThermalState = -1
+	ThermalStateNominal  ThermalState = 0
+	ThermalStateFair     ThermalState = 1
+	ThermalStateSerious  ThermalState = 2
+	ThermalStateCritical ThermalState = 3
+)
+
+// UserInterfaceIdiom 对应UIUserInterfaceIdiom
+type UserInterfaceIdiom int64
+
+const (
+	InterfaceIdiomUnspecified UserInterfaceIdiom = -1
+	InterfaceIdiomPhone       UserInterfaceIdiom = 0
+	InterfaceIdiomPad         UserInterfaceIdiom = 1
+	InterfaceIdiomTV          UserInterfaceIdiom = 2
+	InterfaceIdiomCarPlay     UserInterfaceIdiom = 3
+	InterfaceIdiomMac         UserInterfaceIdiom = 5
+	InterfaceIdiomVision      UserInterfaceIdiom = 6
+)
+
+// UserInterfaceStyle 对应UIUserInterfaceStyle，wda在系统不支持时返回unsupported
+type UserInterfaceStyle int64
+
+const (
+	InterfaceStyleUnsupported UserInterfaceStyle = -1
+	InterfaceStyleAutomatic   UserInterfaceStyle = 0
+	InterfaceStyleLight       UserInterfaceStyle = 1
+	InterfaceStyleDark        UserInterfaceStyle = 2
+)
+
+// LocationAuthorizationStatus 对应CLAuthorizationStatus
+type LocationAuthorizationStatus int64
+
+const (
+	LocationAuthNotDetermined       LocationAuthorizationStatus = 0
+	LocationAuthRestricted          LocationAuthorizationStatus = 1
+	LocationAuthDenied              LocationAuthorizationStatus = 2
+	LocationAuthAuthorizedAlways    LocationAuthorizationStatus = 3
+	LocationAuthAuthorizedWhenInUse LocationAuthorizationStatus = 4
+)
+
+var appStateNames = map[AppState]string{
+	AppStateUnknown:                    "unknown",
+	AppStateNotRunning:                 "notRunning",
+	AppStateRunningBackgroundSuspended: "runningBackgroundSuspended",
+	AppStateRunningBackground:          "runningBackground",
+	AppStateRunningForeground:          "runningForeground",
+}
+
+var batteryStateNames = map[BatteryState]string{
+	BatteryStateUnknown:   "unknown",
+	BatteryStateUnplugged: "unplugged",
+	BatteryStateCharging:  "charging",
+	BatteryStateFull:      "full",
+}
+
+// OrientationNameLandscapeLeft 部分wda版本使用该名称表示向左横屏，与LANDSCAPE等价
+const OrientationNameLandscapeLeft = "UIA_DEVICE_ORIENTATION_LANDSCAPELEFT"
+
+// orientationNames wda /orientation 接口使用的方向名
+var orientationNames = map[Orientation]string{
+	OrientationUnknown:            "UNKNOWN",
+	OrientationPortrait:           "PORTRAIT",
+	OrientationPortraitUpsideDown: "UIA_DEVICE_ORIENTATION_PORTRAIT_UPSIDEDOWN",
+	OrientationLandscapeLeft:      "LANDSCAPE",
+	OrientationLandscapeRight:     "UIA_DEVICE_ORIENTATION_LANDSCAPERIGHT",
+}
+
+var thermalStateNames = map[ThermalState]string{
+	ThermalStateUnknown:  "unknown",
+	ThermalStateNominal:  "nominal",
+	ThermalStateFair:     "fair",
+	ThermalStateSerious:  "serious",
+	ThermalStateCritical: "critical",
+}
+
+var interfaceIdiomNames = map[UserInterfaceIdiom]string{
+	InterfaceIdiomUnspecified: "unspecified",
+	InterfaceIdiomPhone:       "phone",
+	InterfaceIdiomPad:         "pad",
+	InterfaceIdiomTV:          "tv",
+	InterfaceIdiomCarPlay:     "carPlay",
+	InterfaceIdiomMac:         "mac",
+	InterfaceIdiomVision:      "vision",
+}
+
+var interfaceStyleNames = map[UserInterfaceStyle]string{
+	InterfaceStyleUnsupported: "unsupported",
+	InterfaceStyleAutomatic:   "automatic",
+	InterfaceStyleLight:       "light",
+	InterfaceStyleDark:        "dark",
+}
+
+var locationAuthNames = map[LocationAuthorizationStatus]string{
+	LocationAuthNotDetermined:       "notDetermined",
+	LocationAuthRestricted:          "restricted",
+	LocationAuthDenied:              "denied",
+	LocationAuthAuthorizedAlways:    "authorizedAlways",
+	LocationAuthAuthorizedWhenInUse: "authorizedWhenInUse",
+}
+
+func (state AppState) String() string {
+	return enumName(appStateNames, state)
+}
+
+func (state AppState) MarshalJSON() ([]byte, error) {
+	return json.Marshal(state.String())
+}
+
+func (state *AppState) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, appStateNames, state)
+}
+
+func (state BatteryState) String() string {
+	return enumName(batteryStateNames, state)
+}
+
+func (state BatteryState) MarshalJSON() ([]byte, error) {
+	return json.Marshal(state.String())
+}
+
+func (state *BatteryState) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, batteryStateNames, state)
+}
+
+func (orientation Orientation) String() string {
+	return enumName(orientationNames, orientation)
+}
+
+func (orientation Orientation) MarshalJSON() ([]byte, error) {
+	return json.Marshal(orientation.String())
+}
+
+func (orientation *Orientation) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, orientationNames, orientation)
+}
+
+// IsLandscape 是否为横屏
+func (orientation Orientation) IsLandscape() bool {
+	return orientation == OrientationLandscapeLeft || orientation == OrientationLandscapeRight
+}
+
+// ParseOrientation 将wda返回的方向名转为Orientation
+func ParseOrientation(name string) (Orientation, error) {
+	if strings.EqualFold(name, OrientationNameLandscapeLeft) {
+		return OrientationLandscapeLeft, nil
+	}
+	return parseEnumName(orientationNames, name)
+}
+
+// ParseThermalState 将温度状态名转为ThermalState，如 serious
+func ParseThermalState(name string) (ThermalState, error) {
+	return parseEnumName(thermalStateNames, name)
+}
+
+func (state ThermalState) String() string {
+	return enumName(thermalStateNames, state)
+}
+
+func (state ThermalState) MarshalJSON() ([]byte, error) {
+	return json.Marshal(state.String())
+}
+
+func (state *ThermalState) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, thermalStateNames, state)
+}
+
+func (idiom UserInterfaceIdiom) String() string {
+	return enumName(interfaceIdiomNames, idiom)
+}
+
+func (idiom UserInterfaceIdiom) MarshalJSON() ([]byte, error) {
+	return json.Marshal(idiom.String())
+}
+
+func (idiom *UserInterfaceIdiom) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, interfaceIdiomNames, idiom)
+}
+
+func (style UserInterfaceStyle) String() string {
+	return enumName(interfaceStyleNames, style)
+}
+
+func (style UserInterfaceStyle) MarshalJSON() ([]byte, error) {
+	return json.Marshal(style.String())
+}
+
+func (style *UserInterfaceStyle) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, interfaceStyleNames, style)
+}
+
+func (status LocationAuthorizationStatus) String() string {
+	return enumName(locationAuthNames, status)
+}
+
+func (status LocationAuthorizationStatus) MarshalJSON() ([]byte, error) {
+	return json.Marshal(status.String())
+}
+
+func (status *LocationAuthorizationStatus) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, locationAuthNames, status)
+}
+
+// IsAuthorized 是否已授权获取定位
+func (status LocationAuthorizationStatus) IsAuthorized() bool {
+	return status == LocationAuthAuthorizedAlways || status == LocationAuthAuthorizedWhenInUse
+}
+
+// getEnumFromValueInterface 从wda返回的value中读取枚举值，wda对不同字段有时返回数字有时返回名称，两者都支持
+func getEnumFromValueInterface[T ~int64](data map[string]interface{}, key string, names map[T]string, defaultValue T) T {
+	val, ok := data[key]
+	if !ok || val == nil {
+		return defaultValue
+	}
+
+	raw, err := json.Marshal(val)
+	if err != nil {
+		return defaultValue
+	}
+
+	result := defaultValue
+	if err = unmarshalEnum(raw, names, &result); err != nil {
+		return defaultValue
+	}
+	return result
+}
+
+func enumName[T ~int64](names map[T]string, value T) string {
+	if name, ok := names[value]; ok {
+		return name
+	}
+	return strconv.FormatInt(int64(value), 10)
+}
+
+func parseEnumName[T ~int64](names map[T]string, name string) (T, error) {
+	for value, n := range names {
+		if strings.EqualFold(n, name) {
+			return value, nil
+		}
+	}
+	if i, err := strconv.ParseInt(name, 10, 64); err == nil {
+		return T(i), nil
+	}
+	return 0, fmt.Errorf(" Unknown enum name %v ", name)
+}
+
+// unmarshalEnum 支持数字和名称两种json格式
+func unmarshalEnum[T ~int64](data []byte, names map[T]string, value *T) error {
+	var number float64
+	if err := json.Unmarshal(data, &number); err == nil {
+		*value = T(number)
+		return nil
+	}
+
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return fmt.Errorf(" Parse enum failed :%w", err)
+	}
+
+	parsed, err := parseEnumName(names, name)
+	if err != nil {
+		return err
+	}
+	*value = parsed
+	return nil
+}
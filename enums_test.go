@@ -0,0 +1,149 @@
+package WdaGo
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+// roundTrip 序列化后再解析，结果应与原值相同
+func roundTrip[T ~int64](t *testing.T, values map[T]string, unmarshal func([]byte) (T, error)) {
+	t.Helper()
+	for value, name := range values {
+		data, err := json.Marshal(value)
+		if err != nil {
+			t.Fatalf("Marshal(%d) error = %v", value, err)
+		}
+		if string(data) != `"`+name+`"` {
+			t.Errorf("Marshal(%d) = %s, want %q", value, data, name)
+		}
+		got, err := unmarshal(data)
+		if err != nil {
+			t.Fatalf("Unmarshal(%s) error = %v", data, err)
+		}
+		if got != value {
+			t.Errorf("Unmarshal(%s) = %d, want %d", data, got, value)
+		}
+	}
+}
+
+func unmarshalAs[T any, P interface {
+	*T
+	json.Unmarshaler
+}](data []byte) (T, error) {
+	var value T
+	err := P(&value).UnmarshalJSON(data)
+	return value, err
+}
+
+func TestEnumRoundTrip(t *testing.T) {
+	t.Run("AppState", func(t *testing.T) { roundTrip(t, appStateNames, unmarshalAs[AppState]) })
+	t.Run("BatteryState", func(t *testing.T) { roundTrip(t, batteryStateNames, unmarshalAs[BatteryState]) })
+	t.Run("Orientation", func(t *testing.T) { roundTrip(t, orientationNames, unmarshalAs[Orientation]) })
+	t.Run("ThermalState", func(t *testing.T) { roundTrip(t, thermalStateNames, unmarshalAs[ThermalState]) })
+	t.Run("UserInterfaceIdiom", func(t *testing.T) { roundTrip(t, interfaceIdiomNames, unmarshalAs[UserInterfaceIdiom]) })
+	t.Run("UserInterfaceStyle", func(t *testing.T) { roundTrip(t, interfaceStyleNames, unmarshalAs[UserInterfaceStyle]) })
+	t.Run("HealthState", func(t *testing.T) { roundTrip(t, healthStateNames, unmarshalAs[HealthState]) })
+	t.Run("LocationAuthorizationStatus", func(t *testing.T) {
+		roundTrip(t, locationAuthNames, unmarshalAs[LocationAuthorizationStatus])
+	})
+}
+
+func TestEnumUnmarshal(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    AppState
+		wantErr bool
+	}{
+		{"number", `4`, AppStateRunningForeground, false},
+		{"name", `"notRunning"`, AppStateNotRunning, false},
+		{"name case insensitive", `"RUNNINGBACKGROUND"`, AppStateRunningBackground, false},
+		{"numeric string", `"2"`, AppStateRunningBackgroundSuspended, false},
+		{"unknown number keeps value", `9`, AppState(9), false},
+		{"unknown name", `"flying"`, 0, true},
+		{"invalid json", `{}`, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := unmarshalAs[AppState]([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UnmarshalJSON(%s) error = %v, wantErr %v", tt.data, err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("UnmarshalJSON(%s) = %d, want %d", tt.data, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnumString(t *testing.T) {
+	tests := []struct {
+		value fmt.Stringer
+		want  string
+	}{
+		{AppStateRunningForeground, "runningForeground"},
+		{ThermalStateSerious, "serious"},
+		{InterfaceIdiomUnspecified, "unspecified"},
+		{OrientationLandscapeRight, "UIA_DEVICE_ORIENTATION_LANDSCAPERIGHT"},
+		{OrientationFaceUp, "5"},
+		{BatteryState(42), "42"},
+	}
+	for _, tt := range tests {
+		if got := tt.value.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestParseOrientation(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    Orientation
+		wantErr bool
+	}{
+		{"PORTRAIT", OrientationPortrait, false},
+		{"LANDSCAPE", OrientationLandscapeLeft, false},
+		{OrientationNameLandscapeLeft, OrientationLandscapeLeft, false},
+		{"uia_device_orientation_landscaperight", OrientationLandscapeRight, false},
+		{"UIA_DEVICE_ORIENTATION_PORTRAIT_UPSIDEDOWN", OrientationPortraitUpsideDown, false},
+		{"SIDEWAYS", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseOrientation(tt.name)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("ParseOrientation(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("ParseOrientation(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	if !OrientationLandscapeRight.IsLandscape() || OrientationPortrait.IsLandscape() || OrientationFaceUp.IsLandscape() {
+		t.Error("IsLandscape() mismatch")
+	}
+}
+
+func TestGetEnumFromValueInterface(t *testing.T) {
+	data := map[string]interface{}{
+		"number":  2.0,
+		"name":    "charging",
+		"invalid": "exploding",
+		"null":    nil,
+	}
+	tests := []struct {
+		key  string
+		want BatteryState
+	}{
+		{"number", BatteryStateCharging},
+		{"name", BatteryStateCharging},
+		{"invalid", BatteryStateUnknown},
+		{"null", BatteryStateUnknown},
+		{"missing", BatteryStateUnknown},
+	}
+	for _, tt := range tests {
+		if got := getEnumFromValueInterface(data, tt.key, batteryStateNames, BatteryStateUnknown); got != tt.want {
+			t.Errorf("getEnumFromValueInterface(%q) = %v, want %v", tt.key, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,418 @@
+package WdaGo
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tidwall/gjson"
+)
+
+// EventType 事件类型
+type EventType int64
+
+const (
+	EventSessionCreated     EventType = 1
+	EventSessionDeleted     EventType = 2
+	EventCommandStarted     EventType = 3
+	EventCommandFinished    EventType = 4
+	EventAlertDetected      EventType = 5
+	EventAppStateChanged    EventType = 6
+	EventScreenshotCaptured EventType = 7
+	EventError              EventType = 8
+	EventCommandRetried     EventType = 9
+	EventOperationStarted   EventType = 10
+	EventOperationFinished  EventType = 11
+)
+
+// DefaultEventBuffer 通道订阅默认的缓冲大小
+const DefaultEventBuffer = 256
+
+var eventTypeNames = map[EventType]string{
+	EventSessionCreated:     "sessionCreated",
+	EventSessionDeleted:     "sessionDeleted",
+	EventCommandStarted:     "commandStarted",
+	EventCommandFinished:    "commandFinished",
+	EventAlertDetected:      "alertDetected",
+	EventAppStateChanged:    "appStateChanged",
+	EventScreenshotCaptured: "screenshotCaptured",
+	EventError:              "error",
+	EventCommandRetried:     "commandRetried",
+	EventOperationStarted:   "operationStarted",
+	EventOperationFinished:  "operationFinished",
+}
+
+var (
+	eventSessionPattern = regexp.MustCompile(`^/session/([^/]+)`)
+	eventElementPattern = regexp.MustCompile(`^/element/[^/]+`)
+)
+
+func (eventType EventType) String() string {
+	return enumName(eventTypeNames, eventType)
+}
+
+func (eventType EventType) MarshalJSON() ([]byte, error) {
+	return json.Marshal(eventType.String())
+}
+
+func (eventType *EventType) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, eventTypeNames, eventType)
+}
+
+// Event 库内发生的事件，不同类型的事件使用不同的字段
+//
+//	Method、Endpoint  命令事件的请求方法和路径，路径中不包含wda地址和session，如 /wda/tap
+//	Command           命令事件的完整记录，命令开始时没有响应和耗时
+//	Duration          命令耗时，完成事件包括重试，重试事件为本次请求的耗时；方法调用结束事件为方法的耗时
+//	Operation         方法调用事件的调用，命令事件中为发送该命令的调用，不属于方法调用时为空
+//	Alert             弹窗文本
+//	BundleId、AppState app状态变化
+//	Screenshot        截图数据
+//	Err               命令失败或者wda返回的错误
+type Event struct {
+	Type       EventType      `json:"type"`
+	Time       time.Time      `json:"time"`
+	SessionId  string         `json:"sessionId,omitempty"`
+	Method     string         `json:"method,omitempty"`
+	Endpoint   string         `json:"endpoint,omitempty"`
+	Command    *CommandRecord `json:"-"`
+	Operation  *Operation     `json:"operation,omitempty"`
+	Duration   time.Duration  `json:"duration,omitempty"`
+	Alert      string         `json:"alert,omitempty"`
+	BundleId   string         `json:"bundleId,omitempty"`
+	AppState   AppState       `json:"appState,omitempty"`
+	Screenshot []byte         `json:"-"`
+	Err        error          `json:"-"`
+}
+
+// EventHandler 同步订阅的回调，在发布事件的协程中执行，耗时操作会阻塞wda命令
+type EventHandler func(event Event)
+
+// EventBus 事件总线，session的每条命令都会转换为事件发布
+type EventBus struct {
+	mu            sync.RWMutex
+	subscriptions []*Subscription
+	appStates     map[string]AppState
+}
+
+// Subscription 一个订阅，Types为空时接收全部事件
+//
+// 通道订阅在缓冲满时丢弃事件，不会阻塞wda命令，Dropped返回丢弃的数量
+type Subscription struct {
+	C <-chan Event
+
+	bus     *EventBus
+	types   map[EventType]bool
+	handler EventHandler
+	ch      chan Event
+
+	mu      sync.Mutex
+	closed  bool
+	dropped int64
+}
+
+// NewEventBus 创建事件总线
+func NewEventBus() *EventBus {
+	return &EventBus{appStates: make(map[string]AppState)}
+}
+
+// Subscribe 同步订阅，types为空时订阅全部事件
+func (bus *EventBus) Subscribe(handler EventHandler, types ...EventType) *Subscription {
+	return bus.add(&Subscription{handler: handler, types: typeSet(types)})
+}
+
+// SubscribeChan 通过缓冲通道订阅，buffer<=0时使用DefaultEventBuffer，取消订阅后通道关闭
+func (bus *EventBus) SubscribeChan(buffer int, types ...EventType) *Subscription {
+	if buffer <= 0 {
+		buffer = DefaultEventBuffer
+	}
+	ch := make(chan Event, buffer)
+	return bus.add(&Subscription{C: ch, ch: ch, types: typeSet(types)})
+}
+
+func (bus *EventBus) add(subscription *Subscription) *Subscription {
+	subscription.bus = bus
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+	bus.subscriptions = append(bus.subscriptions, subscription)
+	return subscription
+}
+
+// Unsubscribe 取消订阅，通道订阅会关闭通道
+// 同步订阅在Unsubscribe返回后不会再开始新的回调，与Unsubscribe同时开始的回调不会等待其结束，
+// 回调中可以取消自己的订阅
+func (subscription *Subscription) Unsubscribe() {
+	bus := subscription.bus
+	bus.mu.Lock()
+	for i, item := range bus.subscriptions {
+		if item == subscription {
+			bus.subscriptions = append(bus.subscriptions[:i:i], bus.subscriptions[i+1:]...)
+			break
+		}
+	}
+	bus.mu.Unlock()
+
+	subscription.mu.Lock()
+	defer subscription.mu.Unlock()
+	if !subscription.closed {
+		subscription.closed = true
+		if subscription.ch != nil {
+			close(subscription.ch)
+		}
+	}
+}
+
+// Dropped 通道缓冲满时丢弃的事件数量
+func (subscription *Subscription) Dropped() int64 {
+	subscription.mu.Lock()
+	defer subscription.mu.Unlock()
+	return subscription.dropped
+}
+
+func (subscription *Subscription) deliver(event Event) {
+	if len(subscription.types) > 0 && !subscription.types[event.Type] {
+		return
+	}
+
+	subscription.mu.Lock()
+	if subscription.closed {
+		subscription.mu.Unlock()
+		return
+	}
+	if subscription.handler != nil {
+		// 回调中可能取消订阅，不能持有锁执行
+		subscription.mu.Unlock()
+		subscription.handler(event)
+		return
+	}
+	defer subscription.mu.Unlock()
+	select {
+	case subscription.ch <- event:
+	default:
+		subscription.dropped++
+	}
+}
+
+// Publish 发布事件，Time为空时使用当前时间，可用于发布自定义的事件
+func (bus *EventBus) Publish(event Event) {
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	bus.mu.RLock()
+	subscriptions := bus.subscriptions
+	bus.mu.RUnlock()
+
+	for _, subscription := range subscriptions {
+		subscription.deliver(event)
+	}
+}
+
+// hasSubscribers 没有订阅时不解析命令
+func (bus *EventBus) hasSubscribers() bool {
+	bus.mu.RLock()
+	defer bus.mu.RUnlock()
+	return len(bus.subscriptions) > 0
+}
+
+// operationStarted 方法调用开始
+func (bus *EventBus) operationStarted(operation *Operation, sessionId string) {
+	bus.Publish(Event{
+		Type:      EventOperationStarted,
+		Time:      operation.Started,
+		SessionId: sessionId,
+		Operation: operation,
+	})
+}
+
+// operationFinished 方法调用结束，方法返回的错误不在事件中，失败的命令见命令事件
+func (bus *EventBus) operationFinished(operation *Operation, sessionId string) {
+	bus.Publish(Event{
+		Type:      EventOperationFinished,
+		SessionId: sessionId,
+		Operation: operation,
+		Duration:  time.Since(operation.Started),
+	})
+}
+
+// commandStarted 作为请求开始的观察者
+func (bus *EventBus) commandStarted(record CommandRecord) {
+	if !bus.hasSubscribers() {
+		return
+	}
+	sessionId, endpoint := SplitCommandUrl(record.Url)
+	bus.Publish(Event{
+		Type:      EventCommandStarted,
+		Time:      record.Started,
+		SessionId: sessionId,
+		Method:    record.Method,
+		Endpoint:  endpoint,
+		Command:   &record,
+		Operation: record.Operation,
+	})
+}
+
+// commandRetried 作为请求重试的观察者，Err为本次失败的原因
+func (bus *EventBus) commandRetried(record CommandRecord) {
+	if !bus.hasSubscribers() {
+		return
+	}
+	sessionId, endpoint := SplitCommandUrl(record.Url)
+	bus.Publish(Event{
+		Type:      EventCommandRetried,
+		SessionId: sessionId,
+		Method:    record.Method,
+		Endpoint:  endpoint,
+		Command:   &record,
+		Operation: record.Operation,
+		Duration:  record.Duration,
+		Err:       record.Err,
+	})
+}
+
+// commandFinished 作为请求完成的观察者，根据命令发布会话、弹窗、app状态、截图和错误事件
+func (bus *EventBus) commandFinished(record CommandRecord) {
+	if !bus.hasSubscribers() {
+		return
+	}
+	sessionId, endpoint := SplitCommandUrl(record.Url)
+	base := Event{SessionId: sessionId, Method: record.Method, Endpoint: endpoint, Command: &record, Operation: record.Operation}
+
+	event := base
+	event.Type = EventCommandFinished
+	event.Duration = record.TotalDuration
+	value := gjson.GetBytes(record.Response, "value")
+	wdaError := value.Get("error").String()
+	event.Err = record.Err
+	if wdaError != "" {
+		// wda返回的错误比http状态码更具体
+		event.Err = fmt.Errorf(" %v: %v ", wdaError, value.Get("message").String())
+	}
+	bus.Publish(event)
+
+	if event.Err != nil {
+		failed := base
+		failed.Type = EventError
+		failed.Err = event.Err
+		bus.Publish(failed)
+
+		if wdaError == "unexpected alert open" {
+			alert := base
+			alert.Type = EventAlertDetected
+			alert.Alert = value.Get("message").String()
+			bus.Publish(alert)
+		}
+		return
+	}
+
+	switch {
+	case record.Method == "POST" && endpoint == "/session":
+		created := base
+		created.Type = EventSessionCreated
+		created.SessionId = gjson.GetBytes(record.Response, "value.sessionId").String()
+		created.BundleId = gjson.GetBytes(record.Payload, "capabilities.bundleId").String()
+		bus.Publish(created)
+	case record.Method == "DELETE" && endpoint == "" && sessionId != "":
+		deleted := base
+		deleted.Type = EventSessionDeleted
+		bus.Publish(deleted)
+	case record.Method == "GET" && strings.HasSuffix(endpoint, "/screenshot"):
+		if data, err := base64.StdEncoding.DecodeString(value.String()); err == nil {
+			captured := base
+			captured.Type = EventScreenshotCaptured
+			captured.Screenshot = data
+			bus.Publish(captured)
+		}
+	case record.Method == "GET" && endpoint == "/alert/text":
+		alert := base
+		alert.Type = EventAlertDetected
+		alert.Alert = value.String()
+		bus.Publish(alert)
+	case record.Method == "POST" && strings.HasPrefix(endpoint, "/wda/apps/"):
+		bundleId := gjson.GetBytes(record.Payload, "bundleId").String()
+		switch endpoint {
+		case "/wda/apps/launch", "/wda/apps/activate", "/wda/apps/launchUnattached":
+			bus.appStateChanged(base, bundleId, AppStateRunningForeground)
+		case "/wda/apps/terminate":
+			// 未运行的app返回false，状态同样为notRunning
+			bus.appStateChanged(base, bundleId, AppStateNotRunning)
+		case "/wda/apps/state":
+			bus.appStateChanged(base, bundleId, AppState(value.Int()))
+		}
+	}
+}
+
+// appStateChanged 记录app的最近状态，状态变化时发布事件
+func (bus *EventBus) appStateChanged(base Event, bundleId string, state AppState) {
+	if bundleId == "" {
+		return
+	}
+	bus.mu.Lock()
+	previous, ok := bus.appStates[bundleId]
+	bus.appStates[bundleId] = state
+	bus.mu.Unlock()
+	if ok && previous == state {
+		return
+	}
+
+	event := base
+	event.Type = EventAppStateChanged
+	event.BundleId = bundleId
+	event.AppState = state
+	bus.Publish(event)
+}
+
+// SplitCommandUrl 从请求地址中拆分sessionId和路径，如 /session/abc/wda/tap -> abc, /wda/tap
+// 删除session的路径为空，元素id替换为{elementId}，便于按命令统计
+func SplitCommandUrl(rawUrl string) (string, string) {
+	path := rawUrl
+	if parsed, err := url.Parse(rawUrl); err == nil {
+		path = parsed.Path
+	}
+	sessionId := ""
+	if match := eventSessionPattern.FindStringSubmatch(path); match != nil {
+		sessionId = match[1]
+		path = path[len(match[0]):]
+	}
+	return sessionId, eventElementPattern.ReplaceAllString(path, "/element/{elementId}")
+}
+
+// 命令的错误码，wda返回的错误使用wda的错误码，如 no such element
+const (
+	ErrorCodeTransport = "transport"
+	ErrorCodeHttp      = "http"
+)
+
+// ErrorCode 失败命令的错误码，wda没有返回错误码时按http状态码或者网络错误分类，用于按错误统计
+func ErrorCode(record *CommandRecord) string {
+	if code := gjson.GetBytes(record.Response, "value.error").String(); code != "" {
+		return code
+	}
+	if record.StatusCode >= 400 {
+		return ErrorCodeHttp
+	}
+	return ErrorCodeTransport
+}
+
+// DeviceName wda地址中的主机和端口，如 http://127.0.0.1:8100 -> 127.0.0.1:8100，用于没有指定设备名时的指标标签
+func DeviceName(wdaUrl string) string {
+	if parsed, err := url.Parse(wdaUrl); err == nil && parsed.Host != "" {
+		return parsed.Host
+	}
+	return wdaUrl
+}
+
+func typeSet(types []EventType) map[EventType]bool {
+	if len(types) == 0 {
+		return nil
+	}
+	set := make(map[EventType]bool, len(types))
+	for _, eventType := range types {
+		set[eventType] = true
+	}
+	return set
+}
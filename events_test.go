@@ -0,0 +1,310 @@
+package WdaGo
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestSplitCommandUrl(t *testing.T) {
+	tests := []struct {
+		url       string
+		sessionId string
+		endpoint  string
+	}{
+		{"http://127.0.0.1:8100/session/abc/wda/tap", "abc", "/wda/tap"},
+		{"http://127.0.0.1:8100/session/abc", "abc", ""},
+		{"http://127.0.0.1:8100/session", "", "/session"},
+		{"http://127.0.0.1:8100/status", "", "/status"},
+		{"http://127.0.0.1:8100/session/abc/element/E1-2/attribute/name", "abc", "/element/{elementId}/attribute/name"},
+		{"http://127.0.0.1:8100/session/abc/elements", "abc", "/elements"},
+		{"http://127.0.0.1:8100/source?format=json", "", "/source"},
+		{"/session/abc/element/E1/click", "abc", "/element/{elementId}/click"},
+	}
+	for _, tt := range tests {
+		sessionId, endpoint := SplitCommandUrl(tt.url)
+		if sessionId != tt.sessionId || endpoint != tt.endpoint {
+			t.Errorf("SplitCommandUrl(%q) = %q, %q, want %q, %q", tt.url, sessionId, endpoint, tt.sessionId, tt.endpoint)
+		}
+	}
+}
+
+// collectEvents 同步订阅全部事件
+func collectEvents(bus *EventBus, types ...EventType) (*Subscription, func() []Event) {
+	var mu sync.Mutex
+	var events []Event
+	subscription := bus.Subscribe(func(event Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, event)
+	}, types...)
+	return subscription, func() []Event {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]Event(nil), events...)
+	}
+}
+
+func TestCommandFinishedEvents(t *testing.T) {
+	const base = "http://127.0.0.1:8100"
+	tests := []struct {
+		name   string
+		record CommandRecord
+		want   []EventType
+		check  func(t *testing.T, events []Event)
+	}{
+		{
+			name: "session created",
+			record: CommandRecord{Method: "POST", Url: base + "/session",
+				Payload:  []byte(`{"capabilities":{"bundleId":"com.demo"}}`),
+				Response: []byte(`{"value":{"sessionId":"s1"},"sessionId":"s1"}`)},
+			want: []EventType{EventCommandFinished, EventSessionCreated},
+			check: func(t *testing.T, events []Event) {
+				if events[1].SessionId != "s1" || events[1].BundleId != "com.demo" {
+					t.Errorf("created event = %+v", events[1])
+				}
+			},
+		},
+		{
+			name:   "session deleted",
+			record: CommandRecord{Method: "DELETE", Url: base + "/session/s1", Response: []byte(`{"value":null}`)},
+			want:   []EventType{EventCommandFinished, EventSessionDeleted},
+		},
+		{
+			name:   "element delete is not a session delete",
+			record: CommandRecord{Method: "DELETE", Url: base + "/session/s1/element/e1", Response: []byte(`{"value":null}`)},
+			want:   []EventType{EventCommandFinished},
+		},
+		{
+			name: "screenshot",
+			record: CommandRecord{Method: "GET", Url: base + "/session/s1/screenshot",
+				Response: []byte(`{"value":"aGVsbG8="}`)},
+			want: []EventType{EventCommandFinished, EventScreenshotCaptured},
+			check: func(t *testing.T, events []Event) {
+				if string(events[1].Screenshot) != "hello" {
+					t.Errorf("screenshot = %q", events[1].Screenshot)
+				}
+			},
+		},
+		{
+			name:   "alert text",
+			record: CommandRecord{Method: "GET", Url: base + "/session/s1/alert/text", Response: []byte(`{"value":"Allow?"}`)},
+			want:   []EventType{EventCommandFinished, EventAlertDetected},
+			check: func(t *testing.T, events []Event) {
+				if events[1].Alert != "Allow?" {
+					t.Errorf("alert = %q", events[1].Alert)
+				}
+			},
+		},
+		{
+			name: "wda error with alert",
+			record: CommandRecord{Method: "POST", Url: base + "/session/s1/element/e1/click",
+				Response: []byte(`{"value":{"error":"unexpected alert open","message":"Allow?"}}`)},
+			want: []EventType{EventCommandFinished, EventError, EventAlertDetected},
+			check: func(t *testing.T, events []Event) {
+				if events[0].Err == nil || events[0].Endpoint != "/element/{elementId}/click" || events[2].Alert != "Allow?" {
+					t.Errorf("events = %+v", events)
+				}
+			},
+		},
+		{
+			name:   "transport error",
+			record: CommandRecord{Method: "GET", Url: base + "/status", Err: http.ErrHandlerTimeout},
+			want:   []EventType{EventCommandFinished, EventError},
+		},
+		{
+			name: "app launch",
+			record: CommandRecord{Method: "POST", Url: base + "/session/s1/wda/apps/launch",
+				Payload: []byte(`{"bundleId":"com.demo"}`), Response: []byte(`{"value":null}`)},
+			want: []EventType{EventCommandFinished, EventAppStateChanged},
+			check: func(t *testing.T, events []Event) {
+				if events[1].BundleId != "com.demo" || events[1].AppState != AppStateRunningForeground {
+					t.Errorf("app state event = %+v", events[1])
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bus := NewEventBus()
+			_, events := collectEvents(bus)
+			bus.commandFinished(tt.record)
+
+			got := events()
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d events %v, want %v", len(got), got, tt.want)
+			}
+			for i := range tt.want {
+				if got[i].Type != tt.want[i] {
+					t.Errorf("event %d = %v, want %v", i, got[i].Type, tt.want[i])
+				}
+				if got[i].Command == nil {
+					t.Errorf("event %d has no command", i)
+				}
+			}
+			if tt.check != nil {
+				tt.check(t, got)
+			}
+		})
+	}
+}
+
+func TestAppStateChangedOnlyOnChange(t *testing.T) {
+	bus := NewEventBus()
+	_, events := collectEvents(bus, EventAppStateChanged)
+	record := func(endpoint, response string) CommandRecord {
+		return CommandRecord{Method: "POST", Url: "http://wda/session/s1" + endpoint,
+			Payload: []byte(`{"bundleId":"com.demo"}`), Response: []byte(response)}
+	}
+
+	bus.commandFinished(record("/wda/apps/launch", `{"value":null}`))
+	bus.commandFinished(record("/wda/apps/activate", `{"value":null}`))
+	bus.commandFinished(record("/wda/apps/state", `{"value":4}`))
+	bus.commandFinished(record("/wda/apps/terminate", `{"value":true}`))
+	bus.commandFinished(record("/wda/apps/state", `{"value":1}`))
+
+	got := events()
+	if len(got) != 2 || got[0].AppState != AppStateRunningForeground || got[1].AppState != AppStateNotRunning {
+		t.Errorf("app state events = %+v, want foreground then notRunning", got)
+	}
+}
+
+func TestSessionCommandEvents(t *testing.T) {
+	session := newTestSession(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeValue(w, "null")
+	}))
+	_, events := collectEvents(session.Events(), EventCommandStarted, EventCommandFinished, EventAppStateChanged)
+
+	if err := session.LaunchApp("com.demo"); err != nil {
+		t.Fatalf("LaunchApp() error = %v", err)
+	}
+	got := events()
+	want := []EventType{EventCommandStarted, EventCommandFinished, EventAppStateChanged}
+	if len(got) != len(want) {
+		t.Fatalf("got events %+v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i].Type != want[i] || got[i].SessionId != testSessionId || got[i].Endpoint != "/wda/apps/launch" {
+			t.Errorf("event %d = %+v, want %v on /wda/apps/launch", i, got[i], want[i])
+		}
+	}
+}
+
+func TestOperationEvents(t *testing.T) {
+	session := newTestSession(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/elements") {
+			writeValue(w, `[{"ELEMENT":"e1"}]`)
+			return
+		}
+		writeValue(w, "null")
+	}))
+	_, events := collectEvents(session.Events(), EventOperationStarted, EventOperationFinished, EventCommandFinished)
+
+	// Element.Click 中调用的 FindElements、ClickElement 不生成新的调用，两个请求都属于 Element.Click
+	if err := session.Element(Locator{Using: StrategyAccessibilityId, Value: "login"}).Click(); err != nil {
+		t.Fatalf("Click() error = %v", err)
+	}
+	got := events()
+	want := []EventType{EventOperationStarted, EventCommandFinished, EventCommandFinished, EventOperationFinished}
+	if len(got) != len(want) {
+		t.Fatalf("got events %+v, want %v", got, want)
+	}
+	operation := got[0].Operation
+	if operation == nil || operation.Name != "Element.Click" {
+		t.Fatalf("operation = %+v, want Element.Click", operation)
+	}
+	for i := range want {
+		if got[i].Type != want[i] || got[i].Operation != operation {
+			t.Errorf("event %d = %v of %+v, want %v of Element.Click", i, got[i].Type, got[i].Operation, want[i])
+		}
+	}
+
+	// 直接使用HTTPClient的请求不属于方法调用
+	session.client.GetRequest(session.Url()+"/status", nil)
+	if last := events()[len(want)]; last.Type != EventCommandFinished || last.Operation != nil {
+		t.Errorf("event = %v of %+v, want command without operation", last.Type, last.Operation)
+	}
+}
+
+func TestUnsubscribe(t *testing.T) {
+	bus := NewEventBus()
+
+	calls := 0
+	var handler *Subscription
+	handler = bus.Subscribe(func(event Event) {
+		calls++
+		// 回调中取消自己的订阅不能死锁
+		handler.Unsubscribe()
+	})
+	channel := bus.SubscribeChan(1, EventError)
+	filtered := bus.SubscribeChan(1, EventAlertDetected)
+
+	bus.Publish(Event{Type: EventError})
+	bus.Publish(Event{Type: EventError})
+	if calls != 1 {
+		t.Errorf("handler called %d times, want 1", calls)
+	}
+	if channel.Dropped() != 1 {
+		t.Errorf("Dropped() = %d, want 1", channel.Dropped())
+	}
+	if len(filtered.C) != 0 {
+		t.Error("filtered subscription received other event type")
+	}
+
+	// 取消订阅前已拿到订阅列表的发布也不能再回调
+	stale := bus.Subscribe(func(event Event) { t.Error("handler called after Unsubscribe returned") })
+	bus.mu.RLock()
+	subscriptions := bus.subscriptions
+	bus.mu.RUnlock()
+	stale.Unsubscribe()
+	for _, subscription := range subscriptions {
+		subscription.deliver(Event{Type: EventError})
+	}
+
+	channel.Unsubscribe()
+	channel.Unsubscribe()
+	if event, ok := <-channel.C; !ok || event.Type != EventError {
+		t.Errorf("buffered event = %v, %v, want error event", event, ok)
+	}
+	if _, ok := <-channel.C; ok {
+		t.Error("channel not closed after Unsubscribe")
+	}
+	filtered.Unsubscribe()
+	if bus.hasSubscribers() {
+		t.Error("bus still has subscribers")
+	}
+}
+
+func TestErrorCode(t *testing.T) {
+	tests := []struct {
+		name   string
+		record CommandRecord
+		want   string
+	}{
+		{"wda error", CommandRecord{StatusCode: 404, Response: []byte(`{"value":{"error":"no such element"}}`)}, "no such element"},
+		{"http status", CommandRecord{StatusCode: 502, Response: []byte(`bad gateway`)}, ErrorCodeHttp},
+		{"transport", CommandRecord{}, ErrorCodeTransport},
+	}
+	for _, tt := range tests {
+		if got := ErrorCode(&tt.record); got != tt.want {
+			t.Errorf("%s: ErrorCode() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDeviceName(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"http://127.0.0.1:8100", "127.0.0.1:8100"},
+		{"http://iphone.local:8100/", "iphone.local:8100"},
+		{"not a url", "not a url"},
+	}
+	for _, tt := range tests {
+		if got := DeviceName(tt.url); got != tt.want {
+			t.Errorf("DeviceName(%q) = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package WdaGo
 
 import (
 	"fmt"
+	"net/http"
 	"strconv"
 	"strings"
 
@@ -43,6 +44,24 @@ func GetNumFromValueInterface(data map[string]interface{}, key string) int64 {
 	return 0
 }
 
+func GetFloatFromValueInterface(data map[string]interface{}, key string) float64 {
+	if val, ok := data[key]; ok && val != nil {
+		switch v := val.(type) {
+		case float64:
+			return v
+		case int64:
+			return float64(v)
+		case int:
+			return float64(v)
+		case string:
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				return f
+			}
+		}
+	}
+	return 0
+}
+
 func GetBoolFromValueInterface(data map[string]interface{}, key string) bool {
 	if val, ok := data[key]; ok && val != nil {
 		switch v := val.(type) {
@@ -66,3 +85,16 @@ func JudgeResponseCorrect(body []byte, sessionId string) bool {
 		return false
 	}
 }
+
+// JudgeResponseError 解析wda返回的错误信息，value中包含error或者value为false时返回错误，否则返回nil
+func JudgeResponseError(body []byte) error {
+	value := gjson.Get(string(body), "value")
+
+	if value.Get("error").Exists() {
+		return newWdaError(fmt.Sprintf(" %v: %v ", value.Get("error").String(), value.Get("message").String()), http.StatusOK, body)
+	}
+	if value.Type == gjson.False {
+		return fmt.Errorf(" Wda returned false ")
+	}
+	return nil
+}
@@ -0,0 +1,379 @@
+// Package gateway 将多个设备的wda通过一个http服务提供给其他语言使用
+//
+// 转发兼容wda的请求 /devices/{udid}/... -> {wda}/...，如 /devices/abc/session/xxx/wda/tap，
+// 已被预约的设备只有持有者可以使用，请求需要带上 X-Reservation-Id，
+// 预约期间通过网关创建的wda session在取消预约或预约过期时删除；
+// 配置了Tokens时所有请求需要带上 Authorization: Bearer <token>
+//
+//	GET    /devices                     设备列表
+//	GET    /devices/{udid}              设备详情和wda状态
+//	POST   /reservations                预约设备 {"udid":"", "owner":"", "ttl":"10m"}，udid为空时预约任意空闲设备
+//	GET    /reservations                预约列表
+//	POST   /reservations/{id}/renew     延长预约 {"ttl":"10m"}
+//	DELETE /reservations/{id}           取消预约
+//	ANY    /devices/{udid}/{path...}    转发到设备的wda
+package gateway
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	log "github.com/Ning9527fff/MyLog"
+	"github.com/tidwall/gjson"
+	"gopkg.in/yaml.v3"
+)
+
+// ReservationHeader 转发请求中的预约id
+const ReservationHeader = "X-Reservation-Id"
+
+// DefaultQueueTimeout 默认的排队等待时间
+const DefaultQueueTimeout = 30 * time.Second
+
+// Config 网关配置
+//
+//	Tokens             允许访问的token，为空时不校验
+//	RequireReservation 为true时未预约的设备不能转发请求
+//	ReservationTTL     默认的预约时长，转发请求时自动延长
+//	QueueTimeout       设备并发数已满时排队等待的时间，超时返回429
+type Config struct {
+	Devices            []DeviceConfig `yaml:"devices" json:"devices"`
+	Tokens             []string       `yaml:"tokens,omitempty" json:"tokens,omitempty"`
+	RequireReservation bool           `yaml:"requireReservation,omitempty" json:"requireReservation,omitempty"`
+	ReservationTTL     time.Duration  `yaml:"reservationTTL,omitempty" json:"reservationTTL,omitempty"`
+	QueueTimeout       time.Duration  `yaml:"queueTimeout,omitempty" json:"queueTimeout,omitempty"`
+}
+
+// Server 网关服务，实现http.Handler
+type Server struct {
+	config Config
+	pool   *Pool
+	mux    *http.ServeMux
+}
+
+// deviceView 设备列表中的设备信息
+type deviceView struct {
+	DeviceConfig
+	InFlight    int64               `json:"inFlight"`
+	Reservation *Reservation        `json:"reservation,omitempty"`
+	Status      *deviceStatusResult `json:"status,omitempty"`
+}
+
+type deviceStatusResult struct {
+	Ready bool   `json:"ready"`
+	Error string `json:"error,omitempty"`
+}
+
+type reserveRequest struct {
+	Udid  string `json:"udid"`
+	Owner string `json:"owner"`
+	TTL   string `json:"ttl"`
+}
+
+// LoadConfig 读取yaml配置
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf(" Read gateway config failed :%v", err)
+	}
+
+	var config Config
+	if err = yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf(" Parse gateway config failed :%v", err)
+	}
+	return &config, nil
+}
+
+// NewServer 创建网关服务
+func NewServer(config Config) (*Server, error) {
+	if config.ReservationTTL <= 0 {
+		config.ReservationTTL = DefaultReservationTTL
+	}
+	if config.QueueTimeout <= 0 {
+		config.QueueTimeout = DefaultQueueTimeout
+	}
+	pool, err := NewPool(config.Devices)
+	if err != nil {
+		return nil, err
+	}
+
+	server := &Server{config: config, pool: pool, mux: http.NewServeMux()}
+	server.mux.HandleFunc("GET /devices", server.listDevices)
+	server.mux.HandleFunc("GET /devices/{udid}", server.getDevice)
+	server.mux.HandleFunc("POST /reservations", server.reserve)
+	server.mux.HandleFunc("GET /reservations", server.listReservations)
+	server.mux.HandleFunc("POST /reservations/{id}/renew", server.renew)
+	server.mux.HandleFunc("DELETE /reservations/{id}", server.release)
+	server.mux.HandleFunc("/devices/{udid}/{path...}", server.forward)
+	return server, nil
+}
+
+// Pool 网关使用的设备池
+func (server *Server) Pool() *Pool {
+	return server.pool
+}
+
+// ServeHTTP 校验token并记录请求日志
+func (server *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	started := time.Now()
+	recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+	defer func() {
+		log.InfoF("%v %v %d %v %v", r.Method, r.URL.Path, recorder.status, time.Since(started).Round(time.Millisecond), r.RemoteAddr)
+	}()
+
+	if !server.authorized(r) {
+		writeError(recorder, http.StatusUnauthorized, errors.New("invalid or missing token"))
+		return
+	}
+	server.mux.ServeHTTP(recorder, r)
+}
+
+func (server *Server) authorized(r *http.Request) bool {
+	if len(server.config.Tokens) == 0 {
+		return true
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return false
+	}
+	for _, allowed := range server.config.Tokens {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(allowed)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+func (server *Server) listDevices(w http.ResponseWriter, r *http.Request) {
+	var views []deviceView
+	for _, device := range server.pool.Devices() {
+		views = append(views, server.view(device))
+	}
+	if views == nil {
+		views = []deviceView{}
+	}
+	writeJson(w, http.StatusOK, views)
+}
+
+func (server *Server) getDevice(w http.ResponseWriter, r *http.Request) {
+	device, err := server.pool.Device(r.PathValue("udid"))
+	if err != nil {
+		writeError(w, statusOf(err), err)
+		return
+	}
+
+	view := server.view(device)
+	status, err := device.Session().GetStatus()
+	if err != nil {
+		view.Status = &deviceStatusResult{Error: strings.TrimSpace(err.Error())}
+	} else {
+		view.Status = &deviceStatusResult{Ready: status.IsReady}
+	}
+	writeJson(w, http.StatusOK, view)
+}
+
+func (server *Server) view(device *Device) deviceView {
+	return deviceView{
+		DeviceConfig: device.Config,
+		InFlight:     device.InFlight(),
+		Reservation:  server.pool.Reservation(device.Config.Udid),
+	}
+}
+
+func (server *Server) reserve(w http.ResponseWriter, r *http.Request) {
+	var request reserveRequest
+	if err := readJson(r, &request); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	ttl, err := parseTTL(request.TTL, server.config.ReservationTTL)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	reservation, err := server.pool.Reserve(request.Udid, request.Owner, ttl)
+	if err != nil {
+		writeError(w, statusOf(err), err)
+		return
+	}
+	writeJson(w, http.StatusCreated, reservation)
+}
+
+func (server *Server) listReservations(w http.ResponseWriter, r *http.Request) {
+	writeJson(w, http.StatusOK, server.pool.Reservations())
+}
+
+func (server *Server) renew(w http.ResponseWriter, r *http.Request) {
+	var request reserveRequest
+	if err := readJson(r, &request); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	ttl, err := parseTTL(request.TTL, 0)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	reservation, err := server.pool.Renew(r.PathValue("id"), ttl)
+	if err != nil {
+		writeError(w, statusOf(err), err)
+		return
+	}
+	writeJson(w, http.StatusOK, reservation)
+}
+
+func (server *Server) release(w http.ResponseWriter, r *http.Request) {
+	if err := server.pool.Release(r.PathValue("id")); err != nil {
+		writeError(w, statusOf(err), err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// forward 检查预约并占用设备并发数后，通过设备的WdaSession转发请求
+func (server *Server) forward(w http.ResponseWriter, r *http.Request) {
+	udid := r.PathValue("udid")
+	device, err := server.pool.Device(udid)
+	if err != nil {
+		writeError(w, statusOf(err), err)
+		return
+	}
+	id := r.Header.Get(ReservationHeader)
+	reserved, err := server.pool.check(udid, id, server.config.RequireReservation)
+	if err != nil {
+		writeError(w, statusOf(err), err)
+		return
+	}
+
+	payload, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if len(payload) == 0 {
+		payload = nil
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), server.config.QueueTimeout)
+	defer cancel()
+	if err = device.Acquire(ctx); err != nil {
+		writeError(w, http.StatusTooManyRequests, fmt.Errorf("device %v is busy: %v", udid, err))
+		return
+	}
+	defer device.Release()
+
+	// 使用转义后的路径，路径中的%2F等不会被解码后再转发
+	path := forwardPath(r.URL.EscapedPath())
+	if r.URL.RawQuery != "" {
+		path += "?" + r.URL.RawQuery
+	}
+	status, body, err := device.Session().ForwardContext(r.Context(), r.Method, path, payload)
+	if err != nil {
+		// 与wda的错误格式一致，便于wda客户端解析
+		writeJson(w, http.StatusBadGateway, map[string]interface{}{
+			"value": map[string]string{"error": "unknown error", "message": strings.TrimSpace(err.Error())},
+		})
+		return
+	}
+	if reserved && status < 400 {
+		server.trackSession(device, id, r.Method, path, body)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(body)
+}
+
+// forwardPath 去掉 /devices/{udid} 前缀后转发到wda的路径
+func forwardPath(escapedPath string) string {
+	rest := strings.TrimPrefix(escapedPath, "/devices/")
+	if _, path, ok := strings.Cut(rest, "/"); ok {
+		return "/" + path
+	}
+	return "/"
+}
+
+// trackSession 记录预约期间创建和删除的session
+func (server *Server) trackSession(device *Device, id, method, path string, body []byte) {
+	path, _, _ = strings.Cut(path, "?")
+	switch {
+	case method == http.MethodPost && path == "/session":
+		if sessionId := gjson.GetBytes(body, "value.sessionId").String(); sessionId != "" {
+			server.pool.sessionCreated(device, id, sessionId)
+		}
+	case method == http.MethodDelete && strings.HasPrefix(path, "/session/"):
+		sessionId, err := url.PathUnescape(strings.TrimPrefix(path, "/session/"))
+		if err == nil && !strings.Contains(sessionId, "/") {
+			server.pool.sessionDeleted(id, sessionId)
+		}
+	}
+}
+
+// statusOf 设备池错误对应的http状态码
+func statusOf(err error) int {
+	switch {
+	case errors.Is(err, ErrDeviceNotFound), errors.Is(err, ErrReservationNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrDeviceReserved):
+		return http.StatusLocked
+	case errors.Is(err, ErrNoDeviceAvailable):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, ErrReservationRequired):
+		return http.StatusForbidden
+	}
+	return http.StatusInternalServerError
+}
+
+func parseTTL(value string, fallback time.Duration) (time.Duration, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	ttl, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid ttl %q: %v", value, err)
+	}
+	return ttl, nil
+}
+
+// readJson 解析请求体，请求体为空时保持零值
+func readJson(r *http.Request, out interface{}) error {
+	data, err := io.ReadAll(r.Body)
+	if err != nil || len(data) == 0 {
+		return err
+	}
+	if err = json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("invalid json body: %v", err)
+	}
+	return nil
+}
+
+func writeJson(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJson(w, status, map[string]string{"error": strings.TrimSpace(err.Error())})
+}
+
+// statusRecorder 记录响应状态码用于请求日志
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (recorder *statusRecorder) WriteHeader(status int) {
+	recorder.status = status
+	recorder.ResponseWriter.WriteHeader(status)
+}
@@ -0,0 +1,241 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeWda 记录收到的请求，POST /session 返回新的session
+type fakeWda struct {
+	mu       sync.Mutex
+	requests []string
+	created  int
+	release  chan struct{}
+}
+
+func (fake *fakeWda) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	request := r.Method + " " + r.URL.EscapedPath()
+	if r.URL.RawQuery != "" {
+		request += "?" + r.URL.RawQuery
+	}
+	fake.mu.Lock()
+	fake.requests = append(fake.requests, request)
+	fake.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case r.URL.Path == "/slow":
+		select {
+		case <-fake.release:
+		case <-r.Context().Done():
+		}
+		w.Write([]byte(`{"value":null}`))
+	case r.Method == http.MethodPost && r.URL.Path == "/session":
+		fake.mu.Lock()
+		fake.created++
+		sessionId := "session-" + strconv.Itoa(fake.created)
+		fake.mu.Unlock()
+		w.Write([]byte(`{"value":{"sessionId":"` + sessionId + `","capabilities":{}},"sessionId":"` + sessionId + `"}`))
+	default:
+		w.Write([]byte(`{"value":null}`))
+	}
+}
+
+func (fake *fakeWda) received() []string {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	return append([]string(nil), fake.requests...)
+}
+
+// waitReceived 等待wda收到request，过期预约的session在后台删除
+func (fake *fakeWda) waitReceived(t *testing.T, request string) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		for _, item := range fake.received() {
+			if item == request {
+				return
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("wda did not receive %q, got %v", request, fake.received())
+}
+
+func newTestGateway(t *testing.T, config Config) (*fakeWda, *httptest.Server) {
+	t.Helper()
+	fake := &fakeWda{release: make(chan struct{})}
+	wda := httptest.NewServer(fake)
+	t.Cleanup(wda.Close)
+	t.Cleanup(func() { close(fake.release) })
+
+	config.Devices = []DeviceConfig{{Udid: "abc", Url: wda.URL, MaxConcurrent: 2}}
+	server, err := NewServer(config)
+	if err != nil {
+		t.Fatalf("NewServer() error = %v", err)
+	}
+	gateway := httptest.NewServer(server)
+	t.Cleanup(gateway.Close)
+	return fake, gateway
+}
+
+func doRequest(t *testing.T, method, url, reservation, body string) *http.Response {
+	t.Helper()
+	request, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("NewRequest() error = %v", err)
+	}
+	if reservation != "" {
+		request.Header.Set(ReservationHeader, reservation)
+	}
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatalf("%v %v error = %v", method, url, err)
+	}
+	response.Body.Close()
+	return response
+}
+
+func reserve(t *testing.T, gateway *httptest.Server, ttl string) *Reservation {
+	t.Helper()
+	response, err := http.Post(gateway.URL+"/reservations", "application/json", strings.NewReader(`{"udid":"abc","ttl":"`+ttl+`"}`))
+	if err != nil {
+		t.Fatalf("reserve error = %v", err)
+	}
+	defer response.Body.Close()
+	var reservation Reservation
+	if err = json.NewDecoder(response.Body).Decode(&reservation); err != nil || response.StatusCode != http.StatusCreated {
+		t.Fatalf("reserve = %v, %v", response.Status, err)
+	}
+	return &reservation
+}
+
+func TestForwardPath(t *testing.T) {
+	fake, gateway := newTestGateway(t, Config{})
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		want   string
+	}{
+		{"status", http.MethodGet, "/devices/abc/status", "GET /status"},
+		{"query", http.MethodGet, "/devices/abc/source?format=json&scope=a%26b", "GET /source?format=json&scope=a%26b"},
+		{"escaped slash", http.MethodPost, "/devices/abc/session/s%2F1/element/a%20b/click", "POST /session/s%2F1/element/a%20b/click"},
+		{"root", http.MethodGet, "/devices/abc/", "GET /"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := doRequest(t, tt.method, gateway.URL+tt.path, "", "")
+			if response.StatusCode != http.StatusOK {
+				t.Fatalf("status = %v", response.Status)
+			}
+			received := fake.received()
+			if got := received[len(received)-1]; got != tt.want {
+				t.Errorf("wda received %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestForwardReservation(t *testing.T) {
+	_, gateway := newTestGateway(t, Config{RequireReservation: true})
+
+	if response := doRequest(t, http.MethodGet, gateway.URL+"/devices/abc/status", "", ""); response.StatusCode != http.StatusForbidden {
+		t.Errorf("without reservation status = %v, want 403", response.Status)
+	}
+	reservation := reserve(t, gateway, "1m")
+	if response := doRequest(t, http.MethodGet, gateway.URL+"/devices/abc/status", "other", ""); response.StatusCode != http.StatusLocked {
+		t.Errorf("other reservation status = %v, want 423", response.Status)
+	}
+	if response := doRequest(t, http.MethodGet, gateway.URL+"/devices/abc/status", reservation.Id, ""); response.StatusCode != http.StatusOK {
+		t.Errorf("holder status = %v, want 200", response.Status)
+	}
+	if response := doRequest(t, http.MethodGet, gateway.URL+"/devices/xyz/status", reservation.Id, ""); response.StatusCode != http.StatusNotFound {
+		t.Errorf("unknown device status = %v, want 404", response.Status)
+	}
+}
+
+func TestForwardCanceled(t *testing.T) {
+	fake, gateway := newTestGateway(t, Config{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	request, _ := http.NewRequestWithContext(ctx, http.MethodGet, gateway.URL+"/devices/abc/slow", nil)
+	if _, err := http.DefaultClient.Do(request); err == nil {
+		t.Fatal("slow request: want error")
+	}
+
+	// 客户端断开后转发的请求被取消，设备的并发数释放
+	pool := gateway.Config.Handler.(*Server).Pool()
+	device, _ := pool.Device("abc")
+	deadline := time.Now().Add(2 * time.Second)
+	for device.InFlight() != 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := device.InFlight(); got != 0 {
+		t.Errorf("InFlight() = %v after client canceled, want 0; wda received %v", got, fake.received())
+	}
+}
+
+func TestReservationSessionsDeleted(t *testing.T) {
+	t.Run("release", func(t *testing.T) {
+		fake, gateway := newTestGateway(t, Config{})
+		reservation := reserve(t, gateway, "1m")
+
+		doRequest(t, http.MethodPost, gateway.URL+"/devices/abc/session", reservation.Id, `{"capabilities":{}}`)
+		doRequest(t, http.MethodPost, gateway.URL+"/devices/abc/session", reservation.Id, `{"capabilities":{}}`)
+		// 持有者自己删除的session不会再次删除
+		doRequest(t, http.MethodDelete, gateway.URL+"/devices/abc/session/session-1", reservation.Id, "")
+
+		pool := gateway.Config.Handler.(*Server).Pool()
+		if got := pool.Reservation("abc").Sessions; len(got) != 1 || got[0] != "session-2" {
+			t.Fatalf("Sessions = %v, want [session-2]", got)
+		}
+		if response := doRequest(t, http.MethodDelete, gateway.URL+"/reservations/"+reservation.Id, "", ""); response.StatusCode != http.StatusNoContent {
+			t.Fatalf("release status = %v", response.Status)
+		}
+
+		deleted := 0
+		for _, request := range fake.received() {
+			if strings.HasPrefix(request, "DELETE /session/") {
+				deleted++
+			}
+		}
+		if deleted != 2 {
+			t.Errorf("wda received %d session deletes, want 2: %v", deleted, fake.received())
+		}
+		fake.waitReceived(t, "DELETE /session/session-2")
+	})
+
+	t.Run("expiry", func(t *testing.T) {
+		fake, gateway := newTestGateway(t, Config{})
+		reservation := reserve(t, gateway, "100ms")
+
+		doRequest(t, http.MethodPost, gateway.URL+"/devices/abc/session", reservation.Id, `{"capabilities":{}}`)
+		time.Sleep(150 * time.Millisecond)
+		// 过期的预约在下次访问设备池时删除
+		doRequest(t, http.MethodGet, gateway.URL+"/reservations", "", "")
+		fake.waitReceived(t, "DELETE /session/session-1")
+	})
+
+	t.Run("not reserved", func(t *testing.T) {
+		fake, gateway := newTestGateway(t, Config{})
+
+		doRequest(t, http.MethodPost, gateway.URL+"/devices/abc/session", "", `{"capabilities":{}}`)
+		reservation := reserve(t, gateway, "1m")
+		doRequest(t, http.MethodDelete, gateway.URL+"/reservations/"+reservation.Id, "", "")
+		for _, request := range fake.received() {
+			if strings.HasPrefix(request, "DELETE") {
+				t.Errorf("session created without reservation was deleted: %v", fake.received())
+			}
+		}
+	})
+}
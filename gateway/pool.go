@@ -0,0 +1,344 @@
+package gateway
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	log "github.com/Ning9527fff/MyLog"
+	WdaGo "github.com/Ning9527fff/WdaGo"
+)
+
+const (
+	DefaultMaxConcurrent  = 1
+	DefaultReservationTTL = 10 * time.Minute
+)
+
+var (
+	ErrDeviceNotFound      = errors.New("device not found")
+	ErrDeviceReserved      = errors.New("device is reserved")
+	ErrNoDeviceAvailable   = errors.New("no device available")
+	ErrReservationNotFound = errors.New("reservation not found")
+	ErrReservationRequired = errors.New("device must be reserved before use")
+)
+
+// DeviceConfig 设备配置
+//
+//	Udid          设备标识，转发地址为 /devices/{udid}/...
+//	Url           wda地址，如 http://127.0.0.1:8100
+//	MaxConcurrent 同时转发到该设备的请求数，超过时排队
+type DeviceConfig struct {
+	Udid          string `yaml:"udid" json:"udid"`
+	Name          string `yaml:"name,omitempty" json:"name,omitempty"`
+	Url           string `yaml:"url" json:"url"`
+	MaxConcurrent int    `yaml:"maxConcurrent,omitempty" json:"maxConcurrent,omitempty"`
+}
+
+// Device 设备池中的一个设备
+type Device struct {
+	Config DeviceConfig
+
+	session  *WdaGo.WdaSession
+	slots    chan struct{}
+	inFlight atomic.Int64
+}
+
+// Reservation 设备预约，持有者转发请求时需要在X-Reservation-Id中带上Id
+//
+// Sessions为预约期间通过网关创建且未删除的wda session，取消预约或者预约过期时删除
+type Reservation struct {
+	Id       string    `json:"id"`
+	Udid     string    `json:"udid"`
+	Owner    string    `json:"owner,omitempty"`
+	Created  time.Time `json:"created"`
+	Expires  time.Time `json:"expires"`
+	Sessions []string  `json:"sessions,omitempty"`
+
+	ttl time.Duration
+}
+
+// Pool 设备池，管理设备预约和每个设备的并发数
+type Pool struct {
+	mu           sync.Mutex
+	devices      map[string]*Device
+	reservations map[string]*Reservation
+}
+
+// NewPool 根据设备配置创建设备池
+func NewPool(configs []DeviceConfig) (*Pool, error) {
+	pool := &Pool{
+		devices:      make(map[string]*Device),
+		reservations: make(map[string]*Reservation),
+	}
+	for _, config := range configs {
+		if config.Udid == "" || config.Url == "" {
+			return nil, fmt.Errorf(" Device config needs udid and url: %+v", config)
+		}
+		if _, ok := pool.devices[config.Udid]; ok {
+			return nil, fmt.Errorf(" Duplicate device udid %v", config.Udid)
+		}
+		if config.MaxConcurrent <= 0 {
+			config.MaxConcurrent = DefaultMaxConcurrent
+		}
+		pool.devices[config.Udid] = &Device{
+			Config:  config,
+			session: WdaGo.GetWdaSession(config.Url),
+			slots:   make(chan struct{}, config.MaxConcurrent),
+		}
+	}
+	return pool, nil
+}
+
+// Session 设备的WdaSession，转发的请求都通过该session发送
+func (device *Device) Session() *WdaGo.WdaSession {
+	return device.session
+}
+
+// InFlight 正在转发的请求数
+func (device *Device) InFlight() int64 {
+	return device.inFlight.Load()
+}
+
+// Acquire 占用一个并发数，ctx结束时返回错误，成功后需要调用Release
+func (device *Device) Acquire(ctx context.Context) error {
+	select {
+	case device.slots <- struct{}{}:
+		device.inFlight.Add(1)
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Release 释放Acquire占用的并发数
+func (device *Device) Release() {
+	device.inFlight.Add(-1)
+	<-device.slots
+}
+
+// Device 按udid获取设备
+func (pool *Pool) Device(udid string) (*Device, error) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	device, ok := pool.devices[udid]
+	if !ok {
+		return nil, ErrDeviceNotFound
+	}
+	return device, nil
+}
+
+// Devices 所有设备，按udid排序
+func (pool *Pool) Devices() []*Device {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	devices := make([]*Device, 0, len(pool.devices))
+	for _, device := range pool.devices {
+		devices = append(devices, device)
+	}
+	sort.Slice(devices, func(i, j int) bool { return devices[i].Config.Udid < devices[j].Config.Udid })
+	return devices
+}
+
+// Reserve 预约设备，udid为空时预约任意空闲设备，ttl<=0时使用DefaultReservationTTL
+func (pool *Pool) Reserve(udid, owner string, ttl time.Duration) (*Reservation, error) {
+	if ttl <= 0 {
+		ttl = DefaultReservationTTL
+	}
+
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	pool.expire()
+
+	if udid == "" {
+		for _, device := range pool.devices {
+			if pool.reservationOf(device.Config.Udid) == nil && (udid == "" || device.Config.Udid < udid) {
+				udid = device.Config.Udid
+			}
+		}
+		if udid == "" {
+			return nil, ErrNoDeviceAvailable
+		}
+	} else if _, ok := pool.devices[udid]; !ok {
+		return nil, ErrDeviceNotFound
+	} else if pool.reservationOf(udid) != nil {
+		return nil, ErrDeviceReserved
+	}
+
+	now := time.Now()
+	reservation := &Reservation{
+		Id:      newReservationId(),
+		Udid:    udid,
+		Owner:   owner,
+		ttl:     ttl,
+		Created: now,
+		Expires: now.Add(ttl),
+	}
+	pool.reservations[reservation.Id] = reservation
+	return reservation.copy(), nil
+}
+
+// Renew 延长预约，ttl<=0时使用预约时的时长
+func (pool *Pool) Renew(id string, ttl time.Duration) (*Reservation, error) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	pool.expire()
+
+	reservation, ok := pool.reservations[id]
+	if !ok {
+		return nil, ErrReservationNotFound
+	}
+	if ttl > 0 {
+		reservation.ttl = ttl
+	}
+	reservation.Expires = time.Now().Add(reservation.ttl)
+	return reservation.copy(), nil
+}
+
+// Release 取消预约，并删除预约期间创建的wda session，删除失败时只记录日志
+func (pool *Pool) Release(id string) error {
+	pool.mu.Lock()
+	reservation, ok := pool.reservations[id]
+	if !ok {
+		pool.mu.Unlock()
+		return ErrReservationNotFound
+	}
+	delete(pool.reservations, id)
+	device := pool.devices[reservation.Udid]
+	pool.mu.Unlock()
+
+	device.deleteSessions(reservation.Sessions)
+	return nil
+}
+
+// Reservations 未过期的预约，按udid排序
+func (pool *Pool) Reservations() []Reservation {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	pool.expire()
+
+	reservations := make([]Reservation, 0, len(pool.reservations))
+	for _, reservation := range pool.reservations {
+		reservations = append(reservations, *reservation.copy())
+	}
+	sort.Slice(reservations, func(i, j int) bool { return reservations[i].Udid < reservations[j].Udid })
+	return reservations
+}
+
+// Reservation 设备当前的预约，没有预约时返回nil
+func (pool *Pool) Reservation(udid string) *Reservation {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	pool.expire()
+
+	if reservation := pool.reservationOf(udid); reservation != nil {
+		return reservation.copy()
+	}
+	return nil
+}
+
+// check 检查请求是否可以使用设备：设备已被预约时id需要一致，并延长预约，返回true；
+// requireReservation为true时未预约的设备不能使用
+func (pool *Pool) check(udid, id string, requireReservation bool) (bool, error) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	pool.expire()
+
+	reservation := pool.reservationOf(udid)
+	if reservation == nil {
+		if requireReservation {
+			return false, ErrReservationRequired
+		}
+		return false, nil
+	}
+	if reservation.Id != id {
+		return false, ErrDeviceReserved
+	}
+	reservation.Expires = time.Now().Add(reservation.ttl)
+	return true, nil
+}
+
+// sessionCreated 记录预约期间创建的session，预约在请求过程中已取消或过期时直接删除该session
+func (pool *Pool) sessionCreated(device *Device, id, sessionId string) {
+	pool.mu.Lock()
+	reservation, ok := pool.reservations[id]
+	if ok {
+		reservation.Sessions = append(reservation.Sessions, sessionId)
+	}
+	pool.mu.Unlock()
+
+	if !ok {
+		device.deleteSessions([]string{sessionId})
+	}
+}
+
+// sessionDeleted 预约的持有者自己删除了session，不再需要在取消预约时删除
+func (pool *Pool) sessionDeleted(id, sessionId string) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	reservation, ok := pool.reservations[id]
+	if !ok {
+		return
+	}
+	for i, item := range reservation.Sessions {
+		if item == sessionId {
+			reservation.Sessions = append(reservation.Sessions[:i:i], reservation.Sessions[i+1:]...)
+			return
+		}
+	}
+}
+
+func (pool *Pool) reservationOf(udid string) *Reservation {
+	for _, reservation := range pool.reservations {
+		if reservation.Udid == udid {
+			return reservation
+		}
+	}
+	return nil
+}
+
+// expire 删除过期的预约，调用时需要持有锁，预约期间创建的session在后台删除
+func (pool *Pool) expire() {
+	now := time.Now()
+	for id, reservation := range pool.reservations {
+		if now.After(reservation.Expires) {
+			delete(pool.reservations, id)
+			if len(reservation.Sessions) > 0 {
+				go pool.devices[reservation.Udid].deleteSessions(reservation.Sessions)
+			}
+		}
+	}
+}
+
+// deleteSessions 删除wda session，删除失败时只记录日志
+func (device *Device) deleteSessions(sessions []string) {
+	for _, sessionId := range sessions {
+		status, body, err := device.session.Forward(http.MethodDelete, "/session/"+url.PathEscape(sessionId), nil)
+		if err == nil && status >= 400 {
+			err = fmt.Errorf("status %d: %s", status, body)
+		}
+		if err != nil {
+			log.ErrorF("Delete session %v of device %v failed: %v", sessionId, device.Config.Udid, err)
+		}
+	}
+}
+
+// copy 预约的副本，Sessions不与设备池共享
+func (reservation *Reservation) copy() *Reservation {
+	copied := *reservation
+	copied.Sessions = append([]string(nil), reservation.Sessions...)
+	return &copied
+}
+
+func newReservationId() string {
+	data := make([]byte, 16)
+	rand.Read(data)
+	return hex.EncodeToString(data)
+}
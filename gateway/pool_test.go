@@ -0,0 +1,164 @@
+package gateway
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func newTestPool(t *testing.T, udids ...string) *Pool {
+	t.Helper()
+	var configs []DeviceConfig
+	for _, udid := range udids {
+		configs = append(configs, DeviceConfig{Udid: udid, Url: "http://127.0.0.1:1"})
+	}
+	pool, err := NewPool(configs)
+	if err != nil {
+		t.Fatalf("NewPool() error = %v", err)
+	}
+	return pool
+}
+
+func TestNewPool(t *testing.T) {
+	tests := []struct {
+		name    string
+		configs []DeviceConfig
+		wantErr bool
+	}{
+		{name: "valid", configs: []DeviceConfig{{Udid: "a", Url: "http://a"}, {Udid: "b", Url: "http://b"}}},
+		{name: "missing url", configs: []DeviceConfig{{Udid: "a"}}, wantErr: true},
+		{name: "duplicate udid", configs: []DeviceConfig{{Udid: "a", Url: "http://a"}, {Udid: "a", Url: "http://b"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewPool(tt.configs); (err != nil) != tt.wantErr {
+				t.Errorf("NewPool() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReserve(t *testing.T) {
+	pool := newTestPool(t, "b", "a")
+
+	first, err := pool.Reserve("", "alice", 0)
+	if err != nil || first.Udid != "a" {
+		t.Fatalf("Reserve(any) = %+v, %v, want device a", first, err)
+	}
+	if got := first.Expires.Sub(first.Created); got != DefaultReservationTTL {
+		t.Errorf("default ttl = %v, want %v", got, DefaultReservationTTL)
+	}
+	second, err := pool.Reserve("", "bob", time.Minute)
+	if err != nil || second.Udid != "b" {
+		t.Fatalf("Reserve(any) = %+v, %v, want device b", second, err)
+	}
+
+	tests := []struct {
+		name string
+		udid string
+		want error
+	}{
+		{"no free device", "", ErrNoDeviceAvailable},
+		{"reserved device", "a", ErrDeviceReserved},
+		{"unknown device", "c", ErrDeviceNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := pool.Reserve(tt.udid, "carol", 0); !errors.Is(err, tt.want) {
+				t.Errorf("Reserve(%q) error = %v, want %v", tt.udid, err, tt.want)
+			}
+		})
+	}
+
+	if err = pool.Release(first.Id); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+	if err = pool.Release(first.Id); !errors.Is(err, ErrReservationNotFound) {
+		t.Errorf("second Release() error = %v, want %v", err, ErrReservationNotFound)
+	}
+	if reservation, err := pool.Reserve("a", "carol", 0); err != nil || reservation.Udid != "a" {
+		t.Errorf("Reserve(a) after release = %+v, %v", reservation, err)
+	}
+}
+
+func TestReservationExpiry(t *testing.T) {
+	pool := newTestPool(t, "a")
+
+	reservation, err := pool.Reserve("a", "alice", 50*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Reserve() error = %v", err)
+	}
+	if got := pool.Reservation("a"); got == nil || got.Id != reservation.Id {
+		t.Fatalf("Reservation(a) = %+v, want %v", got, reservation.Id)
+	}
+
+	time.Sleep(80 * time.Millisecond)
+	if got := pool.Reservation("a"); got != nil {
+		t.Errorf("Reservation(a) after ttl = %+v, want nil", got)
+	}
+	if got := pool.Reservations(); len(got) != 0 {
+		t.Errorf("Reservations() after ttl = %+v, want empty", got)
+	}
+	if _, err = pool.Renew(reservation.Id, 0); !errors.Is(err, ErrReservationNotFound) {
+		t.Errorf("Renew() after ttl error = %v, want %v", err, ErrReservationNotFound)
+	}
+	if _, err = pool.Reserve("a", "bob", 0); err != nil {
+		t.Errorf("Reserve() after ttl error = %v", err)
+	}
+}
+
+func TestRenewAndCheckExtendExpiry(t *testing.T) {
+	pool := newTestPool(t, "a")
+
+	reservation, err := pool.Reserve("a", "alice", 100*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Reserve() error = %v", err)
+	}
+	// 每次使用都延长预约，总时长超过ttl后预约仍然有效
+	for i := 0; i < 4; i++ {
+		time.Sleep(40 * time.Millisecond)
+		reserved, err := pool.check("a", reservation.Id, true)
+		if err != nil || !reserved {
+			t.Fatalf("check() #%d = %v, %v, want reserved", i, reserved, err)
+		}
+	}
+
+	renewed, err := pool.Renew(reservation.Id, time.Hour)
+	if err != nil {
+		t.Fatalf("Renew() error = %v", err)
+	}
+	if got := time.Until(renewed.Expires); got < 59*time.Minute {
+		t.Errorf("Renew() expires in %v, want about 1h", got)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	pool := newTestPool(t, "a", "b")
+	reservation, err := pool.Reserve("a", "alice", 0)
+	if err != nil {
+		t.Fatalf("Reserve() error = %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		udid         string
+		id           string
+		require      bool
+		wantReserved bool
+		wantErr      error
+	}{
+		{name: "holder", udid: "a", id: reservation.Id, wantReserved: true},
+		{name: "other client", udid: "a", id: "other", wantErr: ErrDeviceReserved},
+		{name: "no reservation header", udid: "a", wantErr: ErrDeviceReserved},
+		{name: "free device", udid: "b"},
+		{name: "free device requires reservation", udid: "b", require: true, wantErr: ErrReservationRequired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reserved, err := pool.check(tt.udid, tt.id, tt.require)
+			if !errors.Is(err, tt.wantErr) || reserved != tt.wantReserved {
+				t.Errorf("check() = %v, %v, want %v, %v", reserved, err, tt.wantReserved, tt.wantErr)
+			}
+		})
+	}
+}
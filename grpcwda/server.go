@@ -0,0 +1,628 @@
+// Package grpcwda 通过gRPC提供WdaSession的操作，供其他服务远程控制设备
+//
+// 服务定义在 wdapb/wda.proto，wdapb 中包含生成的客户端和服务端代码，
+// 截图可以通过StreamScreenshots持续获取，Events为双向流，客户端发送订阅的事件类型，服务端推送session的事件
+//
+//	server := grpc.NewServer()
+//	wdapb.RegisterWdaServiceServer(server, grpcwda.NewServer(session, grpcwda.ServerOption{}))
+//	server.Serve(listener)
+//
+//	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+//	client := wdapb.NewWdaServiceClient(conn)
+//	status, err := client.GetStatus(ctx, &emptypb.Empty{})
+package grpcwda
+
+//go:generate protoc -I.. --go_out=.. --go_opt=paths=source_relative --go-grpc_out=.. --go-grpc_opt=paths=source_relative ../grpcwda/wdapb/wda.proto
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/Ning9527fff/MyLog"
+	WdaGo "github.com/Ning9527fff/WdaGo"
+	"github.com/Ning9527fff/WdaGo/grpcwda/wdapb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// DefaultScreenshotInterval 截图流默认的截图间隔
+const DefaultScreenshotInterval = time.Second
+
+// ServerOption 服务参数
+//
+//	ScreenshotInterval 截图流请求未指定间隔时使用的间隔
+//	EventBuffer        每个事件流的缓冲大小，客户端接收过慢时丢弃事件
+type ServerOption struct {
+	ScreenshotInterval time.Duration
+	EventBuffer        int
+}
+
+// Server 使用一个WdaSession实现wdapb.WdaServiceServer
+//
+// 所有请求共用同一个session，CreateSession和DeleteSession会影响其他客户端，两者按顺序执行；
+// WdaSession的请求不能随RPC取消，RPC的截止时间用于等待元素，请求失败时RPC已结束的返回对应的状态码
+type Server struct {
+	wdapb.UnimplementedWdaServiceServer
+
+	session *WdaGo.WdaSession
+	option  ServerOption
+
+	// lifecycle 保证创建和删除session时读取和替换的是同一个session id
+	lifecycle sync.Mutex
+}
+
+// NewServer 创建服务
+func NewServer(session *WdaGo.WdaSession, option ServerOption) *Server {
+	if option.ScreenshotInterval <= 0 {
+		option.ScreenshotInterval = DefaultScreenshotInterval
+	}
+	if option.EventBuffer <= 0 {
+		option.EventBuffer = WdaGo.DefaultEventBuffer
+	}
+	return &Server{session: session, option: option}
+}
+
+// GetStatus 获取wda状态
+func (server *Server) GetStatus(ctx context.Context, _ *emptypb.Empty) (*wdapb.Status, error) {
+	phoneStatus, err := server.session.GetStatus()
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return &wdapb.Status{
+		Device:       phoneStatus.Device,
+		DeviceIp:     phoneStatus.DeviceIP,
+		AgentVersion: phoneStatus.AgentVersion,
+		OsName:       phoneStatus.OsName,
+		OsVersion:    phoneStatus.OsVersion,
+		SdkVersion:   phoneStatus.SdkVersion,
+		State:        phoneStatus.State,
+		Ready:        phoneStatus.IsReady,
+	}, nil
+}
+
+// CreateSession 创建session，已有session时先删除，删除失败不影响创建
+func (server *Server) CreateSession(ctx context.Context, request *wdapb.CreateSessionRequest) (*wdapb.Session, error) {
+	server.lifecycle.Lock()
+	defer server.lifecycle.Unlock()
+
+	if previous := server.session.SessionId(); previous != "" {
+		if err := server.session.CloseSession(); err != nil {
+			log.DebugF("Delete previous session %v failed: %v", previous, err)
+		}
+	}
+	if err := server.session.GetSession(request.GetBundleId()); err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	sessionId := server.session.SessionId()
+	if sessionId == "" {
+		return nil, status.Error(codes.Unknown, "wda returned no session id")
+	}
+	return &wdapb.Session{SessionId: sessionId, Valid: true}, nil
+}
+
+// GetSession 当前session，没有session时返回空的session id
+func (server *Server) GetSession(ctx context.Context, _ *emptypb.Empty) (*wdapb.Session, error) {
+	sessionId := server.session.SessionId()
+	if sessionId == "" {
+		return &wdapb.Session{}, nil
+	}
+	valid, err := server.session.CheckSession()
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return &wdapb.Session{SessionId: sessionId, Valid: valid}, nil
+}
+
+// DeleteSession 删除当前session
+func (server *Server) DeleteSession(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+	server.lifecycle.Lock()
+	defer server.lifecycle.Unlock()
+
+	if server.session.SessionId() == "" {
+		return nil, status.Error(codes.FailedPrecondition, "no session")
+	}
+	return empty(ctx, server.session.CloseSession())
+}
+
+// FindElement 查找元素，timeout不超过请求的deadline
+func (server *Server) FindElement(ctx context.Context, request *wdapb.FindElementRequest) (*wdapb.ElementRef, error) {
+	locator, err := toLocator(request.GetLocator())
+	if err != nil {
+		return nil, err
+	}
+
+	timeout := request.GetTimeout().AsDuration()
+	if timeout <= 0 {
+		elementId, err := server.session.FindElement(locator)
+		if err != nil {
+			return nil, toStatus(ctx, err)
+		}
+		return &wdapb.ElementRef{ElementId: elementId}, nil
+	}
+
+	// 等待元素时RPC取消或者到达截止时间立即结束
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	var elementId string
+	err = WdaGo.WaitUntilContext(waitCtx, WdaGo.DefaultPollInterval, func() (bool, error) {
+		elements, err := server.session.FindElements(locator.Using, locator.Value)
+		if err != nil {
+			return false, err
+		}
+		if len(elements) > locator.Index {
+			elementId = elements[locator.Index]
+			return true, nil
+		}
+		return false, nil
+	})
+	if err != nil {
+		return nil, toStatus(ctx, fmt.Errorf(" Wait for element %v failed :%w", locator, err))
+	}
+	return &wdapb.ElementRef{ElementId: elementId}, nil
+}
+
+// FindElements 查找所有匹配的元素，Locator的index不生效
+func (server *Server) FindElements(ctx context.Context, request *wdapb.Locator) (*wdapb.ElementRefs, error) {
+	locator, err := toLocator(request)
+	if err != nil {
+		return nil, err
+	}
+	elements, err := server.session.FindElements(locator.Using, locator.Value)
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return &wdapb.ElementRefs{ElementIds: elements}, nil
+}
+
+// GetElement 获取元素文本、状态和位置
+func (server *Server) GetElement(ctx context.Context, request *wdapb.ElementRef) (*wdapb.ElementInfo, error) {
+	elementId, err := requireElement(request.GetElementId())
+	if err != nil {
+		return nil, err
+	}
+
+	info := &wdapb.ElementInfo{ElementId: elementId}
+	if info.Text, err = server.session.GetElementText(elementId); err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	if info.Displayed, err = server.session.IsElementDisplayed(elementId); err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	if info.Enabled, err = server.session.IsElementEnabled(elementId); err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	rect, err := server.session.GetElementRect(elementId)
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	info.Rect = &wdapb.Rect{X: rect.X, Y: rect.Y, Width: rect.Width, Height: rect.Height}
+	return info, nil
+}
+
+// GetElementAttribute 获取元素属性
+func (server *Server) GetElementAttribute(ctx context.Context, request *wdapb.ElementAttributeRequest) (*wdapb.ElementAttribute, error) {
+	elementId, err := requireElement(request.GetElementId())
+	if err != nil {
+		return nil, err
+	}
+	if request.GetName() == "" {
+		return nil, status.Error(codes.InvalidArgument, "attribute name is required")
+	}
+	value, err := server.session.GetElementAttribute(elementId, request.GetName())
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return &wdapb.ElementAttribute{Value: value}, nil
+}
+
+// Click 点击元素
+func (server *Server) Click(ctx context.Context, request *wdapb.ElementRef) (*emptypb.Empty, error) {
+	elementId, err := requireElement(request.GetElementId())
+	if err != nil {
+		return nil, err
+	}
+	return empty(ctx, server.session.ClickElement(elementId))
+}
+
+// TypeText 输入文本，element_id为空时输入到当前焦点
+func (server *Server) TypeText(ctx context.Context, request *wdapb.TypeTextRequest) (*emptypb.Empty, error) {
+	frequency := int(request.GetFrequency())
+	if request.GetElementId() == "" {
+		return empty(ctx, server.session.SendKeysWithFrequency(request.GetText(), frequency))
+	}
+	return empty(ctx, server.session.TypingTextWithFrequency(request.GetElementId(), request.GetText(), frequency))
+}
+
+// ClearText 清空元素文本
+func (server *Server) ClearText(ctx context.Context, request *wdapb.ElementRef) (*emptypb.Empty, error) {
+	elementId, err := requireElement(request.GetElementId())
+	if err != nil {
+		return nil, err
+	}
+	return empty(ctx, server.session.ClearText(elementId))
+}
+
+// Tap 点击坐标
+func (server *Server) Tap(ctx context.Context, request *wdapb.Point) (*emptypb.Empty, error) {
+	return empty(ctx, server.session.TapWithLocation(WdaGo.ElementLocation{X: request.GetX(), Y: request.GetY()}))
+}
+
+// DoubleTap 双击坐标
+func (server *Server) DoubleTap(ctx context.Context, request *wdapb.Point) (*emptypb.Empty, error) {
+	return empty(ctx, server.session.DoubleTapWithLocation(request.GetX(), request.GetY()))
+}
+
+// TouchAndHold 长按坐标
+func (server *Server) TouchAndHold(ctx context.Context, request *wdapb.TouchAndHoldRequest) (*emptypb.Empty, error) {
+	point := request.GetPoint()
+	return empty(ctx, server.session.TouchAndHoldWithLocation(point.GetX(), point.GetY(), request.GetDuration()))
+}
+
+// Swipe 从一个坐标滑动到另一个坐标
+func (server *Server) Swipe(ctx context.Context, request *wdapb.SwipeRequest) (*emptypb.Empty, error) {
+	from, to := request.GetFrom(), request.GetTo()
+	return empty(ctx, server.session.SwipeWithLocation(from.GetX(), from.GetY(), to.GetX(), to.GetY(), request.GetDuration()))
+}
+
+// PressButton 按下硬件按钮
+func (server *Server) PressButton(ctx context.Context, request *wdapb.PressButtonRequest) (*emptypb.Empty, error) {
+	switch request.GetButton() {
+	case wdapb.Button_BUTTON_VOLUME_UP, wdapb.Button_BUTTON_VOLUME_DOWN, wdapb.Button_BUTTON_HOME:
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unknown button %v", request.GetButton())
+	}
+	// Button的值与VolumeUp、VolumeDown、Home一致
+	return empty(ctx, server.session.PressButton(int(request.GetButton())))
+}
+
+// GetWindowSize 获取当前窗口大小
+func (server *Server) GetWindowSize(ctx context.Context, _ *emptypb.Empty) (*wdapb.WindowSize, error) {
+	size, err := server.session.GetWindowSize()
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return &wdapb.WindowSize{Width: size.Width, Height: size.Height}, nil
+}
+
+// Screenshot 截图
+func (server *Server) Screenshot(ctx context.Context, _ *emptypb.Empty) (*wdapb.ScreenshotFrame, error) {
+	data, err := server.session.ScreenShotData()
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return &wdapb.ScreenshotFrame{Png: data, Time: timestamppb.Now(), Sequence: 1}, nil
+}
+
+// StreamScreenshots 按间隔截图并发送，截图失败时结束
+func (server *Server) StreamScreenshots(request *wdapb.StreamScreenshotsRequest, stream wdapb.WdaService_StreamScreenshotsServer) error {
+	interval := request.GetInterval().AsDuration()
+	if interval <= 0 {
+		interval = server.option.ScreenshotInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	ctx := stream.Context()
+	for sequence := int64(1); ; sequence++ {
+		data, err := server.session.ScreenShotData()
+		if err != nil {
+			return toStatus(ctx, err)
+		}
+		if err = stream.Send(&wdapb.ScreenshotFrame{Png: data, Time: timestamppb.Now(), Sequence: sequence}); err != nil {
+			return err
+		}
+		if request.GetMaxFrames() > 0 && sequence >= request.GetMaxFrames() {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// GetSource 获取页面树
+func (server *Server) GetSource(ctx context.Context, request *wdapb.GetSourceRequest) (*wdapb.Source, error) {
+	source, err := server.session.GetSource(request.GetFormat())
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return &wdapb.Source{Source: source}, nil
+}
+
+// LaunchApp 启动app
+func (server *Server) LaunchApp(ctx context.Context, request *wdapb.LaunchAppRequest) (*emptypb.Empty, error) {
+	bundleId, err := requireBundleId(request.GetBundleId())
+	if err != nil {
+		return nil, err
+	}
+	return empty(ctx, server.session.LaunchAppWithOption(WdaGo.AppLaunchOption{
+		BundleId:                bundleId,
+		Arguments:               request.GetArguments(),
+		Environment:             request.GetEnvironment(),
+		ShouldWaitForQuiescence: request.ShouldWaitForQuiescence,
+	}))
+}
+
+// TerminateApp 关闭app
+func (server *Server) TerminateApp(ctx context.Context, request *wdapb.AppRequest) (*emptypb.Empty, error) {
+	bundleId, err := requireBundleId(request.GetBundleId())
+	if err != nil {
+		return nil, err
+	}
+	return empty(ctx, server.session.TerminateApp(bundleId))
+}
+
+// ActivateApp 将app切换到前台
+func (server *Server) ActivateApp(ctx context.Context, request *wdapb.AppRequest) (*emptypb.Empty, error) {
+	bundleId, err := requireBundleId(request.GetBundleId())
+	if err != nil {
+		return nil, err
+	}
+	return empty(ctx, server.session.ActivateApp(bundleId))
+}
+
+// GetAppState 获取app状态
+func (server *Server) GetAppState(ctx context.Context, request *wdapb.AppRequest) (*wdapb.AppStateResponse, error) {
+	bundleId, err := requireBundleId(request.GetBundleId())
+	if err != nil {
+		return nil, err
+	}
+	state, err := server.session.GetAppState(bundleId)
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	// AppState的值与WdaGo.AppState一致
+	return &wdapb.AppStateResponse{State: wdapb.AppState(state)}, nil
+}
+
+// GetActiveApp 获取前台app
+func (server *Server) GetActiveApp(ctx context.Context, _ *emptypb.Empty) (*wdapb.AppInfo, error) {
+	app, err := server.session.GetActiveAppInfo()
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return &wdapb.AppInfo{BundleId: app.Value.BundleId, Name: app.Value.Name, Pid: int64(app.Value.Pid)}, nil
+}
+
+// ListApps 获取正在运行的app
+func (server *Server) ListApps(ctx context.Context, _ *emptypb.Empty) (*wdapb.AppList, error) {
+	apps, err := server.session.GetAppList()
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	list := &wdapb.AppList{}
+	for _, app := range *apps {
+		list.Apps = append(list.Apps, &wdapb.AppInfo{BundleId: app.BundleId, Pid: app.Pid})
+	}
+	return list, nil
+}
+
+// GetAlert 获取当前弹窗的文本和按钮
+func (server *Server) GetAlert(ctx context.Context, _ *emptypb.Empty) (*wdapb.Alert, error) {
+	text, err := server.session.AlertGet()
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	buttons, err := server.session.AlertButtons()
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return &wdapb.Alert{Text: text, Buttons: buttons}, nil
+}
+
+// AcceptAlert 接受弹窗
+func (server *Server) AcceptAlert(ctx context.Context, request *wdapb.AlertActionRequest) (*emptypb.Empty, error) {
+	return empty(ctx, server.session.AlertAccept(request.GetButton()))
+}
+
+// DismissAlert 取消弹窗
+func (server *Server) DismissAlert(ctx context.Context, request *wdapb.AlertActionRequest) (*emptypb.Empty, error) {
+	return empty(ctx, server.session.AlertDismiss(request.GetButton()))
+}
+
+// Events 推送session的事件，收到请求时替换订阅，客户端关闭发送或者取消时结束
+func (server *Server) Events(stream wdapb.WdaService_EventsServer) error {
+	ctx := stream.Context()
+	requests := make(chan *wdapb.EventsRequest)
+	recvErr := make(chan error, 1)
+	go func() {
+		for {
+			request, err := stream.Recv()
+			if err != nil {
+				recvErr <- err
+				return
+			}
+			select {
+			case requests <- request:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var (
+		subscription       *WdaGo.Subscription
+		events             <-chan WdaGo.Event
+		dropped            int64
+		includeScreenshots bool
+	)
+	defer func() {
+		if subscription != nil {
+			subscription.Unsubscribe()
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case err := <-recvErr:
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		case request := <-requests:
+			if subscription != nil {
+				subscription.Unsubscribe()
+			}
+			var types []WdaGo.EventType
+			for _, eventType := range request.GetTypes() {
+				if eventType != wdapb.EventType_EVENT_TYPE_UNSPECIFIED {
+					// EventType的值与WdaGo.EventType一致
+					types = append(types, WdaGo.EventType(eventType))
+				}
+			}
+			subscription = server.session.Events().SubscribeChan(server.option.EventBuffer, types...)
+			events, dropped, includeScreenshots = subscription.C, 0, request.GetIncludeScreenshots()
+		case event, ok := <-events:
+			if !ok {
+				events = nil
+				continue
+			}
+			if _, ok := wdapb.EventType_name[int32(event.Type)]; !ok {
+				// 方法调用事件用于链路，proto中没有对应的类型，不发送
+				continue
+			}
+			message := toEvent(event, includeScreenshots)
+			if total := subscription.Dropped(); total > dropped {
+				message.Dropped, dropped = total-dropped, total
+			}
+			if err := stream.Send(message); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// toEvent 转换为wdapb.Event，includeScreenshot为false时不携带截图数据
+func toEvent(event WdaGo.Event, includeScreenshot bool) *wdapb.Event {
+	message := &wdapb.Event{
+		Type:      wdapb.EventType(event.Type),
+		Time:      timestamppb.New(event.Time),
+		SessionId: event.SessionId,
+		Method:    event.Method,
+		Endpoint:  event.Endpoint,
+		Alert:     event.Alert,
+		BundleId:  event.BundleId,
+		AppState:  wdapb.AppState(event.AppState),
+	}
+	if event.Command != nil {
+		message.StatusCode = int32(event.Command.StatusCode)
+	}
+	if event.Duration > 0 {
+		message.Duration = durationpb.New(event.Duration)
+	}
+	if includeScreenshot {
+		message.Screenshot = event.Screenshot
+	}
+	if event.Err != nil {
+		message.Error = strings.TrimSpace(event.Err.Error())
+	}
+	return message
+}
+
+func toLocator(locator *wdapb.Locator) (WdaGo.Locator, error) {
+	if locator.GetUsing() == "" || locator.GetValue() == "" {
+		return WdaGo.Locator{}, status.Error(codes.InvalidArgument, "locator using and value are required")
+	}
+	if locator.GetIndex() < 0 {
+		return WdaGo.Locator{}, status.Error(codes.InvalidArgument, "locator index must not be negative")
+	}
+	return WdaGo.Locator{Using: locator.GetUsing(), Value: locator.GetValue(), Index: int(locator.GetIndex())}, nil
+}
+
+func requireElement(elementId string) (string, error) {
+	if elementId == "" {
+		return "", status.Error(codes.InvalidArgument, "element id is required")
+	}
+	return elementId, nil
+}
+
+func requireBundleId(bundleId string) (string, error) {
+	if bundleId == "" {
+		return "", status.Error(codes.InvalidArgument, "bundle id is required")
+	}
+	return bundleId, nil
+}
+
+func empty(ctx context.Context, err error) (*emptypb.Empty, error) {
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// toStatus 按wda返回的错误码转换为gRPC状态码，wda没有返回错误码时按http状态码转换，
+// RPC已取消或者到达截止时间时返回对应的状态码，没有收到wda响应时返回Unavailable
+func toStatus(ctx context.Context, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return status.FromContextError(ctxErr).Err()
+	}
+
+	var wdaErr *WdaGo.WdaError
+	code := codes.Unknown
+	switch {
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, WdaGo.ErrWaitTimeout):
+		code = codes.DeadlineExceeded
+	case errors.Is(err, context.Canceled):
+		code = codes.Canceled
+	case errors.Is(err, WdaGo.ErrTransport):
+		code = codes.Unavailable
+	case errors.Is(err, WdaGo.ErrElementNotFound):
+		code = codes.NotFound
+	case errors.As(err, &wdaErr):
+		code = wdaCode(wdaErr)
+	}
+	return status.Error(code, strings.TrimSpace(err.Error()))
+}
+
+// wdaCodes wda(W3C WebDriver)的错误码对应的gRPC状态码
+var wdaCodes = map[string]codes.Code{
+	"no such element":           codes.NotFound,
+	"no such alert":             codes.NotFound,
+	"no such window":            codes.NotFound,
+	"stale element reference":   codes.NotFound,
+	"invalid session id":        codes.FailedPrecondition,
+	"invalid element state":     codes.FailedPrecondition,
+	"element not interactable":  codes.FailedPrecondition,
+	"element click intercepted": codes.FailedPrecondition,
+	"invalid argument":          codes.InvalidArgument,
+	"invalid selector":          codes.InvalidArgument,
+	"timeout":                   codes.DeadlineExceeded,
+	"script timeout":            codes.DeadlineExceeded,
+	"unknown command":           codes.Unimplemented,
+	"unknown method":            codes.Unimplemented,
+	"unsupported operation":     codes.Unimplemented,
+}
+
+func wdaCode(err *WdaGo.WdaError) codes.Code {
+	if code, ok := wdaCodes[err.Code]; ok {
+		return code
+	}
+	switch err.StatusCode {
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		return codes.Unimplemented
+	case http.StatusRequestTimeout, http.StatusGatewayTimeout:
+		return codes.DeadlineExceeded
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	}
+	return codes.Unknown
+}
@@ -0,0 +1,226 @@
+package grpcwda
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	WdaGo "github.com/Ning9527fff/WdaGo"
+	"github.com/Ning9527fff/WdaGo/grpcwda/wdapb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// fakeWda 按顺序创建session，记录删除的session，查找元素时visible之后返回元素
+type fakeWda struct {
+	mu      sync.Mutex
+	created int
+	deleted []string
+	visible time.Time
+}
+
+func (fake *fakeWda) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", WdaGo.ContentTypeJson)
+	if r.Method == http.MethodDelete {
+		// 删除较慢时并发的创建和删除更容易交错
+		time.Sleep(5 * time.Millisecond)
+	}
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/session":
+		fake.created++
+		sessionId := "session-" + strconv.Itoa(fake.created)
+		w.Write([]byte(`{"value":{"sessionId":"` + sessionId + `","capabilities":{}},"sessionId":"` + sessionId + `"}`))
+	case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/session/"):
+		fake.deleted = append(fake.deleted, strings.TrimPrefix(r.URL.Path, "/session/"))
+		w.Write([]byte(`{"value":null}`))
+	case strings.HasSuffix(r.URL.Path, "/elements"):
+		if fake.visible.IsZero() || time.Now().Before(fake.visible) {
+			w.Write([]byte(`{"value":[]}`))
+			return
+		}
+		w.Write([]byte(`{"value":[{"ELEMENT":"element-1"}]}`))
+	case strings.HasSuffix(r.URL.Path, "/element/gone/click"):
+		// 没有错误码的404
+		w.WriteHeader(http.StatusNotFound)
+	case strings.HasSuffix(r.URL.Path, "/alert/accept"):
+		// 部分wda版本在状态码为200时返回错误
+		w.Write([]byte(`{"value":{"error":"no such alert","message":"no alert open"}}`))
+	case strings.HasSuffix(r.URL.Path, "/text"):
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"value":{"error":"no such element","message":"stale"}}`))
+	default:
+		w.Write([]byte(`{"value":null}`))
+	}
+}
+
+func newTestServer(t *testing.T, fake *fakeWda) (*Server, *WdaGo.WdaSession) {
+	t.Helper()
+	wda := httptest.NewServer(fake)
+	t.Cleanup(wda.Close)
+	session := WdaGo.GetWdaSession(wda.URL)
+	return NewServer(session, ServerOption{}), session
+}
+
+func TestToStatus(t *testing.T) {
+	// 已关闭的wda，GET、POST、DELETE都没有响应
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+	unreachable := WdaGo.GetWdaSession(closed.URL)
+	unreachable.AttachSession("session-1")
+	unreachableServer := NewServer(unreachable, ServerOption{})
+
+	server, session := newTestServer(t, &fakeWda{})
+	session.AttachSession("session-1")
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name string
+		call func() error
+		want codes.Code
+	}{
+		{"GET without response", func() error {
+			_, err := unreachableServer.GetStatus(context.Background(), &emptypb.Empty{})
+			return err
+		}, codes.Unavailable},
+		{"POST without response", func() error {
+			_, err := unreachableServer.Click(context.Background(), &wdapb.ElementRef{ElementId: "element-1"})
+			return err
+		}, codes.Unavailable},
+		{"DELETE without response", func() error {
+			_, err := unreachableServer.DeleteSession(context.Background(), &emptypb.Empty{})
+			return err
+		}, codes.Unavailable},
+		{"no such element", func() error {
+			_, err := server.GetElement(context.Background(), &wdapb.ElementRef{ElementId: "element-1"})
+			return err
+		}, codes.NotFound},
+		{"POST 404 without error code", func() error {
+			_, err := server.Click(context.Background(), &wdapb.ElementRef{ElementId: "gone"})
+			return err
+		}, codes.NotFound},
+		{"error code with status 200", func() error {
+			_, err := server.AcceptAlert(context.Background(), &wdapb.AlertActionRequest{})
+			return err
+		}, codes.NotFound},
+		{"element index out of range", func() error {
+			_, err := server.FindElement(context.Background(), &wdapb.FindElementRequest{
+				Locator: &wdapb.Locator{Using: WdaGo.StrategyId, Value: "login", Index: 1},
+			})
+			return err
+		}, codes.NotFound},
+		{"canceled rpc", func() error {
+			_, err := unreachableServer.GetStatus(canceled, &emptypb.Empty{})
+			return err
+		}, codes.Canceled},
+		{"invalid argument", func() error {
+			_, err := server.Click(context.Background(), &wdapb.ElementRef{})
+			return err
+		}, codes.InvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := status.Code(tt.call()); got != tt.want {
+				t.Errorf("code = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestErrTransport(t *testing.T) {
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+	session := WdaGo.GetWdaSession(closed.URL)
+
+	if _, err := session.GetStatus(); !errors.Is(err, WdaGo.ErrTransport) {
+		t.Errorf("GetStatus() error = %v, want ErrTransport", err)
+	}
+	if _, err := session.FindElements(WdaGo.StrategyId, "login"); !errors.Is(err, WdaGo.ErrTransport) {
+		t.Errorf("FindElements() error = %v, want ErrTransport", err)
+	}
+	if err := session.DeleteSession(); !errors.Is(err, WdaGo.ErrTransport) {
+		t.Errorf("DeleteSession() error = %v, want ErrTransport", err)
+	}
+	if _, _, err := session.Forward(http.MethodPut, "/status", nil); !errors.Is(err, WdaGo.ErrTransport) {
+		t.Errorf("Forward() error = %v, want ErrTransport", err)
+	}
+}
+
+func TestFindElementDeadline(t *testing.T) {
+	fake := &fakeWda{}
+	server, session := newTestServer(t, fake)
+	session.AttachSession("session-1")
+	request := &wdapb.FindElementRequest{
+		Locator: &wdapb.Locator{Using: WdaGo.StrategyId, Value: "login"},
+		Timeout: durationpb.New(10 * time.Second),
+	}
+
+	// RPC的截止时间早于请求的timeout
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	started := time.Now()
+	_, err := server.FindElement(ctx, request)
+	if got := status.Code(err); got != codes.DeadlineExceeded {
+		t.Fatalf("FindElement() code = %v, want %v: %v", got, codes.DeadlineExceeded, err)
+	}
+	if elapsed := time.Since(started); elapsed > 2*time.Second {
+		t.Errorf("FindElement() returned after %v, want about the rpc deadline", elapsed)
+	}
+
+	fake.mu.Lock()
+	fake.visible = time.Now().Add(300 * time.Millisecond)
+	fake.mu.Unlock()
+	element, err := server.FindElement(context.Background(), request)
+	if err != nil || element.GetElementId() != "element-1" {
+		t.Errorf("FindElement() = %v, %v, want element-1", element, err)
+	}
+}
+
+func TestSessionLifecycleConcurrent(t *testing.T) {
+	fake := &fakeWda{}
+	server, session := newTestServer(t, fake)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			server.CreateSession(context.Background(), &wdapb.CreateSessionRequest{})
+		}()
+		go func() {
+			defer wg.Done()
+			server.DeleteSession(context.Background(), &emptypb.Empty{})
+		}()
+	}
+	wg.Wait()
+
+	// 每个session只删除一次，没有删除的session只能是当前的session，不会被替换后丢失
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	remaining := make(map[string]bool)
+	for i := 1; i <= fake.created; i++ {
+		remaining["session-"+strconv.Itoa(i)] = true
+	}
+	for _, sessionId := range fake.deleted {
+		if !remaining[sessionId] {
+			t.Errorf("session %q deleted twice or never created", sessionId)
+		}
+		delete(remaining, sessionId)
+	}
+	delete(remaining, session.SessionId())
+	if len(remaining) > 0 {
+		t.Errorf("sessions %v lost without delete, deleted %v", remaining, fake.deleted)
+	}
+}
@@ -2,20 +2,119 @@ package WdaGo
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	log "github.com/Ning9527fff/MyLog"
+	"github.com/tidwall/gjson"
 )
 
 // HTTPClient HTTP
 type HTTPClient struct {
-	client *http.Client
+	client         *http.Client
+	observers      []CommandObserver
+	startObservers []CommandObserver
+	retryObservers []CommandObserver
+	retry          RetryOption
+	mu             sync.RWMutex
 }
 
+// RetryOption 请求失败时的重试参数，Count为0时不重试
+//
+// 只重试连接失败的请求，wda重启或者端口转发断开时会出现；
+// 其他网络错误只重试GET请求，避免重复执行点击等操作
+type RetryOption struct {
+	Count    int
+	Interval time.Duration
+}
+
+// ErrTransport 请求没有收到wda的响应，如连接失败、超时或者请求被取消，
+// HTTPClient发送请求失败时返回的错误可以用 errors.Is(err, ErrTransport) 判断
+var ErrTransport = errors.New("wda request failed without response")
+
+// transportError 发送请求失败的错误，错误信息与原始错误一致
+type transportError struct {
+	err error
+}
+
+func (e *transportError) Error() string {
+	return e.err.Error()
+}
+
+func (e *transportError) Unwrap() error {
+	return e.err
+}
+
+func (e *transportError) Is(target error) bool {
+	return target == ErrTransport
+}
+
+// WdaError wda返回的错误，StatusCode为http状态码，Code、Message为wda返回的value.error和value.message，
+// 如 no such element；wda返回200但value中包含error时StatusCode为200
+//
+//	var wdaErr *WdaError
+//	if errors.As(err, &wdaErr) && wdaErr.Code == "no such alert" {}
+type WdaError struct {
+	StatusCode int
+	Code       string
+	Message    string
+
+	text string
+}
+
+func (e *WdaError) Error() string {
+	return e.text
+}
+
+// newWdaError 从响应中读取wda的错误码，text为错误信息
+func newWdaError(text string, statusCode int, body []byte) *WdaError {
+	value := gjson.GetBytes(body, "value")
+	return &WdaError{
+		StatusCode: statusCode,
+		Code:       value.Get("error").String(),
+		Message:    value.Get("message").String(),
+		text:       text,
+	}
+}
+
+// commandId 每条命令的id，用于关联开始、重试和完成的回调
+var commandId atomic.Uint64
+
+// CommandRecord 一次wda请求的记录，Payload为请求的json数据
+//
+//	Id             同一条命令开始、重试和完成时相同
+//	Attempt        第几次发送请求，从1开始
+//	Started        本次发送请求的时间，Duration为本次请求的耗时
+//	CommandStarted 第一次发送请求的时间，重试时不变，TotalDuration为包括重试在内的命令耗时
+//	Operation      发送请求的WdaSession或者Element方法调用，直接使用HTTPClient或者转发的请求为空
+type CommandRecord struct {
+	Id         uint64
+	Operation  *Operation
+	Attempt    int
+	Method     string
+	Url        string
+	Payload    []byte
+	Response   []byte
+	StatusCode int
+	Err        error
+	Started    time.Time
+	Duration   time.Duration
+
+	CommandStarted time.Time
+	TotalDuration  time.Duration
+}
+
+// CommandObserver 每次请求完成后回调，回调在请求所在的协程中同步执行
+type CommandObserver func(record CommandRecord)
+
 // NewHTTPClient 创建新的HTTP客户端
 func NewHTTPClient(timeout time.Duration) *HTTPClient {
 	if timeout == 0 {
@@ -31,28 +130,35 @@ func NewHTTPClient(timeout time.Duration) *HTTPClient {
 
 // GetRequest 发送GET请求
 func (h *HTTPClient) GetRequest(url string, headers map[string]string) ([]byte, error) {
+	return h.getRequest(nil, url, headers)
+}
+
+func (h *HTTPClient) getRequest(operation *Operation, url string, headers map[string]string) (body []byte, err error) {
+	record := newCommandRecord(operation, http.MethodGet, url)
+	defer func() { h.notify(record, body, err) }()
 
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
-		return nil, fmt.Errorf(" Error in create http request: %v", err)
+		return nil, fmt.Errorf(" Error in create http request: %w", err)
 	}
 
 	for key, value := range headers {
 		req.Header.Set(key, value)
 	}
-	resp, err := h.client.Do(req)
+	resp, err := h.send(&record, req)
 	if err != nil {
-		return nil, fmt.Errorf(" Error in send request : %v", err)
+		return nil, fmt.Errorf(" Error in send request : %w", err)
 	}
 	defer resp.Body.Close()
+	record.StatusCode = resp.StatusCode
 
-	body, err := io.ReadAll(resp.Body)
+	body, err = io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf(" Error in read message from response : %v", err)
+		return nil, fmt.Errorf(" Error in read message from response : %w", err)
 	}
 
 	if resp.StatusCode >= 400 {
-		return body, fmt.Errorf(" Error in check status code : %s", resp.Status)
+		return body, newWdaError(fmt.Sprintf(" Error in check status code : %s", resp.Status), resp.StatusCode, body)
 	}
 
 	return body, nil
@@ -60,7 +166,13 @@ func (h *HTTPClient) GetRequest(url string, headers map[string]string) ([]byte,
 
 // PostRequest 发送POST请求
 func (h *HTTPClient) PostRequest(url string, data interface{}, headers map[string]string) ([]byte, error) {
+	return h.postRequest(nil, url, data, headers)
+}
+
+func (h *HTTPClient) postRequest(operation *Operation, url string, data interface{}, headers map[string]string) (respBody []byte, err error) {
 	var body io.Reader
+	record := newCommandRecord(operation, http.MethodPost, url)
+	defer func() { h.notify(record, respBody, err) }()
 
 	// 处理请求数据
 	if data != nil {
@@ -69,14 +181,15 @@ func (h *HTTPClient) PostRequest(url string, data interface{}, headers map[strin
 		log.DebugF("Request body is : %v", string(jsonData))
 
 		if err != nil {
-			return nil, fmt.Errorf(" Format json failed : %v", err)
+			return nil, fmt.Errorf(" Format json failed : %w", err)
 		}
 		body = bytes.NewBuffer(jsonData)
+		record.Payload = jsonData
 	}
 
 	req, err := http.NewRequest("POST", url, body)
 	if err != nil {
-		return nil, fmt.Errorf(" Create POST requestd failed: %v", err)
+		return nil, fmt.Errorf(" Create POST requestd failed: %w", err)
 	}
 
 	// 设置默认Content-Type
@@ -90,26 +203,27 @@ func (h *HTTPClient) PostRequest(url string, data interface{}, headers map[strin
 	}
 
 	// 发送请求
-	resp, err := h.client.Do(req)
+	resp, err := h.send(&record, req)
 	if err != nil {
-		return nil, fmt.Errorf(" Send POST failed : %v", err)
+		return nil, fmt.Errorf(" Send POST failed : %w", err)
 	}
 	defer resp.Body.Close()
+	record.StatusCode = resp.StatusCode
 
 	// 读取响应
-	respBody, err := io.ReadAll(resp.Body)
+	respBody, err = io.ReadAll(resp.Body)
 	if err != nil {
 		log.DebugF("response status code is %v ", resp.StatusCode)
 		log.DebugF("response body is %v ", respBody)
 
-		return nil, fmt.Errorf(" Read  message from response failed: %v", err)
+		return nil, fmt.Errorf(" Read  message from response failed: %w", err)
 	}
 
 	log.DebugF("response body is %v\n", string(respBody))
 
 	// 检查状态码
 	if resp.StatusCode >= 400 {
-		return respBody, fmt.Errorf(" Error, http status code is not correct : %s", resp.Status)
+		return respBody, newWdaError(fmt.Sprintf(" Error, http status code is not correct : %s", resp.Status), resp.StatusCode, respBody)
 	}
 
 	return respBody, nil
@@ -117,9 +231,16 @@ func (h *HTTPClient) PostRequest(url string, data interface{}, headers map[strin
 
 // DeleteRequest 发送DELETE请求
 func (h *HTTPClient) DeleteRequest(url string, headers map[string]string) ([]byte, error) {
+	return h.deleteRequest(nil, url, headers)
+}
+
+func (h *HTTPClient) deleteRequest(operation *Operation, url string, headers map[string]string) (body []byte, err error) {
+	record := newCommandRecord(operation, http.MethodDelete, url)
+	defer func() { h.notify(record, body, err) }()
+
 	req, err := http.NewRequest("DELETE", url, nil)
 	if err != nil {
-		return nil, fmt.Errorf(" Error in Create Delete Request: %v", err)
+		return nil, fmt.Errorf(" Error in Create Delete Request: %w", err)
 	}
 
 	// 设置请求头
@@ -128,26 +249,202 @@ func (h *HTTPClient) DeleteRequest(url string, headers map[string]string) ([]byt
 	}
 
 	// 发送请求
-	resp, err := h.client.Do(req)
+	resp, err := h.send(&record, req)
 	if err != nil {
-		return nil, fmt.Errorf(" Error in sending Delete Request : %v", err)
+		return nil, fmt.Errorf(" Error in sending Delete Request : %w", err)
 	}
 	defer resp.Body.Close()
+	record.StatusCode = resp.StatusCode
 
 	// 读取响应
-	body, err := io.ReadAll(resp.Body)
+	body, err = io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf(" Error in reading message from response : %v", err)
+		return nil, fmt.Errorf(" Error in reading message from response : %w", err)
 	}
 
 	// 检查状态码
 	if resp.StatusCode >= 400 {
-		return body, fmt.Errorf(" Error in checking http status code : %s", resp.Status)
+		return body, newWdaError(fmt.Sprintf(" Error in checking http status code : %s", resp.Status), resp.StatusCode, body)
 	}
 
 	return body, nil
 }
 
+// Request 发送任意方法的请求，payload为原始的请求体，返回状态码和响应
+// 状态码>=400时只在观察者的记录中标记错误，不返回错误，用于转发请求
+func (h *HTTPClient) Request(method, url string, payload []byte, headers map[string]string) (int, []byte, error) {
+	return h.RequestContext(context.Background(), method, url, payload, headers)
+}
+
+// RequestContext 与Request相同，ctx结束时取消请求和重试等待
+func (h *HTTPClient) RequestContext(ctx context.Context, method, url string, payload []byte, headers map[string]string) (statusCode int, respBody []byte, err error) {
+	record := newCommandRecord(nil, method, url)
+	record.Payload = payload
+	defer func() {
+		notifyErr := err
+		if notifyErr == nil && statusCode >= 400 {
+			notifyErr = fmt.Errorf(" Error in check status code : %d", statusCode)
+		}
+		h.notify(record, respBody, notifyErr)
+	}()
+
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return 0, nil, fmt.Errorf(" Create %v request failed: %w", method, err)
+	}
+	if payload != nil && headers["Content-Type"] == "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := h.send(&record, req)
+	if err != nil {
+		return 0, nil, fmt.Errorf(" Send %v request failed : %w", method, err)
+	}
+	defer resp.Body.Close()
+	record.StatusCode = resp.StatusCode
+
+	respBody, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, nil, fmt.Errorf(" Read message from response failed: %w", err)
+	}
+	return resp.StatusCode, respBody, nil
+}
+
+// SetRetry 设置请求失败时的重试参数
+func (h *HTTPClient) SetRetry(option RetryOption) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.retry = option
+}
+
+func newCommandRecord(operation *Operation, method, url string) CommandRecord {
+	now := time.Now()
+	return CommandRecord{Id: commandId.Add(1), Operation: operation, Attempt: 1, Method: method, Url: url, Started: now, CommandStarted: now}
+}
+
+// send 发送请求，按重试参数重试，record中记录最后一次请求的序号和开始时间，失败时返回的错误为ErrTransport
+func (h *HTTPClient) send(record *CommandRecord, req *http.Request) (*http.Response, error) {
+	resp, err := h.sendWithRetry(record, req)
+	if err != nil {
+		return nil, &transportError{err: err}
+	}
+	return resp, nil
+}
+
+func (h *HTTPClient) sendWithRetry(record *CommandRecord, req *http.Request) (*http.Response, error) {
+	h.mu.RLock()
+	retry := h.retry
+	h.mu.RUnlock()
+
+	h.notifyStart(*record)
+	for {
+		resp, err := h.client.Do(req)
+		if err == nil || record.Attempt > retry.Count || !retryable(record.Method, err) {
+			return resp, err
+		}
+
+		// 重试需要新的请求体，NewRequest时已设置GetBody
+		next := req.Clone(req.Context())
+		if req.GetBody != nil {
+			body, bodyErr := req.GetBody()
+			if bodyErr != nil {
+				return nil, bodyErr
+			}
+			next.Body = body
+		}
+
+		log.DebugF("Request %v %v failed, retry %d: %v", record.Method, record.Url, record.Attempt, err)
+		h.notifyRetry(*record, err)
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(retry.Interval):
+		}
+		req = next
+		record.Attempt++
+		record.Started = time.Now()
+	}
+}
+
+// retryable 连接失败时请求没有发送到wda，可以重试；其他错误只重试GET请求
+func retryable(method string, err error) bool {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	return method == http.MethodGet
+}
+
+// AddObserver 添加请求观察者，用于记录、统计wda请求
+func (h *HTTPClient) AddObserver(observer CommandObserver) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.observers = append(h.observers, observer)
+}
+
+// AddStartObserver 添加请求开始时的观察者，回调时只有请求信息，没有响应
+func (h *HTTPClient) AddStartObserver(observer CommandObserver) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.startObservers = append(h.startObservers, observer)
+}
+
+func (h *HTTPClient) notifyStart(record CommandRecord) {
+	h.mu.RLock()
+	observers := h.startObservers
+	h.mu.RUnlock()
+	for _, observer := range observers {
+		observer(record)
+	}
+}
+
+// AddRetryObserver 添加请求重试的观察者，每次失败后重试前回调，Err为本次失败的原因
+func (h *HTTPClient) AddRetryObserver(observer CommandObserver) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.retryObservers = append(h.retryObservers, observer)
+}
+
+func (h *HTTPClient) notifyRetry(record CommandRecord, err error) {
+	h.mu.RLock()
+	observers := h.retryObservers
+	h.mu.RUnlock()
+	if len(observers) == 0 {
+		return
+	}
+
+	record.Err = err
+	record.Duration = time.Since(record.Started)
+	record.TotalDuration = time.Since(record.CommandStarted)
+	for _, observer := range observers {
+		observer(record)
+	}
+}
+
+func (h *HTTPClient) notify(record CommandRecord, body []byte, err error) {
+	h.mu.RLock()
+	observers := h.observers
+	h.mu.RUnlock()
+	if len(observers) == 0 {
+		return
+	}
+
+	record.Response = body
+	record.Err = err
+	record.Duration = time.Since(record.Started)
+	record.TotalDuration = time.Since(record.CommandStarted)
+	for _, observer := range observers {
+		observer(record)
+	}
+}
+
 // 便捷函数 - 使用默认客户端
 
 // Get 使用默认客户端发送GET请求
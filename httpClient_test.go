@@ -0,0 +1,104 @@
+package WdaGo
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCommandRecordRetryTiming(t *testing.T) {
+	var failures atomic.Int32
+	failures.Store(2)
+	session := newTestSession(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failures.Add(-1) >= 0 {
+			conn, _, _ := w.(http.Hijacker).Hijack()
+			conn.Close()
+			return
+		}
+		writeValue(w, `{"ready":true}`)
+	}))
+	const interval = 30 * time.Millisecond
+	session.SetRetry(RetryOption{Count: 2, Interval: interval})
+
+	var mu sync.Mutex
+	var started, retried, finished []CommandRecord
+	collect := func(records *[]CommandRecord) CommandObserver {
+		return func(record CommandRecord) {
+			mu.Lock()
+			defer mu.Unlock()
+			*records = append(*records, record)
+		}
+	}
+	session.client.AddStartObserver(collect(&started))
+	session.client.AddRetryObserver(collect(&retried))
+	session.client.AddObserver(collect(&finished))
+
+	if _, err := session.GetStatus(); err != nil {
+		t.Fatalf("GetStatus() error = %v", err)
+	}
+	if len(started) != 1 || len(retried) != 2 || len(finished) != 1 {
+		t.Fatalf("got %d start, %d retry and %d finish callbacks, want 1, 2, 1", len(started), len(retried), len(finished))
+	}
+
+	first, last := started[0], finished[0]
+	if last.Id != first.Id || last.Attempt != 3 {
+		t.Errorf("finished record id %d attempt %d, want id %d attempt 3", last.Id, last.Attempt, first.Id)
+	}
+	// 每次重试更新Started，CommandStarted始终为第一次请求的时间
+	if !last.CommandStarted.Equal(first.Started) || !retried[1].CommandStarted.Equal(first.Started) {
+		t.Errorf("CommandStarted changed during retries")
+	}
+	if !last.Started.After(first.Started) {
+		t.Errorf("Started not updated on retry")
+	}
+	if last.TotalDuration < 2*interval || last.TotalDuration <= last.Duration {
+		t.Errorf("TotalDuration = %v, Duration = %v, want total including %v of retries", last.TotalDuration, last.Duration, 2*interval)
+	}
+}
+
+func TestWdaError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"value":{"error":"no such element","message":"gone"}}`))
+		case "/empty":
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+	client := NewHTTPClient(0)
+
+	tests := []struct {
+		name       string
+		send       func(url string) error
+		path       string
+		wantStatus int
+		wantCode   string
+	}{
+		{"GET", func(url string) error { _, err := client.GetRequest(url, nil); return err }, "/missing", 404, "no such element"},
+		{"POST", func(url string) error { _, err := client.PostRequest(url, nil, nil); return err }, "/missing", 404, "no such element"},
+		{"DELETE", func(url string) error { _, err := client.DeleteRequest(url, nil); return err }, "/missing", 404, "no such element"},
+		{"without error code", func(url string) error { _, err := client.PostRequest(url, nil, nil); return err }, "/empty", 500, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.send(server.URL + tt.path)
+			var wdaErr *WdaError
+			if !errors.As(err, &wdaErr) {
+				t.Fatalf("error = %v, want WdaError", err)
+			}
+			if wdaErr.StatusCode != tt.wantStatus || wdaErr.Code != tt.wantCode {
+				t.Errorf("WdaError = %d %q, want %d %q", wdaErr.StatusCode, wdaErr.Code, tt.wantStatus, tt.wantCode)
+			}
+		})
+	}
+
+	if err := JudgeResponseError([]byte(`{"value":{"error":"no such alert","message":""}}`)); !errors.As(err, new(*WdaError)) {
+		t.Errorf("JudgeResponseError() = %v, want WdaError", err)
+	}
+}
@@ -0,0 +1,96 @@
+package WdaGo
+
+import (
+	"fmt"
+)
+
+// 特殊按键，对应XCUIKeyboardKey，可与普通文本拼接后传给SendKeys或TypingText
+const (
+	KeyReturn = "\r"
+	KeyDelete = "\x7F"
+	KeyTab    = "\t"
+	KeyEscape = "\x1b"
+	KeySpace  = " "
+)
+
+const KeyboardClassName = "XCUIElementTypeKeyboard"
+
+// SendKeys 向当前获得焦点的元素输入文本，不需要指定元素
+func (session *WdaSession) SendKeys(text string) error {
+	session, end := session.begin("WdaSession.SendKeys")
+	defer end()
+
+	return session.SendKeysWithFrequency(text, 0)
+}
+
+// SendKeysWithFrequency 向当前获得焦点的元素输入文本，frequency为每分钟输入的字符数
+func (session *WdaSession) SendKeysWithFrequency(text string, frequency int) error {
+	session, end := session.begin("WdaSession.SendKeysWithFrequency")
+	defer end()
+
+	api := session.url + "/session/" + session.SessionId() + "/wda/keys"
+
+	body, err := session.post(api, TypingRequest{
+		Value:     SplitKeys(text),
+		Frequency: frequency,
+	})
+	if err != nil {
+		return fmt.Errorf(" Send keys failed from api :%w", err)
+	}
+
+	if JudgeResponseCorrect(body, session.SessionId()) {
+		return nil
+	} else {
+		return fmt.Errorf(" Send keys failed ")
+	}
+}
+
+// PressKey 按下特殊按键，如KeyReturn、KeyDelete、KeyTab
+func (session *WdaSession) PressKey(key string) error {
+	session, end := session.begin("WdaSession.PressKey")
+	defer end()
+
+	return session.SendKeys(key)
+}
+
+// DismissKeyboard 收起软键盘，keyNames为用于收起键盘的按键名，如"Done"，不传时由wda自行尝试
+func (session *WdaSession) DismissKeyboard(keyNames ...string) error {
+	session, end := session.begin("WdaSession.DismissKeyboard")
+	defer end()
+
+	api := session.url + "/session/" + session.SessionId() + "/wda/keyboard/dismiss"
+
+	body, err := session.post(api, KeyboardDismissRequest{
+		KeyNames: keyNames,
+	})
+	if err != nil {
+		return fmt.Errorf(" Dismiss keyboard failed from api :%w", err)
+	}
+
+	if JudgeResponseCorrect(body, session.SessionId()) {
+		return nil
+	} else {
+		return fmt.Errorf(" Dismiss keyboard failed ")
+	}
+}
+
+// IsKeyboardShown 软键盘是否正在显示
+func (session *WdaSession) IsKeyboardShown() (bool, error) {
+	session, end := session.begin("WdaSession.IsKeyboardShown")
+	defer end()
+
+	elementId, err := session.SearchElement(ClassName, KeyboardClassName)
+	if err != nil {
+		return false, fmt.Errorf(" Check keyboard shown failed :%w", err)
+	}
+	return elementId != "", nil
+}
+
+// SplitKeys 将文本按字符拆分为wda需要的字符数组
+func SplitKeys(text string) []string {
+	keys := make([]string, 0, len(text))
+	for _, r := range text {
+		keys = append(keys, string(r))
+	}
+	return keys
+}
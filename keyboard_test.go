@@ -0,0 +1,43 @@
+package WdaGo
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestSplitKeys(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"", []string{}},
+		{"abc", []string{"a", "b", "c"}},
+		{"你好", []string{"你", "好"}},
+		{"a" + KeyDelete + KeyReturn, []string{"a", "\u007f", "\r"}},
+	}
+	for _, tt := range tests {
+		if got := SplitKeys(tt.text); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SplitKeys(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestPressKey(t *testing.T) {
+	var received TypingRequest
+	mux := http.NewServeMux()
+	mux.HandleFunc("/session/"+testSessionId+"/wda/keys", func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&received)
+		writeValue(w, "null")
+	})
+	session := newTestSession(t, mux)
+
+	if err := session.PressKey(KeyDelete); err != nil {
+		t.Fatalf("PressKey() error = %v", err)
+	}
+	// XCUIKeyboardKeyDelete 为 \u007F
+	if want := []string{"\u007f"}; !reflect.DeepEqual(received.Value, want) {
+		t.Errorf("wda received %q, want %q", received.Value, want)
+	}
+}
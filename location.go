@@ -0,0 +1,261 @@
+package WdaGo
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"time"
+
+	"github.com/tidwall/gjson"
+)
+
+const (
+	DefaultRouteSpeed    = 10.0
+	DefaultRouteInterval = time.Second
+	earthRadius          = 6371000.0
+)
+
+// RoutePoint 路线上的一个点，Elevation单位为米
+type RoutePoint struct {
+	Latitude  float64
+	Longitude float64
+	Elevation float64
+}
+
+// RoutePlayOption 路线回放参数
+//
+//	Speed    移动速度，单位为米/秒
+//	Interval 更新模拟定位的时间间隔
+//	OnUpdate 每次更新定位后回调，可用于记录日志
+type RoutePlayOption struct {
+	Speed    float64
+	Interval time.Duration
+	OnUpdate func(point RoutePoint)
+}
+
+type gpxFile struct {
+	Waypoints []gpxPoint `xml:"wpt"`
+	Routes    []struct {
+		Points []gpxPoint `xml:"rtept"`
+	} `xml:"rte"`
+	Tracks []struct {
+		Segments []struct {
+			Points []gpxPoint `xml:"trkpt"`
+		} `xml:"trkseg"`
+	} `xml:"trk"`
+}
+
+type gpxPoint struct {
+	Latitude  float64 `xml:"lat,attr"`
+	Longitude float64 `xml:"lon,attr"`
+	Elevation float64 `xml:"ele"`
+}
+
+// SetSimulatedLocation 设置模拟定位，需要iOS 16.4以上或模拟器，以及Xcode 14.3以上编译的wda
+func (session *WdaSession) SetSimulatedLocation(latitude, longitude float64) error {
+	session, end := session.begin("WdaSession.SetSimulatedLocation")
+	defer end()
+
+	api := session.url + "/wda/simulatedLocation"
+
+	body, err := session.post(api, SimulatedLocation{
+		Latitude:  latitude,
+		Longitude: longitude,
+	})
+	if err != nil {
+		return fmt.Errorf(" Set simulated location failed from api :%w", err)
+	}
+
+	if gjson.Get(string(body), "value").String() == "" {
+		return nil
+	} else {
+		return fmt.Errorf(" Set simulated location failed ")
+	}
+}
+
+// GetSimulatedLocation 获取当前的模拟定位，未设置时返回nil
+func (session *WdaSession) GetSimulatedLocation() (*SimulatedLocation, error) {
+	session, end := session.begin("WdaSession.GetSimulatedLocation")
+	defer end()
+
+	api := session.url + "/wda/simulatedLocation"
+
+	body, err := session.get(api)
+	if err != nil {
+		return nil, fmt.Errorf(" Get simulated location failed from api :%w", err)
+	}
+
+	value := gjson.Get(string(body), "value")
+	if !value.Get("latitude").Exists() || value.Get("latitude").Type == gjson.Null {
+		return nil, nil
+	}
+
+	return &SimulatedLocation{
+		Latitude:  value.Get("latitude").Float(),
+		Longitude: value.Get("longitude").Float(),
+	}, nil
+}
+
+// ClearSimulatedLocation 清除模拟定位，恢复真实定位
+func (session *WdaSession) ClearSimulatedLocation() error {
+	session, end := session.begin("WdaSession.ClearSimulatedLocation")
+	defer end()
+
+	api := session.url + "/wda/simulatedLocation"
+
+	body, err := session.delete(api)
+	if err != nil {
+		return fmt.Errorf(" Clear simulated location failed from api :%w", err)
+	}
+
+	if gjson.Get(string(body), "value").String() == "" {
+		return nil
+	} else {
+		return fmt.Errorf(" Clear simulated location failed ")
+	}
+}
+
+// PlayRoute 按指定速度沿路线移动模拟定位，ctx取消时停止回放
+// 每个Interval根据速度计算在路线上的位置，在相邻两点之间做线性插值
+func (session *WdaSession) PlayRoute(ctx context.Context, points []RoutePoint, option RoutePlayOption) error {
+	session, end := session.begin("WdaSession.PlayRoute")
+	defer end()
+
+	if len(points) == 0 {
+		return fmt.Errorf(" Route is empty ")
+	}
+	if option.Speed <= 0 {
+		option.Speed = DefaultRouteSpeed
+	}
+	if option.Interval <= 0 {
+		option.Interval = DefaultRouteInterval
+	}
+
+	// 每一段的累计距离
+	distances := make([]float64, len(points))
+	for i := 1; i < len(points); i++ {
+		distances[i] = distances[i-1] + Distance(points[i-1], points[i])
+	}
+	total := distances[len(distances)-1]
+
+	ticker := time.NewTicker(option.Interval)
+	defer ticker.Stop()
+
+	start := time.Now()
+	segment := 0
+	for {
+		travelled := math.Min(time.Since(start).Seconds()*option.Speed, total)
+		for segment < len(points)-2 && distances[segment+1] < travelled {
+			segment++
+		}
+
+		point := points[segment]
+		if segment+1 < len(points) {
+			length := distances[segment+1] - distances[segment]
+			ratio := 0.0
+			if length > 0 {
+				ratio = (travelled - distances[segment]) / length
+			}
+			point = interpolate(points[segment], points[segment+1], ratio)
+		}
+
+		if err := session.SetSimulatedLocation(point.Latitude, point.Longitude); err != nil {
+			return err
+		}
+		if option.OnUpdate != nil {
+			option.OnUpdate(point)
+		}
+		if travelled >= total {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// PlayGpxFile 读取gpx文件并回放路线
+func (session *WdaSession) PlayGpxFile(ctx context.Context, path string, option RoutePlayOption) error {
+	session, end := session.begin("WdaSession.PlayGpxFile")
+	defer end()
+
+	points, err := LoadGpxRoute(path)
+	if err != nil {
+		return err
+	}
+	return session.PlayRoute(ctx, points, option)
+}
+
+// LoadGpxRoute 读取gpx文件中的路线点
+func LoadGpxRoute(path string) ([]RoutePoint, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf(" Open gpx file failed :%w", err)
+	}
+	defer file.Close()
+
+	return ParseGpx(file)
+}
+
+// ParseGpx 解析gpx，依次读取trk、rte、wpt中的点，优先使用轨迹点
+func ParseGpx(reader io.Reader) ([]RoutePoint, error) {
+	var gpx gpxFile
+	if err := xml.NewDecoder(reader).Decode(&gpx); err != nil {
+		return nil, fmt.Errorf(" Parse gpx failed :%w", err)
+	}
+
+	var raw []gpxPoint
+	for _, track := range gpx.Tracks {
+		for _, seg := range track.Segments {
+			raw = append(raw, seg.Points...)
+		}
+	}
+	if len(raw) == 0 {
+		for _, route := range gpx.Routes {
+			raw = append(raw, route.Points...)
+		}
+	}
+	if len(raw) == 0 {
+		raw = gpx.Waypoints
+	}
+	if len(raw) == 0 {
+		return nil, fmt.Errorf(" No point found in gpx ")
+	}
+
+	points := make([]RoutePoint, 0, len(raw))
+	for _, p := range raw {
+		points = append(points, RoutePoint{
+			Latitude:  p.Latitude,
+			Longitude: p.Longitude,
+			Elevation: p.Elevation,
+		})
+	}
+	return points, nil
+}
+
+// Distance 使用haversine公式计算两点间的距离，单位为米
+func Distance(a, b RoutePoint) float64 {
+	lat1 := a.Latitude * math.Pi / 180
+	lat2 := b.Latitude * math.Pi / 180
+	dLat := lat2 - lat1
+	dLon := (b.Longitude - a.Longitude) * math.Pi / 180
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadius * math.Asin(math.Sqrt(h))
+}
+
+func interpolate(a, b RoutePoint, ratio float64) RoutePoint {
+	ratio = math.Max(0, math.Min(1, ratio))
+	return RoutePoint{
+		Latitude:  a.Latitude + (b.Latitude-a.Latitude)*ratio,
+		Longitude: a.Longitude + (b.Longitude-a.Longitude)*ratio,
+		Elevation: a.Elevation + (b.Elevation-a.Elevation)*ratio,
+	}
+}
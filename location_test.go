@@ -0,0 +1,182 @@
+package WdaGo
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestParseGpx(t *testing.T) {
+	tests := []struct {
+		name    string
+		gpx     string
+		want    []RoutePoint
+		wantErr bool
+	}{
+		{
+			name: "track segments are joined",
+			gpx: `<gpx><trk><trkseg>
+				<trkpt lat="31.1" lon="121.1"><ele>5</ele></trkpt>
+				<trkpt lat="31.2" lon="121.2"></trkpt>
+			</trkseg><trkseg><trkpt lat="31.3" lon="121.3"><ele>7.5</ele></trkpt></trkseg></trk>
+			<wpt lat="1" lon="1"/></gpx>`,
+			want: []RoutePoint{{31.1, 121.1, 5}, {31.2, 121.2, 0}, {31.3, 121.3, 7.5}},
+		},
+		{
+			name: "route used without track",
+			gpx: `<gpx><rte><rtept lat="-33.86" lon="151.2"/><rtept lat="-33.87" lon="151.21"/></rte>
+			<wpt lat="1" lon="1"/></gpx>`,
+			want: []RoutePoint{{-33.86, 151.2, 0}, {-33.87, 151.21, 0}},
+		},
+		{
+			name: "waypoints used as fallback",
+			gpx:  `<gpx><wpt lat="48.85" lon="2.35"><ele>35</ele></wpt></gpx>`,
+			want: []RoutePoint{{48.85, 2.35, 35}},
+		},
+		{name: "no points", gpx: `<gpx><trk><trkseg/></trk></gpx>`, wantErr: true},
+		{name: "invalid xml", gpx: `<gpx><trk>`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseGpx(strings.NewReader(tt.gpx))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseGpx() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("ParseGpx() got %d points, want %d", len(got), len(tt.want))
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("point %d = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b RoutePoint
+		want float64
+	}{
+		{"same point", RoutePoint{Latitude: 31, Longitude: 121}, RoutePoint{Latitude: 31, Longitude: 121}, 0},
+		{"one degree latitude", RoutePoint{Latitude: 0, Longitude: 0}, RoutePoint{Latitude: 1, Longitude: 0}, 111195},
+		{"one degree longitude at equator", RoutePoint{Latitude: 0, Longitude: 0}, RoutePoint{Latitude: 0, Longitude: 1}, 111195},
+		{"paris to london", RoutePoint{Latitude: 48.8566, Longitude: 2.3522}, RoutePoint{Latitude: 51.5074, Longitude: -0.1278}, 343556},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Distance(tt.a, tt.b); math.Abs(got-tt.want) > 1 {
+				t.Errorf("Distance() = %v, want %v", got, tt.want)
+			}
+			if got, reverse := Distance(tt.a, tt.b), Distance(tt.b, tt.a); math.Abs(got-reverse) > 1e-6 {
+				t.Errorf("Distance() not symmetric: %v, %v", got, reverse)
+			}
+		})
+	}
+}
+
+func TestInterpolate(t *testing.T) {
+	a := RoutePoint{Latitude: 10, Longitude: 20, Elevation: 0}
+	b := RoutePoint{Latitude: 20, Longitude: 40, Elevation: 100}
+	tests := []struct {
+		ratio float64
+		want  RoutePoint
+	}{
+		{0, a},
+		{0.25, RoutePoint{Latitude: 12.5, Longitude: 25, Elevation: 25}},
+		{1, b},
+		{-1, a},
+		{2, b},
+	}
+	for _, tt := range tests {
+		if got := interpolate(a, b, tt.ratio); got != tt.want {
+			t.Errorf("interpolate(%v) = %v, want %v", tt.ratio, got, tt.want)
+		}
+	}
+}
+
+// fakeSimulatedLocation 记录wda收到的模拟定位
+type fakeSimulatedLocation struct {
+	mu     sync.Mutex
+	points []SimulatedLocation
+}
+
+func (fake *fakeSimulatedLocation) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var location SimulatedLocation
+	json.NewDecoder(r.Body).Decode(&location)
+	fake.mu.Lock()
+	fake.points = append(fake.points, location)
+	fake.mu.Unlock()
+	writeValue(w, "null")
+}
+
+func (fake *fakeSimulatedLocation) received() []SimulatedLocation {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	return append([]SimulatedLocation(nil), fake.points...)
+}
+
+func TestPlayRoute(t *testing.T) {
+	fake := &fakeSimulatedLocation{}
+	mux := http.NewServeMux()
+	mux.Handle("/wda/simulatedLocation", fake)
+	session := newTestSession(t, mux)
+
+	// 约1112米的路线，速度10000米/秒、间隔20ms时每次移动约200米
+	route := []RoutePoint{{Latitude: 0, Longitude: 0}, {Latitude: 0.005, Longitude: 0}, {Latitude: 0.01, Longitude: 0}}
+	var updates []RoutePoint
+	err := session.PlayRoute(context.Background(), route, RoutePlayOption{
+		Speed:    10000,
+		Interval: 20 * time.Millisecond,
+		OnUpdate: func(point RoutePoint) { updates = append(updates, point) },
+	})
+	if err != nil {
+		t.Fatalf("PlayRoute() error = %v", err)
+	}
+
+	points := fake.received()
+	if len(points) < 3 || len(points) != len(updates) {
+		t.Fatalf("got %d locations and %d updates, want at least 3 of each", len(points), len(updates))
+	}
+	// 第一次定位前已经过去极短的时间，允许少量偏移
+	if points[0].Latitude > 1e-4 || points[0].Longitude != 0 {
+		t.Errorf("first location = %v, want route start", points[0])
+	}
+	if last := points[len(points)-1]; last != (SimulatedLocation{Latitude: 0.01, Longitude: 0}) {
+		t.Errorf("last location = %v, want route end", last)
+	}
+	for i := 1; i < len(points); i++ {
+		if points[i].Latitude < points[i-1].Latitude {
+			t.Errorf("location %d moved backwards: %v -> %v", i, points[i-1], points[i])
+		}
+	}
+}
+
+func TestPlayRouteCancel(t *testing.T) {
+	fake := &fakeSimulatedLocation{}
+	mux := http.NewServeMux()
+	mux.Handle("/wda/simulatedLocation", fake)
+	session := newTestSession(t, mux)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	route := []RoutePoint{{Latitude: 0, Longitude: 0}, {Latitude: 1, Longitude: 0}}
+	err := session.PlayRoute(ctx, route, RoutePlayOption{Speed: 1, Interval: 10 * time.Millisecond})
+	if err != context.DeadlineExceeded {
+		t.Fatalf("PlayRoute() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if len(fake.received()) == 0 {
+		t.Error("no location sent before cancel")
+	}
+
+	if err = session.PlayRoute(context.Background(), nil, RoutePlayOption{}); err == nil {
+		t.Error("PlayRoute() with empty route: want error")
+	}
+}
@@ -0,0 +1,325 @@
+package WdaGo
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// 定位方式的基础分，越稳定、查找越快的方式分数越高
+const (
+	scoreAccessibilityId = 100
+	scorePredicate       = 85
+	scoreClassChain      = 75
+	scoreAnchoredChain   = 60
+	scoreIndexedChain    = 40
+	scoreXpath           = 35
+	scoreIndexedXpath    = 15
+
+	// 匹配到多个元素需要使用Index时扣分
+	penaltyIndex = 30
+	// 属性值看起来是动态生成的时扣分
+	penaltyDynamic = 40
+	// 依赖显示文本时扣分
+	penaltyText = 20
+)
+
+var dynamicValuePattern = regexp.MustCompile(`\d{4,}|[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-`)
+
+// LocatorSuggestion 定位建议
+//
+//	Count  在页面树中匹配到的元素个数
+//	Unique 是否只匹配到该元素，不唯一时Locator.Index为该元素在匹配结果中的序号
+//	Score  稳定性和查找速度的综合分数，越高越好
+type LocatorSuggestion struct {
+	Locator
+	Count  int    `json:"count"`
+	Unique bool   `json:"unique"`
+	Score  int    `json:"score"`
+	Reason string `json:"reason"`
+}
+
+// locatorCandidate 候选定位方式，resolve按wda的查找方式在页面树中返回该定位方式匹配到的全部元素，
+// value为定位方式依赖的属性值，用于判断是否为动态生成或者来自显示文本
+type locatorCandidate struct {
+	locator Locator
+	value   string
+	score   int
+	reason  string
+	resolve func() []*SourceNode
+}
+
+// SuggestLocators 为页面树中的节点生成多种定位方式，在页面树中计算每种方式实际匹配到的元素，
+// 按稳定性和查找速度排序，唯一的定位方式排在前面
+func SuggestLocators(root *SourceNode, node *SourceNode) []LocatorSuggestion {
+	var suggestions []LocatorSuggestion
+	for _, candidate := range locatorCandidates(root, node) {
+		matches := candidate.resolve()
+
+		index := -1
+		for i, n := range matches {
+			if n == node {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			continue
+		}
+
+		suggestion := LocatorSuggestion{
+			Locator: candidate.locator,
+			Count:   len(matches),
+			Unique:  len(matches) == 1,
+			Score:   candidate.score,
+			Reason:  candidate.reason,
+		}
+		if !suggestion.Unique {
+			suggestion.Index = index
+			suggestion.Score -= penaltyIndex
+			suggestion.Reason += fmt.Sprintf(", %d matches", len(matches))
+		}
+		suggestions = append(suggestions, suggestion)
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		if suggestions[i].Unique != suggestions[j].Unique {
+			return suggestions[i].Unique
+		}
+		return suggestions[i].Score > suggestions[j].Score
+	})
+	return suggestions
+}
+
+// BestLocator 返回分数最高的定位方式，没有可用的定位方式时返回错误
+func BestLocator(root *SourceNode, node *SourceNode) (*Locator, error) {
+	suggestions := SuggestLocators(root, node)
+	if len(suggestions) == 0 {
+		return nil, fmt.Errorf(" No locator found for %s ", node.Title())
+	}
+	return &suggestions[0].Locator, nil
+}
+
+// SuggestLocatorsAt 获取当前页面树，为坐标处的元素生成定位建议，坐标单位为点
+func (session *WdaSession) SuggestLocatorsAt(x, y float64) (*SourceNode, []LocatorSuggestion, error) {
+	session, end := session.begin("WdaSession.SuggestLocatorsAt")
+	defer end()
+
+	root, err := session.GetSourceTree()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	node := root.ElementAt(x, y)
+	if node == nil {
+		return nil, nil, fmt.Errorf(" No element found at %v,%v ", x, y)
+	}
+	return node, SuggestLocators(root, node), nil
+}
+
+// locatorCandidates 生成节点的候选定位方式
+// accessibility id、predicate和xpath在整个页面树中查找，class chain的 **/ 只查找根节点的后代，
+// 带序号的定位方式在全部匹配的元素中按文档顺序取序号
+func locatorCandidates(root *SourceNode, node *SourceNode) []locatorCandidate {
+	var candidates []locatorCandidate
+	add := func(candidate locatorCandidate) {
+		switch {
+		case isDynamicValue(candidate.value):
+			candidate.score -= penaltyDynamic
+			candidate.reason += ", value looks dynamic"
+		case candidate.value != "" && candidate.value == node.Name && node.Name == node.Label:
+			// name与label相同时通常是由显示文本生成的，多语言或文案修改后会失效
+			candidate.score -= penaltyText
+			candidate.reason += ", name derived from label"
+		}
+		candidates = append(candidates, candidate)
+	}
+	sameType := func(n *SourceNode) bool { return n.Type == node.Type }
+	all := func(match func(n *SourceNode) bool) func() []*SourceNode {
+		return func() []*SourceNode { return root.FindAll(match) }
+	}
+
+	// name策略与accessibility id在wda中都按name属性查找，只生成accessibility id
+	if node.Name != "" {
+		add(locatorCandidate{
+			locator: Locator{Using: StrategyAccessibilityId, Value: node.Name},
+			value:   node.Name,
+			score:   scoreAccessibilityId,
+			reason:  "accessibility id",
+			resolve: all(func(n *SourceNode) bool { return n.Name == node.Name }),
+		})
+		add(locatorCandidate{
+			locator: Locator{Using: StrategyPredicate, Value: fmt.Sprintf("type == '%s' AND name == '%s'", node.Type, escapePredicateString(node.Name))},
+			value:   node.Name,
+			score:   scorePredicate,
+			reason:  "predicate on type and name",
+			resolve: all(func(n *SourceNode) bool { return sameType(n) && n.Name == node.Name }),
+		})
+	}
+
+	if node.Label != "" && node.Label != node.Name {
+		add(locatorCandidate{
+			locator: Locator{Using: StrategyPredicate, Value: fmt.Sprintf("type == '%s' AND label == '%s'", node.Type, escapePredicateString(node.Label))},
+			value:   node.Label,
+			score:   scorePredicate - penaltyText,
+			reason:  "predicate on type and label",
+			resolve: all(func(n *SourceNode) bool { return sameType(n) && n.Label == node.Label }),
+		})
+	}
+
+	// class chain使用元素自身的属性
+	if attribute, value := identifyingAttribute(node); attribute != "" && !strings.Contains(value, "`") {
+		add(locatorCandidate{
+			locator: Locator{Using: StrategyClassChain, Value: fmt.Sprintf("**/%s[`%s == \"%s\"`]", node.Type, attribute, escapeChainString(value))},
+			value:   value,
+			score:   scoreClassChain,
+			reason:  "class chain on " + attribute,
+			resolve: func() []*SourceNode {
+				return descendants(root, func(n *SourceNode) bool { return sameType(n) && n.Attributes[attribute] == value })
+			},
+		})
+	}
+
+	// 以最近的有唯一name的祖先节点为锚点，列表中的元素通常使用这种方式
+	if anchor := uniqueAncestor(root, node); anchor != nil {
+		isAnchor := func(n *SourceNode) bool { return n.Type == anchor.Type && n.Name == anchor.Name }
+		var underAnchors []*SourceNode
+		root.Walk(func(n *SourceNode) bool {
+			if sameType(n) && hasAncestor(n, isAnchor) {
+				underAnchors = append(underAnchors, n)
+			}
+			return true
+		})
+		if position := indexOf(underAnchors, node); position > 0 {
+			resolve := func() []*SourceNode { return nth(underAnchors, position) }
+			add(locatorCandidate{
+				locator: Locator{Using: StrategyClassChain, Value: fmt.Sprintf("**/%s[`name == \"%s\"`]/**/%s[%d]", anchor.Type, escapeChainString(anchor.Name), node.Type, position)},
+				score:   scoreAnchoredChain,
+				reason:  "class chain anchored on " + anchor.Title(),
+				resolve: resolve,
+			})
+			if literal, ok := xpathLiteral(anchor.Name); ok {
+				add(locatorCandidate{
+					locator: Locator{Using: StrategyXpath, Value: fmt.Sprintf("(//%s[@name=%s]//%s)[%d]", anchor.Type, literal, node.Type, position)},
+					score:   scoreXpath - 10,
+					reason:  "xpath anchored on " + anchor.Title(),
+					resolve: resolve,
+				})
+			}
+		}
+	}
+
+	if attribute, value := identifyingAttribute(node); attribute != "" {
+		if literal, ok := xpathLiteral(value); ok {
+			add(locatorCandidate{
+				locator: Locator{Using: StrategyXpath, Value: fmt.Sprintf("//%s[@%s=%s]", node.Type, attribute, literal)},
+				value:   value,
+				score:   scoreXpath,
+				reason:  "xpath on " + attribute,
+				resolve: all(func(n *SourceNode) bool { return sameType(n) && n.Attributes[attribute] == value }),
+			})
+		}
+	}
+
+	// 只依赖序号的定位方式，界面变化后最容易失效，作为兜底
+	if position := indexOf(descendants(root, sameType), node); position > 0 {
+		add(locatorCandidate{
+			locator: Locator{Using: StrategyClassChain, Value: fmt.Sprintf("**/%s[%d]", node.Type, position)},
+			score:   scoreIndexedChain,
+			reason:  "class chain by index",
+			resolve: func() []*SourceNode { return nth(descendants(root, sameType), position) },
+		})
+	}
+	if position := indexOf(root.FindAll(sameType), node); position > 0 {
+		add(locatorCandidate{
+			locator: Locator{Using: StrategyXpath, Value: fmt.Sprintf("(//%s)[%d]", node.Type, position)},
+			score:   scoreIndexedXpath,
+			reason:  "xpath by index",
+			resolve: func() []*SourceNode { return nth(root.FindAll(sameType), position) },
+		})
+	}
+	return candidates
+}
+
+// descendants parent的后代中满足条件的节点，不包含parent自身
+func descendants(parent *SourceNode, match func(n *SourceNode) bool) []*SourceNode {
+	return parent.FindAll(func(n *SourceNode) bool { return n != parent && match(n) })
+}
+
+// nth 返回第position个节点，从1开始，超出范围时返回空
+func nth(nodes []*SourceNode, position int) []*SourceNode {
+	if position < 1 || position > len(nodes) {
+		return nil
+	}
+	return nodes[position-1 : position]
+}
+
+// indexOf node在nodes中的序号，从1开始，不存在时返回0
+func indexOf(nodes []*SourceNode, node *SourceNode) int {
+	for i, n := range nodes {
+		if n == node {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+func hasAncestor(node *SourceNode, match func(n *SourceNode) bool) bool {
+	for parent := node.Parent; parent != nil; parent = parent.Parent {
+		if match(parent) {
+			return true
+		}
+	}
+	return false
+}
+
+// identifyingAttribute 用于class chain和xpath的属性，依次使用name、label、value
+func identifyingAttribute(node *SourceNode) (string, string) {
+	switch {
+	case node.Name != "":
+		return "name", node.Name
+	case node.Label != "":
+		return "label", node.Label
+	case node.Value != "":
+		return "value", node.Value
+	}
+	return "", ""
+}
+
+// uniqueAncestor 最近的name在页面树中唯一的祖先节点
+func uniqueAncestor(root *SourceNode, node *SourceNode) *SourceNode {
+	for parent := node.Parent; parent != nil; parent = parent.Parent {
+		if parent.Name == "" || parent == root || isDynamicValue(parent.Name) {
+			continue
+		}
+		name := parent.Name
+		if len(root.FindAll(func(n *SourceNode) bool { return n.Name == name })) == 1 {
+			return parent
+		}
+	}
+	return nil
+}
+
+func isDynamicValue(value string) bool {
+	return len(value) > 60 || dynamicValuePattern.MatchString(value)
+}
+
+func escapePredicateString(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value)
+}
+
+func escapeChainString(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value)
+}
+
+// xpathLiteral xpath 1.0没有转义，同时包含单引号和双引号时无法表示
+func xpathLiteral(value string) (string, bool) {
+	if !strings.Contains(value, `"`) {
+		return `"` + value + `"`, true
+	}
+	if !strings.Contains(value, `'`) {
+		return `'` + value + `'`, true
+	}
+	return "", false
+}
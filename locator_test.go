@@ -0,0 +1,203 @@
+package WdaGo
+
+import (
+	"testing"
+)
+
+const locatorTestSource = `<?xml version="1.0" encoding="UTF-8"?>
+<AppiumAUT>
+<XCUIElementTypeApplication type="XCUIElementTypeApplication" name="Demo" label="Demo">
+  <XCUIElementTypeWindow type="XCUIElementTypeWindow">
+    <XCUIElementTypeButton type="XCUIElementTypeButton" name="login" label="Log In"/>
+    <XCUIElementTypeButton type="XCUIElementTypeButton" name="Back" label="Back"/>
+    <XCUIElementTypeTable type="XCUIElementTypeTable" name="settingsList">
+      <XCUIElementTypeCell type="XCUIElementTypeCell">
+        <XCUIElementTypeStaticText type="XCUIElementTypeStaticText" label="Wi-Fi"/>
+      </XCUIElementTypeCell>
+      <XCUIElementTypeCell type="XCUIElementTypeCell">
+        <XCUIElementTypeStaticText type="XCUIElementTypeStaticText" label="Bluetooth"/>
+      </XCUIElementTypeCell>
+    </XCUIElementTypeTable>
+    <XCUIElementTypeTable type="XCUIElementTypeTable" name="otherList">
+      <XCUIElementTypeCell type="XCUIElementTypeCell">
+        <XCUIElementTypeStaticText type="XCUIElementTypeStaticText" label="Wi-Fi"/>
+      </XCUIElementTypeCell>
+    </XCUIElementTypeTable>
+    <XCUIElementTypeButton type="XCUIElementTypeButton" name="item_1234567"/>
+    <XCUIElementTypeButton type="XCUIElementTypeButton"/>
+  </XCUIElementTypeWindow>
+</XCUIElementTypeApplication>
+</AppiumAUT>`
+
+func TestSuggestLocators(t *testing.T) {
+	root, err := ParseSource(locatorTestSource)
+	if err != nil {
+		t.Fatalf("ParseSource() error = %v", err)
+	}
+
+	type expectation struct {
+		count  int
+		unique bool
+		index  int
+	}
+	tests := []struct {
+		name string
+		path string
+		// best 排在第一位的定位方式
+		best Locator
+		// want 部分定位方式在页面树中的匹配结果
+		want map[Locator]expectation
+		// absent 不应该生成的定位方式
+		absent []Locator
+	}{
+		{
+			name: "accessibility id without duplicated name strategy",
+			path: "0.0.0",
+			best: Locator{Using: StrategyAccessibilityId, Value: "login"},
+			want: map[Locator]expectation{
+				{Using: StrategyAccessibilityId, Value: "login"}:                                           {1, true, 0},
+				{Using: StrategyPredicate, Value: "type == 'XCUIElementTypeButton' AND label == 'Log In'"}: {1, true, 0},
+				{Using: StrategyClassChain, Value: "**/XCUIElementTypeButton[1]"}:                          {1, true, 0},
+				{Using: StrategyXpath, Value: "(//XCUIElementTypeButton)[1]"}:                              {1, true, 0},
+			},
+			absent: []Locator{{Using: StrategyName, Value: "login"}},
+		},
+		{
+			name: "duplicated label resolved by anchor",
+			path: "0.0.2.0.0",
+			best: Locator{Using: StrategyClassChain, Value: "**/XCUIElementTypeTable[`name == \"settingsList\"`]/**/XCUIElementTypeStaticText[1]"},
+			want: map[Locator]expectation{
+				{Using: StrategyPredicate, Value: "type == 'XCUIElementTypeStaticText' AND label == 'Wi-Fi'"}:                   {2, false, 0},
+				{Using: StrategyClassChain, Value: "**/XCUIElementTypeStaticText[`label == \"Wi-Fi\"`]"}:                        {2, false, 0},
+				{Using: StrategyXpath, Value: "//XCUIElementTypeStaticText[@label=\"Wi-Fi\"]"}:                                  {2, false, 0},
+				{Using: StrategyXpath, Value: "(//XCUIElementTypeTable[@name=\"settingsList\"]//XCUIElementTypeStaticText)[1]"}: {1, true, 0},
+			},
+		},
+		{
+			name: "second match of duplicated label gets index",
+			path: "0.0.3.0.0",
+			best: Locator{Using: StrategyClassChain, Value: "**/XCUIElementTypeTable[`name == \"otherList\"`]/**/XCUIElementTypeStaticText[1]"},
+			want: map[Locator]expectation{
+				{Using: StrategyPredicate, Value: "type == 'XCUIElementTypeStaticText' AND label == 'Wi-Fi'"}: {2, false, 1},
+				{Using: StrategyClassChain, Value: "**/XCUIElementTypeStaticText[3]"}:                         {1, true, 0},
+			},
+		},
+		{
+			name: "element without attributes only has index locators",
+			path: "0.0.5",
+			best: Locator{Using: StrategyClassChain, Value: "**/XCUIElementTypeButton[4]"},
+			want: map[Locator]expectation{
+				{Using: StrategyClassChain, Value: "**/XCUIElementTypeButton[4]"}: {1, true, 0},
+				{Using: StrategyXpath, Value: "(//XCUIElementTypeButton)[4]"}:     {1, true, 0},
+			},
+		},
+		{
+			name: "root is not a class chain descendant",
+			path: "0",
+			best: Locator{Using: StrategyAccessibilityId, Value: "Demo"},
+			want: map[Locator]expectation{
+				{Using: StrategyXpath, Value: "(//XCUIElementTypeApplication)[1]"}: {1, true, 0},
+			},
+			absent: []Locator{
+				{Using: StrategyClassChain, Value: "**/XCUIElementTypeApplication[1]"},
+				{Using: StrategyClassChain, Value: "**/XCUIElementTypeApplication[`name == \"Demo\"`]"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := root.FindByPath(tt.path)
+			if node == nil {
+				t.Fatalf("node %s not found", tt.path)
+			}
+			suggestions := SuggestLocators(root, node)
+			if len(suggestions) == 0 {
+				t.Fatal("SuggestLocators() returned nothing")
+			}
+
+			seen := make(map[[2]string]bool)
+			got := make(map[Locator]LocatorSuggestion)
+			for _, s := range suggestions {
+				key := [2]string{s.Using, s.Value}
+				if seen[key] {
+					t.Errorf("duplicated suggestion %s %s", s.Using, s.Value)
+				}
+				seen[key] = true
+				got[Locator{Using: s.Using, Value: s.Value}] = s
+				if s.Unique != (s.Count == 1) {
+					t.Errorf("%s %s: Unique = %v with Count %d", s.Using, s.Value, s.Unique, s.Count)
+				}
+			}
+
+			if best := suggestions[0]; best.Using != tt.best.Using || best.Value != tt.best.Value || !best.Unique {
+				t.Errorf("best = %s %s (unique %v), want %s %s", best.Using, best.Value, best.Unique, tt.best.Using, tt.best.Value)
+			}
+			for locator, want := range tt.want {
+				s, ok := got[locator]
+				if !ok {
+					t.Errorf("missing suggestion %s %s", locator.Using, locator.Value)
+					continue
+				}
+				if s.Count != want.count || s.Unique != want.unique || s.Index != want.index {
+					t.Errorf("%s %s: count %d unique %v index %d, want %d %v %d",
+						locator.Using, locator.Value, s.Count, s.Unique, s.Index, want.count, want.unique, want.index)
+				}
+			}
+			for _, locator := range tt.absent {
+				if _, ok := got[locator]; ok {
+					t.Errorf("unexpected suggestion %s %s", locator.Using, locator.Value)
+				}
+			}
+		})
+	}
+}
+
+func TestSuggestLocatorsPenalties(t *testing.T) {
+	root, err := ParseSource(locatorTestSource)
+	if err != nil {
+		t.Fatalf("ParseSource() error = %v", err)
+	}
+
+	score := func(path string, using string) int {
+		for _, s := range SuggestLocators(root, root.FindByPath(path)) {
+			if s.Using == using {
+				return s.Score
+			}
+		}
+		t.Fatalf("no %s suggestion for %s", using, path)
+		return 0
+	}
+
+	stable := score("0.0.0", StrategyAccessibilityId)
+	if text := score("0.0.1", StrategyAccessibilityId); text != stable-penaltyText {
+		t.Errorf("name derived from label score = %d, want %d", text, stable-penaltyText)
+	}
+	if dynamic := score("0.0.4", StrategyAccessibilityId); dynamic != stable-penaltyDynamic {
+		t.Errorf("dynamic name score = %d, want %d", dynamic, stable-penaltyDynamic)
+	}
+}
+
+func TestLocatorEscaping(t *testing.T) {
+	if got := escapePredicateString(`it's \ ok`); got != `it\'s \\ ok` {
+		t.Errorf("escapePredicateString() = %q", got)
+	}
+	if got := escapeChainString(`say "hi"`); got != `say \"hi\"` {
+		t.Errorf("escapeChainString() = %q", got)
+	}
+
+	tests := []struct {
+		value string
+		want  string
+		ok    bool
+	}{
+		{`plain`, `"plain"`, true},
+		{`say "hi"`, `'say "hi"'`, true},
+		{`it's "both"`, "", false},
+	}
+	for _, tt := range tests {
+		got, ok := xpathLiteral(tt.value)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("xpathLiteral(%q) = %q, %v, want %q, %v", tt.value, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package WdaGo
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/Ning9527fff/MyLog"
+)
+
+const (
+	DefaultMjpegPort       = 9100
+	DefaultFrameBufferSize = 16
+	// maxFrameSize 单帧最大字节数，防止异常数据导致内存无限增长
+	maxFrameSize = 32 << 20
+)
+
+// MjpegFrame mjpeg视频流中的一帧，Data为jpeg原始数据
+type MjpegFrame struct {
+	Index int64
+	Time  time.Time
+	Data  []byte
+}
+
+// MjpegStream wda mjpeg视频流读取，帧通过channel传出，消费过慢时丢弃新帧
+type MjpegStream struct {
+	url        string
+	client     *http.Client
+	bufferSize int
+
+	mu      sync.Mutex
+	body    io.ReadCloser
+	done    chan struct{}
+	err     error
+	dropped int64
+}
+
+// Image 将帧数据解码为image.Image
+func (frame MjpegFrame) Image() (image.Image, error) {
+	img, err := jpeg.Decode(bytes.NewReader(frame.Data))
+	if err != nil {
+		return nil, fmt.Errorf(" Decode mjpeg frame failed :%w", err)
+	}
+	return img, nil
+}
+
+// NewMjpegStream 创建mjpeg视频流，streamUrl为wda mjpeg服务地址，如 http://127.0.0.1:9100
+func NewMjpegStream(streamUrl string) *MjpegStream {
+	return &MjpegStream{
+		url:        streamUrl,
+		client:     &http.Client{},
+		bufferSize: DefaultFrameBufferSize,
+	}
+}
+
+// SetBufferSize 设置帧channel的缓冲大小，需要在Start之前调用
+func (stream *MjpegStream) SetBufferSize(size int) {
+	if size > 0 {
+		stream.bufferSize = size
+	}
+}
+
+// Start 连接mjpeg服务并开始读取，返回的channel在Stop或者视频流结束时关闭
+func (stream *MjpegStream) Start() (<-chan MjpegFrame, error) {
+	stream.mu.Lock()
+	defer stream.mu.Unlock()
+
+	if stream.body != nil {
+		return nil, fmt.Errorf(" Mjpeg stream already started ")
+	}
+
+	resp, err := stream.client.Get(stream.url)
+	if err != nil {
+		return nil, fmt.Errorf(" Connect mjpeg stream failed :%w", err)
+	}
+	if resp.StatusCode >= 400 {
+		resp.Body.Close()
+		return nil, fmt.Errorf(" Connect mjpeg stream failed, http status code is : %s", resp.Status)
+	}
+
+	frames := make(chan MjpegFrame, stream.bufferSize)
+	stream.body = resp.Body
+	stream.done = make(chan struct{})
+	stream.err = nil
+	stream.dropped = 0
+
+	go stream.readLoop(resp.Body, frames, stream.done)
+	return frames, nil
+}
+
+// Stop 停止读取视频流，等待读取协程退出
+func (stream *MjpegStream) Stop() error {
+	stream.mu.Lock()
+	body, done := stream.body, stream.done
+	stream.body = nil
+	stream.mu.Unlock()
+
+	if body == nil {
+		return fmt.Errorf(" Mjpeg stream is not started ")
+	}
+
+	err := body.Close()
+	<-done
+	return err
+}
+
+// Err 返回视频流异常结束时的错误，主动Stop时为nil
+func (stream *MjpegStream) Err() error {
+	stream.mu.Lock()
+	defer stream.mu.Unlock()
+	return stream.err
+}
+
+// Dropped 返回因消费过慢而丢弃的帧数
+func (stream *MjpegStream) Dropped() int64 {
+	stream.mu.Lock()
+	defer stream.mu.Unlock()
+	return stream.dropped
+}
+
+func (stream *MjpegStream) readLoop(body io.ReadCloser, frames chan<- MjpegFrame, done chan struct{}) {
+	defer close(done)
+	defer close(frames)
+
+	reader := bufio.NewReaderSize(body, 64*1024)
+	var index int64
+	for {
+		data, err := readMjpegPart(reader)
+		if err != nil {
+			stream.mu.Lock()
+			// 主动Stop关闭body导致的错误不记录
+			if stream.body != nil && err != io.EOF {
+				stream.err = err
+				log.ErrorF("Read mjpeg stream failed: %v", err)
+			}
+			stream.mu.Unlock()
+			return
+		}
+
+		frame := MjpegFrame{Index: index, Time: time.Now(), Data: data}
+		index++
+		select {
+		case frames <- frame:
+		default:
+			stream.mu.Lock()
+			stream.dropped++
+			stream.mu.Unlock()
+		}
+	}
+}
+
+// readMjpegPart 读取multipart中的一帧jpeg数据
+// wda的boundary声明与实际分隔符并不一致，因此不依赖boundary，只解析每一段的header，
+// 有Content-Length时按长度读取，否则读取到jpeg结束标记0xFFD9
+func readMjpegPart(reader *bufio.Reader) ([]byte, error) {
+	contentLength := -1
+	inHeader := false
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		line = strings.TrimSpace(line)
+
+		if line == "" {
+			if inHeader {
+				break
+			}
+			continue
+		}
+		if strings.HasPrefix(line, "--") {
+			inHeader = true
+			continue
+		}
+
+		inHeader = true
+		name, value, found := strings.Cut(line, ":")
+		if found && strings.EqualFold(strings.TrimSpace(name), "Content-Length") {
+			contentLength, err = strconv.Atoi(strings.TrimSpace(value))
+			if err != nil {
+				return nil, fmt.Errorf(" Parse mjpeg Content-Length failed :%w", err)
+			}
+		}
+	}
+
+	if contentLength > maxFrameSize {
+		return nil, fmt.Errorf(" Mjpeg frame is too large : %d ", contentLength)
+	}
+	if contentLength >= 0 {
+		data := make([]byte, contentLength)
+		if _, err := io.ReadFull(reader, data); err != nil {
+			return nil, err
+		}
+		return data, nil
+	}
+
+	var data []byte
+	for {
+		b, err := reader.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, b)
+		if len(data) > maxFrameSize {
+			return nil, fmt.Errorf(" Mjpeg frame is too large ")
+		}
+		if len(data) >= 4 && data[len(data)-2] == 0xFF && data[len(data)-1] == 0xD9 {
+			return data, nil
+		}
+	}
+}
+
+// MjpegUrl 根据wda地址生成mjpeg服务地址，port为0时使用默认端口9100
+func (session *WdaSession) MjpegUrl(port int) (string, error) {
+	if port == 0 {
+		port = DefaultMjpegPort
+	}
+
+	wdaUrl, err := url.Parse(session.url)
+	if err != nil {
+		return StringNull, fmt.Errorf(" Parse wda url failed :%w", err)
+	}
+
+	return (&url.URL{
+		Scheme: wdaUrl.Scheme,
+		Host:   net.JoinHostPort(wdaUrl.Hostname(), strconv.Itoa(port)),
+	}).String(), nil
+}
+
+// GetMjpegStream 获取当前设备的mjpeg视频流，port为0时使用默认端口
+func (session *WdaSession) GetMjpegStream(port int) (*MjpegStream, error) {
+	streamUrl, err := session.MjpegUrl(port)
+	if err != nil {
+		return nil, err
+	}
+	return NewMjpegStream(streamUrl), nil
+}
@@ -0,0 +1,332 @@
+package WdaGo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	log "github.com/Ning9527fff/MyLog"
+)
+
+// HealthState wda健康状态
+type HealthState int64
+
+const (
+	HealthUnknown     HealthState = 0
+	HealthHealthy     HealthState = 1
+	HealthNotReady    HealthState = 2
+	HealthUnreachable HealthState = 3
+	HealthSessionLost HealthState = 4
+	HealthRecovering  HealthState = 5
+	HealthFailed      HealthState = 6
+)
+
+const (
+	DefaultHealthInterval   = 10 * time.Second
+	DefaultFailureThreshold = 3
+	DefaultMaxRecoveries    = 3
+	DefaultRecoveryTimeout  = time.Minute
+)
+
+var healthStateNames = map[HealthState]string{
+	HealthUnknown:     "unknown",
+	HealthHealthy:     "healthy",
+	HealthNotReady:    "notReady",
+	HealthUnreachable: "unreachable",
+	HealthSessionLost: "sessionLost",
+	HealthRecovering:  "recovering",
+	HealthFailed:      "failed",
+}
+
+func (state HealthState) String() string {
+	return enumName(healthStateNames, state)
+}
+
+func (state HealthState) MarshalJSON() ([]byte, error) {
+	return json.Marshal(state.String())
+}
+
+func (state *HealthState) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, healthStateNames, state)
+}
+
+// HealthEvent 健康状态变化或者恢复过程中的事件
+//
+//	Attempt 恢复时为第几次尝试，从1开始
+//	Err     检查或者恢复失败的原因
+type HealthEvent struct {
+	State     HealthState  `json:"state"`
+	Previous  HealthState  `json:"previous"`
+	Time      time.Time    `json:"time"`
+	Status    *PhoneStatus `json:"status,omitempty"`
+	SessionId string       `json:"sessionId,omitempty"`
+	Attempt   int          `json:"attempt,omitempty"`
+	Err       error        `json:"-"`
+}
+
+// RecoveryHook 恢复wda的操作，如重新执行xcodebuild、重启端口转发，返回错误时本次恢复失败
+type RecoveryHook func(ctx context.Context, event HealthEvent) error
+
+// HealthMonitorOption 健康检查参数
+//
+//	Interval         检查间隔
+//	FailureThreshold 连续失败多少次后开始恢复
+//	CheckSession     是否检查session是否有效，session失效时只重新创建session
+//	Hooks            恢复时依次执行的操作
+//	MaxRecoveries    单次故障最多尝试恢复的次数，全部失败后状态为failed，之后继续检查
+//	RecoveryTimeout  执行Hooks后等待wda就绪的时间
+//	AfterRecover     session重新创建后执行，如重新启动被测app
+//	OnEvent          状态变化以及恢复过程中的回调
+type HealthMonitorOption struct {
+	Interval         time.Duration
+	FailureThreshold int
+	CheckSession     bool
+	Hooks            []RecoveryHook
+	MaxRecoveries    int
+	RecoveryTimeout  time.Duration
+	AfterRecover     func(session *WdaSession) error
+	OnEvent          func(event HealthEvent)
+}
+
+// HealthMonitor 定期检查wda状态，在wda不可用或者session失效时自动恢复
+type HealthMonitor struct {
+	session *WdaSession
+	option  HealthMonitorOption
+
+	mu         sync.Mutex
+	state      HealthState
+	failures   int
+	recoveries int
+	changed    chan struct{}
+	cancel     context.CancelFunc
+	done       chan struct{}
+}
+
+// StartHealthMonitor 启动健康检查，调用Stop停止
+// 恢复时会重新创建session并替换sessionId，恢复期间的其他操作会失败，可以使用WaitHealthy等待恢复后重试
+func (session *WdaSession) StartHealthMonitor(option HealthMonitorOption) *HealthMonitor {
+	if option.Interval <= 0 {
+		option.Interval = DefaultHealthInterval
+	}
+	if option.FailureThreshold <= 0 {
+		option.FailureThreshold = DefaultFailureThreshold
+	}
+	if option.MaxRecoveries <= 0 {
+		option.MaxRecoveries = DefaultMaxRecoveries
+	}
+	if option.RecoveryTimeout <= 0 {
+		option.RecoveryTimeout = DefaultRecoveryTimeout
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	monitor := &HealthMonitor{
+		session: session,
+		option:  option,
+		changed: make(chan struct{}),
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+	go monitor.run(ctx)
+	return monitor
+}
+
+// Stop 停止健康检查，等待正在执行的检查或恢复结束
+func (monitor *HealthMonitor) Stop() {
+	monitor.cancel()
+	<-monitor.done
+}
+
+// State 当前健康状态
+func (monitor *HealthMonitor) State() HealthState {
+	monitor.mu.Lock()
+	defer monitor.mu.Unlock()
+	return monitor.state
+}
+
+// Recoveries 累计成功恢复的次数
+func (monitor *HealthMonitor) Recoveries() int {
+	monitor.mu.Lock()
+	defer monitor.mu.Unlock()
+	return monitor.recoveries
+}
+
+// WaitHealthy 等待wda恢复为healthy，ctx结束时返回错误，用于在恢复后继续执行
+func (monitor *HealthMonitor) WaitHealthy(ctx context.Context) error {
+	for {
+		monitor.mu.Lock()
+		state, changed := monitor.state, monitor.changed
+		monitor.mu.Unlock()
+
+		if state == HealthHealthy {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf(" Wait for wda healthy failed, state is %v :%v", state, ctx.Err())
+		case <-changed:
+		}
+	}
+}
+
+func (monitor *HealthMonitor) run(ctx context.Context) {
+	defer close(monitor.done)
+
+	ticker := time.NewTicker(monitor.option.Interval)
+	defer ticker.Stop()
+
+	for {
+		monitor.check(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// check 检查一次wda状态，连续失败达到阈值时恢复
+func (monitor *HealthMonitor) check(ctx context.Context) {
+	state, status, err := monitor.probe()
+	monitor.setState(state, HealthEvent{Status: status, Err: err})
+
+	if state == HealthHealthy {
+		monitor.mu.Lock()
+		monitor.failures = 0
+		monitor.mu.Unlock()
+		return
+	}
+
+	monitor.mu.Lock()
+	monitor.failures++
+	failures := monitor.failures
+	monitor.mu.Unlock()
+
+	// session失效时wda本身是正常的，直接重新创建session
+	if state == HealthSessionLost || failures >= monitor.option.FailureThreshold {
+		monitor.recover(ctx, state)
+	}
+}
+
+// probe 获取wda状态，CheckSession时同时检查session
+func (monitor *HealthMonitor) probe() (HealthState, *PhoneStatus, error) {
+	status, err := monitor.session.GetStatus()
+	if err != nil {
+		return HealthUnreachable, nil, err
+	}
+	if !status.IsReady {
+		return HealthNotReady, status, fmt.Errorf(" Wda is not ready ")
+	}
+
+	if monitor.option.CheckSession && monitor.session.SessionId() != "" {
+		valid, err := monitor.session.CheckSession()
+		if err != nil || !valid {
+			if err == nil {
+				err = fmt.Errorf(" Session %v is invalid ", monitor.session.SessionId())
+			}
+			return HealthSessionLost, status, err
+		}
+	}
+	return HealthHealthy, status, nil
+}
+
+// recover 依次执行恢复操作，等待wda就绪后重新创建session
+func (monitor *HealthMonitor) recover(ctx context.Context, reason HealthState) {
+	for attempt := 1; attempt <= monitor.option.MaxRecoveries; attempt++ {
+		if ctx.Err() != nil {
+			return
+		}
+		monitor.setState(HealthRecovering, HealthEvent{Attempt: attempt})
+
+		err := monitor.recoverOnce(ctx, reason, attempt)
+		if err == nil {
+			monitor.mu.Lock()
+			monitor.failures = 0
+			monitor.recoveries++
+			monitor.mu.Unlock()
+			monitor.setState(HealthHealthy, HealthEvent{Attempt: attempt})
+			return
+		}
+
+		log.ErrorF("Recover wda failed, attempt %d: %v", attempt, err)
+		monitor.emit(HealthEvent{State: HealthRecovering, Previous: HealthRecovering, Attempt: attempt, Err: err})
+		// session失效只需要重新创建session，失败后按wda异常处理
+		reason = HealthUnreachable
+	}
+
+	monitor.mu.Lock()
+	monitor.failures = 0
+	monitor.mu.Unlock()
+	monitor.setState(HealthFailed, HealthEvent{Err: fmt.Errorf(" Recover wda failed after %d attempts ", monitor.option.MaxRecoveries)})
+}
+
+func (monitor *HealthMonitor) recoverOnce(ctx context.Context, reason HealthState, attempt int) error {
+	session := monitor.session
+
+	if reason != HealthSessionLost {
+		event := HealthEvent{State: reason, Attempt: attempt, SessionId: session.SessionId(), Time: time.Now()}
+		for _, hook := range monitor.option.Hooks {
+			if err := hook(ctx, event); err != nil {
+				return fmt.Errorf(" Recovery hook failed :%w", err)
+			}
+		}
+
+		err := WaitUntil(monitor.option.RecoveryTimeout, DefaultPollInterval, func() (bool, error) {
+			if ctx.Err() != nil {
+				return false, ctx.Err()
+			}
+			status, err := session.GetStatus()
+			return err == nil && status.IsReady, nil
+		})
+		if err != nil {
+			return fmt.Errorf(" Wait for wda ready failed :%w", err)
+		}
+	}
+
+	// 没有session时不需要重新创建
+	if session.SessionId() != "" {
+		if err := session.RecreateSession(); err != nil {
+			return err
+		}
+	}
+	if monitor.option.AfterRecover != nil {
+		if err := monitor.option.AfterRecover(session); err != nil {
+			return fmt.Errorf(" After recover failed :%w", err)
+		}
+	}
+	return nil
+}
+
+// setState 更新状态，状态变化时发送事件
+func (monitor *HealthMonitor) setState(state HealthState, event HealthEvent) {
+	monitor.mu.Lock()
+	previous := monitor.state
+	if previous == state {
+		monitor.mu.Unlock()
+		return
+	}
+	monitor.state = state
+	close(monitor.changed)
+	monitor.changed = make(chan struct{})
+	monitor.mu.Unlock()
+
+	event.State = state
+	event.Previous = previous
+	monitor.emit(event)
+}
+
+func (monitor *HealthMonitor) emit(event HealthEvent) {
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	if event.SessionId == "" {
+		event.SessionId = monitor.session.SessionId()
+	}
+	if event.Err != nil {
+		log.DebugF("Wda health %v -> %v: %v", event.Previous, event.State, event.Err)
+	}
+	if monitor.option.OnEvent != nil {
+		monitor.option.OnEvent(event)
+	}
+}
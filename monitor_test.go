@@ -0,0 +1,260 @@
+package WdaGo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeHealthWda 模拟wda的/status和session接口
+//
+//	down  /status返回500，恢复操作中设置为false模拟wda重启
+//	lost  查询session时返回其他sessionId，模拟session失效，创建session后恢复
+//	calls 按顺序记录收到的请求和恢复操作
+type fakeHealthWda struct {
+	mu      sync.Mutex
+	down    bool
+	lost    bool
+	created int
+	calls   []string
+}
+
+func (fake *fakeHealthWda) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	switch {
+	case r.URL.Path == "/status":
+		fake.calls = append(fake.calls, "status")
+		if fake.down {
+			w.WriteHeader(http.StatusInternalServerError)
+			writeValue(w, `{"error":"unknown error","message":"wda is down"}`)
+			return
+		}
+		writeValue(w, `{"ready":true}`)
+	case r.Method == http.MethodPost && r.URL.Path == "/session":
+		fake.created++
+		fake.lost = false
+		fake.calls = append(fake.calls, "create")
+		sessionId := "session-" + strconv.Itoa(fake.created)
+		w.Write([]byte(`{"value":{"sessionId":"` + sessionId + `","capabilities":{}},"sessionId":"` + sessionId + `"}`))
+	case r.Method == http.MethodDelete:
+		fake.calls = append(fake.calls, "delete")
+		w.Write([]byte(`{"value":null,"sessionId":null}`))
+	default:
+		sessionId := strings.TrimPrefix(r.URL.Path, "/session/")
+		if fake.lost {
+			sessionId = "other"
+		}
+		w.Write([]byte(`{"value":null,"sessionId":"` + sessionId + `"}`))
+	}
+}
+
+func (fake *fakeHealthWda) record(call string) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	fake.calls = append(fake.calls, call)
+}
+
+func (fake *fakeHealthWda) setDown(down bool) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	fake.down = down
+}
+
+// recoverCalls 恢复操作相关的记录，去掉/status
+func (fake *fakeHealthWda) recoverCalls() []string {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	var calls []string
+	for _, call := range fake.calls {
+		if call != "status" {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// newTestMonitor 创建不启动定时检查的HealthMonitor，直接调用check
+func newTestMonitor(session *WdaSession, option HealthMonitorOption, events *[]HealthEvent) *HealthMonitor {
+	option.OnEvent = func(event HealthEvent) { *events = append(*events, event) }
+	if option.RecoveryTimeout == 0 {
+		option.RecoveryTimeout = time.Second
+	}
+	return &HealthMonitor{session: session, option: option, changed: make(chan struct{})}
+}
+
+func states(events []HealthEvent) []HealthState {
+	var result []HealthState
+	for _, event := range events {
+		result = append(result, event.State)
+	}
+	return result
+}
+
+func TestHealthMonitorFailureThreshold(t *testing.T) {
+	fake := &fakeHealthWda{down: true}
+	session := newTestSession(t, fake)
+
+	var events []HealthEvent
+	monitor := newTestMonitor(session, HealthMonitorOption{
+		FailureThreshold: 3,
+		MaxRecoveries:    1,
+		Hooks: []RecoveryHook{
+			func(ctx context.Context, event HealthEvent) error {
+				fake.record("hook 1")
+				if event.State != HealthUnreachable || event.Attempt != 1 || event.SessionId != testSessionId {
+					t.Errorf("hook event = %+v", event)
+				}
+				return nil
+			},
+			func(ctx context.Context, event HealthEvent) error {
+				fake.record("hook 2")
+				fake.setDown(false)
+				return nil
+			},
+		},
+		AfterRecover: func(*WdaSession) error {
+			fake.record("after recover")
+			return nil
+		},
+	}, &events)
+
+	// 未达到阈值时不恢复
+	for i := 0; i < 2; i++ {
+		monitor.check(context.Background())
+	}
+	if calls := fake.recoverCalls(); len(calls) != 0 || monitor.State() != HealthUnreachable {
+		t.Fatalf("state = %v calls = %v before threshold, want unreachable without recovery", monitor.State(), calls)
+	}
+
+	// 按顺序执行恢复操作，wda就绪后重新创建session
+	monitor.check(context.Background())
+	wantCalls := []string{"hook 1", "hook 2", "delete", "create", "after recover"}
+	if calls := fake.recoverCalls(); !slices.Equal(calls, wantCalls) {
+		t.Errorf("recover calls = %v, want %v", calls, wantCalls)
+	}
+	wantStates := []HealthState{HealthUnreachable, HealthRecovering, HealthHealthy}
+	if got := states(events); !slices.Equal(got, wantStates) {
+		t.Errorf("events = %v, want %v", got, wantStates)
+	}
+	if monitor.Recoveries() != 1 || monitor.failures != 0 || session.SessionId() != "session-1" {
+		t.Errorf("recoveries = %d failures = %d session = %q", monitor.Recoveries(), monitor.failures, session.SessionId())
+	}
+}
+
+func TestHealthMonitorMaxRecoveries(t *testing.T) {
+	fake := &fakeHealthWda{down: true}
+	session := newTestSession(t, fake)
+
+	hookErr := errors.New("xcodebuild failed")
+	var events []HealthEvent
+	monitor := newTestMonitor(session, HealthMonitorOption{
+		FailureThreshold: 1,
+		MaxRecoveries:    2,
+		Hooks: []RecoveryHook{func(ctx context.Context, event HealthEvent) error {
+			fake.record("hook " + strconv.Itoa(event.Attempt))
+			return hookErr
+		}},
+	}, &events)
+
+	monitor.check(context.Background())
+	if calls := fake.recoverCalls(); !slices.Equal(calls, []string{"hook 1", "hook 2"}) {
+		t.Errorf("recover calls = %v, want two failed hooks without recreating session", calls)
+	}
+	if monitor.State() != HealthFailed || monitor.Recoveries() != 0 || monitor.failures != 0 {
+		t.Errorf("state = %v recoveries = %d failures = %d, want failed", monitor.State(), monitor.Recoveries(), monitor.failures)
+	}
+
+	// 每次恢复失败发送一个带错误的事件，全部失败后状态为failed
+	wantStates := []HealthState{HealthUnreachable, HealthRecovering, HealthRecovering, HealthRecovering, HealthFailed}
+	if got := states(events); !slices.Equal(got, wantStates) {
+		t.Fatalf("events = %v, want %v", got, wantStates)
+	}
+	for i, attempt := range []int{1, 2} {
+		event := events[2+i]
+		if event.Attempt != attempt || !errors.Is(event.Err, hookErr) {
+			t.Errorf("failed recovery event = %+v, want attempt %d with hook error", event, attempt)
+		}
+	}
+	if events[4].Err == nil {
+		t.Error("failed event without error")
+	}
+
+	// 下一次检查重新开始计数
+	fake.setDown(false)
+	monitor.check(context.Background())
+	if monitor.State() != HealthHealthy {
+		t.Errorf("state after wda is back = %v, want healthy", monitor.State())
+	}
+}
+
+func TestHealthMonitorSessionLost(t *testing.T) {
+	fake := &fakeHealthWda{lost: true}
+	session := newTestSession(t, fake)
+
+	var events []HealthEvent
+	monitor := newTestMonitor(session, HealthMonitorOption{
+		FailureThreshold: 3,
+		MaxRecoveries:    1,
+		CheckSession:     true,
+		Hooks: []RecoveryHook{func(ctx context.Context, event HealthEvent) error {
+			fake.record("hook")
+			return nil
+		}},
+	}, &events)
+
+	// session失效时wda是正常的，不等待阈值，不执行恢复操作，直接重新创建session
+	monitor.check(context.Background())
+	if calls := fake.recoverCalls(); !slices.Equal(calls, []string{"delete", "create"}) {
+		t.Errorf("recover calls = %v, want recreate session only", calls)
+	}
+	wantStates := []HealthState{HealthSessionLost, HealthRecovering, HealthHealthy}
+	if got := states(events); !slices.Equal(got, wantStates) {
+		t.Errorf("events = %v, want %v", got, wantStates)
+	}
+	if session.SessionId() != "session-1" || monitor.Recoveries() != 1 {
+		t.Errorf("session = %q recoveries = %d, want session-1 after one recovery", session.SessionId(), monitor.Recoveries())
+	}
+}
+
+func TestHealthMonitorWaitHealthy(t *testing.T) {
+	fake := &fakeHealthWda{down: true}
+	session := newTestSession(t, fake)
+
+	monitor := session.StartHealthMonitor(HealthMonitorOption{
+		Interval:         10 * time.Millisecond,
+		FailureThreshold: 2,
+		Hooks: []RecoveryHook{func(ctx context.Context, event HealthEvent) error {
+			fake.setDown(false)
+			return nil
+		}},
+	})
+	defer monitor.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := monitor.WaitHealthy(ctx); err != nil {
+		t.Fatalf("WaitHealthy() error = %v", err)
+	}
+	if monitor.Recoveries() != 1 {
+		t.Errorf("Recoveries() = %d, want 1", monitor.Recoveries())
+	}
+
+	// wda再次异常后，ctx结束时返回错误
+	fake.mu.Lock()
+	fake.down = true
+	fake.mu.Unlock()
+	monitor.Stop()
+	monitor.check(context.Background())
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := monitor.WaitHealthy(ctx); err == nil || !strings.Contains(err.Error(), "unreachable") {
+		t.Errorf("WaitHealthy() error = %v, want timeout while unreachable", err)
+	}
+}
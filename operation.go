@@ -0,0 +1,60 @@
+package WdaGo
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// operationId 每次方法调用的id，用于关联方法调用和其中的请求
+var operationId atomic.Uint64
+
+// Operation 一次WdaSession或者Element方法的调用，方法内发送的wda请求都属于该调用，
+// CommandRecord.Operation 为请求所属的调用
+//
+// 方法内调用的其他方法不会生成新的调用，如 Element.Click 中查找元素和点击元素的请求都属于 Element.Click
+type Operation struct {
+	Id      uint64    `json:"id"`
+	Name    string    `json:"name"`
+	Started time.Time `json:"started"`
+}
+
+// begin 开始一次方法调用，name为 类型.方法，如 WdaSession.TapWithLocation，
+// 返回绑定本次调用的session副本和结束调用的函数，方法内需要使用副本发送请求和调用其他方法
+//
+//	session, end := session.begin("WdaSession.TapWithLocation")
+//	defer end()
+//
+// 已经在调用中时返回session本身，没有事件订阅时不记录调用
+func (session *WdaSession) begin(name string) (*WdaSession, func()) {
+	if session.operation != nil || !session.events.hasSubscribers() {
+		return session, func() {}
+	}
+
+	operation := &Operation{Id: operationId.Add(1), Name: name, Started: time.Now()}
+	bound := &WdaSession{sessionState: session.sessionState, operation: operation}
+	session.events.operationStarted(operation, session.SessionId())
+	return bound, func() {
+		session.events.operationFinished(operation, session.SessionId())
+	}
+}
+
+// begin 与 WdaSession.begin 相同，返回绑定本次调用的元素句柄
+func (element *Element) begin(name string) (*Element, func()) {
+	session, end := element.session.begin(name)
+	return &Element{session: session, Locator: element.Locator, Timeout: element.Timeout}, end
+}
+
+// get 发送GET请求，请求属于当前的方法调用
+func (session *WdaSession) get(api string) ([]byte, error) {
+	return session.client.getRequest(session.operation, api, session.headers)
+}
+
+// post 发送POST请求，请求属于当前的方法调用
+func (session *WdaSession) post(api string, data interface{}) ([]byte, error) {
+	return session.client.postRequest(session.operation, api, data, session.headers)
+}
+
+// delete 发送DELETE请求，请求属于当前的方法调用
+func (session *WdaSession) delete(api string) ([]byte, error) {
+	return session.client.deleteRequest(session.operation, api, session.headers)
+}
@@ -0,0 +1,342 @@
+// Package otelwda 为WdaSession提供OpenTelemetry链路和指标
+//
+// 每次调用WdaSession或者Element方法生成一个span，名称为方法名，如 Element.Click，
+// 方法内的每个wda请求生成一个子span，名称为请求方法和路径，如 POST /element/{elementId}/click，
+// 方法内调用的其他方法不再生成span，如 Element.Click 生成一个span，查找元素和点击元素的请求为它的两个子span。
+// 请求重试时每次请求在请求span下生成一个子span；同时记录命令耗时、按错误码统计的错误数以及每个设备正在执行的命令数
+//
+//	exporter := tracetest.NewInMemoryExporter()
+//	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+//	reader := sdkmetric.NewManualReader()
+//	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+//
+//	instrumentation, err := otelwda.Instrument(session, otelwda.Config{
+//		TracerProvider: tracerProvider,
+//		MeterProvider:  meterProvider,
+//		Device:         "iPhone-15",
+//	})
+//	defer instrumentation.Close()
+package otelwda
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+
+	WdaGo "github.com/Ning9527fff/WdaGo"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ScopeName tracer和meter的名称
+const ScopeName = "github.com/Ning9527fff/WdaGo/otelwda"
+
+// 属性名
+const (
+	AttributeDevice     = attribute.Key("wda.device")
+	AttributeSessionId  = attribute.Key("wda.session.id")
+	AttributeEndpoint   = attribute.Key("wda.endpoint")
+	AttributeOperation  = attribute.Key("wda.operation")
+	AttributeErrorCode  = attribute.Key("wda.error.code")
+	AttributeAttempt    = attribute.Key("wda.attempt")
+	AttributeHttpMethod = attribute.Key("http.request.method")
+	AttributeHttpStatus = attribute.Key("http.response.status_code")
+)
+
+// 错误码，wda返回的错误使用wda的错误码，如 no such element
+const (
+	ErrorCodeTransport = WdaGo.ErrorCodeTransport
+	ErrorCodeHttp      = WdaGo.ErrorCodeHttp
+)
+
+// Config 链路和指标参数
+//
+//	TracerProvider、MeterProvider 为空时使用otel的全局设置
+//	Device                        设备名，为空时使用wda地址
+type Config struct {
+	TracerProvider trace.TracerProvider
+	MeterProvider  metric.MeterProvider
+	Device         string
+}
+
+// Instrumentation 一个session的链路和指标
+type Instrumentation struct {
+	tracer       trace.Tracer
+	device       attribute.KeyValue
+	subscription *WdaGo.Subscription
+
+	duration metric.Float64Histogram
+	errors   metric.Int64Counter
+	retries  metric.Int64Counter
+	inFlight metric.Int64UpDownCounter
+
+	mu         sync.Mutex
+	parent     context.Context
+	operations map[uint64]*operation
+	commands   map[uint64]*command
+}
+
+// operation 正在执行的方法调用，last为方法内最后完成的命令
+type operation struct {
+	ctx  context.Context
+	span trace.Span
+	last *WdaGo.CommandRecord
+	err  error
+}
+
+// command 正在执行的命令
+type command struct {
+	ctx       context.Context
+	span      trace.Span
+	operation *operation
+}
+
+// Instrument 订阅session的命令事件生成链路和指标，调用Close取消
+func Instrument(session *WdaGo.WdaSession, config Config) (*Instrumentation, error) {
+	if config.TracerProvider == nil {
+		config.TracerProvider = otel.GetTracerProvider()
+	}
+	if config.MeterProvider == nil {
+		config.MeterProvider = otel.GetMeterProvider()
+	}
+	if config.Device == "" {
+		config.Device = WdaGo.DeviceName(session.Url())
+	}
+
+	meter := config.MeterProvider.Meter(ScopeName)
+	instrumentation := &Instrumentation{
+		tracer:     config.TracerProvider.Tracer(ScopeName),
+		device:     AttributeDevice.String(config.Device),
+		parent:     context.Background(),
+		operations: make(map[uint64]*operation),
+		commands:   make(map[uint64]*command),
+	}
+
+	var err error
+	instrumentation.duration, err = meter.Float64Histogram("wda.command.duration",
+		metric.WithDescription("Duration of wda commands including retries"), metric.WithUnit("s"))
+	if err != nil {
+		return nil, err
+	}
+	instrumentation.errors, err = meter.Int64Counter("wda.command.errors",
+		metric.WithDescription("Number of failed wda commands by error code"), metric.WithUnit("{command}"))
+	if err != nil {
+		return nil, err
+	}
+	instrumentation.retries, err = meter.Int64Counter("wda.command.retries",
+		metric.WithDescription("Number of retried wda requests"), metric.WithUnit("{request}"))
+	if err != nil {
+		return nil, err
+	}
+	instrumentation.inFlight, err = meter.Int64UpDownCounter("wda.command.in_flight",
+		metric.WithDescription("Number of wda commands in flight per device"), metric.WithUnit("{command}"))
+	if err != nil {
+		return nil, err
+	}
+
+	instrumentation.subscription = session.Events().Subscribe(instrumentation.handle,
+		WdaGo.EventOperationStarted, WdaGo.EventOperationFinished,
+		WdaGo.EventCommandStarted, WdaGo.EventCommandRetried, WdaGo.EventCommandFinished)
+	return instrumentation, nil
+}
+
+// Close 取消订阅，之后的命令不再生成链路和指标
+func (instrumentation *Instrumentation) Close() {
+	instrumentation.subscription.Unsubscribe()
+}
+
+// SetParent 设置之后方法span的父span，如测试用例的span，ctx为nil时方法span为根span
+func (instrumentation *Instrumentation) SetParent(ctx context.Context) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	instrumentation.mu.Lock()
+	defer instrumentation.mu.Unlock()
+	instrumentation.parent = ctx
+}
+
+func (instrumentation *Instrumentation) handle(event WdaGo.Event) {
+	switch event.Type {
+	case WdaGo.EventOperationStarted:
+		instrumentation.startOperation(event)
+	case WdaGo.EventOperationFinished:
+		instrumentation.finishOperation(event)
+	case WdaGo.EventCommandStarted:
+		instrumentation.start(event)
+	case WdaGo.EventCommandRetried:
+		instrumentation.retry(event)
+	case WdaGo.EventCommandFinished:
+		instrumentation.finish(event)
+	}
+}
+
+// startOperation 方法调用开始时生成方法span
+func (instrumentation *Instrumentation) startOperation(event WdaGo.Event) {
+	attributes := []attribute.KeyValue{
+		instrumentation.device,
+		AttributeOperation.String(event.Operation.Name),
+	}
+	if event.SessionId != "" {
+		attributes = append(attributes, AttributeSessionId.String(event.SessionId))
+	}
+
+	instrumentation.mu.Lock()
+	defer instrumentation.mu.Unlock()
+	ctx, span := instrumentation.tracer.Start(instrumentation.parent, event.Operation.Name,
+		trace.WithTimestamp(event.Operation.Started),
+		trace.WithAttributes(attributes...))
+	instrumentation.operations[event.Operation.Id] = &operation{ctx: ctx, span: span}
+}
+
+// finishOperation 结束方法span，http状态码和错误码为方法内最后一个命令的结果，最后一个命令失败时span标记为失败
+func (instrumentation *Instrumentation) finishOperation(event WdaGo.Event) {
+	instrumentation.mu.Lock()
+	op := instrumentation.operations[event.Operation.Id]
+	delete(instrumentation.operations, event.Operation.Id)
+	var last *WdaGo.CommandRecord
+	var err error
+	if op != nil {
+		last, err = op.last, op.err
+	}
+	instrumentation.mu.Unlock()
+	if op == nil {
+		return
+	}
+
+	end := event.Operation.Started.Add(event.Duration)
+	if event.SessionId != "" {
+		op.span.SetAttributes(AttributeSessionId.String(event.SessionId))
+	}
+	if last != nil && last.StatusCode != 0 {
+		op.span.SetAttributes(AttributeHttpStatus.Int(last.StatusCode))
+	}
+	if err != nil {
+		op.span.SetAttributes(AttributeErrorCode.String(WdaGo.ErrorCode(last)))
+		op.span.SetStatus(codes.Error, strings.TrimSpace(err.Error()))
+	}
+	op.span.End(trace.WithTimestamp(end))
+}
+
+// start 命令开始时在所属的方法span下生成请求span，不属于方法调用的命令，如转发的请求，直接使用SetParent设置的父span
+func (instrumentation *Instrumentation) start(event WdaGo.Event) {
+	record := event.Command
+	attributes := []attribute.KeyValue{
+		instrumentation.device,
+		AttributeEndpoint.String(event.Endpoint),
+		AttributeHttpMethod.String(record.Method),
+	}
+	if event.SessionId != "" {
+		attributes = append(attributes, AttributeSessionId.String(event.SessionId))
+	}
+
+	instrumentation.mu.Lock()
+	parent := instrumentation.parent
+	var op *operation
+	if record.Operation != nil {
+		op = instrumentation.operations[record.Operation.Id]
+	}
+	instrumentation.mu.Unlock()
+	if op != nil {
+		parent = op.ctx
+		attributes = append(attributes, AttributeOperation.String(record.Operation.Name))
+	}
+
+	ctx, span := instrumentation.tracer.Start(parent, record.Method+" "+event.Endpoint,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithTimestamp(record.CommandStarted),
+		trace.WithAttributes(attributes...))
+
+	instrumentation.mu.Lock()
+	instrumentation.commands[record.Id] = &command{ctx: ctx, span: span, operation: op}
+	instrumentation.mu.Unlock()
+
+	instrumentation.inFlight.Add(ctx, 1, metric.WithAttributes(instrumentation.device))
+}
+
+// retry 失败的请求生成子span
+func (instrumentation *Instrumentation) retry(event WdaGo.Event) {
+	record := event.Command
+	instrumentation.mu.Lock()
+	cmd := instrumentation.commands[record.Id]
+	instrumentation.mu.Unlock()
+	if cmd == nil {
+		return
+	}
+
+	instrumentation.attemptSpan(cmd, record, event.Err)
+	instrumentation.retries.Add(cmd.ctx, 1, metric.WithAttributes(
+		instrumentation.device, AttributeEndpoint.String(event.Endpoint)))
+}
+
+func (instrumentation *Instrumentation) finish(event WdaGo.Event) {
+	record := event.Command
+	instrumentation.mu.Lock()
+	cmd := instrumentation.commands[record.Id]
+	delete(instrumentation.commands, record.Id)
+	if cmd != nil && cmd.operation != nil {
+		cmd.operation.last, cmd.operation.err = record, event.Err
+	}
+	instrumentation.mu.Unlock()
+	if cmd == nil {
+		return
+	}
+
+	// 发生过重试时最后一次请求同样生成子span
+	if record.Attempt > 1 {
+		instrumentation.attemptSpan(cmd, record, event.Err)
+	}
+
+	end := record.CommandStarted.Add(record.TotalDuration)
+	attributes := []attribute.KeyValue{
+		instrumentation.device,
+		AttributeEndpoint.String(event.Endpoint),
+		AttributeHttpMethod.String(record.Method),
+	}
+	if record.StatusCode != 0 {
+		attributes = append(attributes, AttributeHttpStatus.Int(record.StatusCode))
+	}
+
+	span := cmd.span
+	if record.StatusCode != 0 {
+		span.SetAttributes(AttributeHttpStatus.Int(record.StatusCode))
+	}
+	if event.SessionId != "" {
+		span.SetAttributes(AttributeSessionId.String(event.SessionId))
+	}
+	if record.Attempt > 1 {
+		span.SetAttributes(AttributeAttempt.Int(record.Attempt))
+	}
+	if event.Err != nil {
+		code := WdaGo.ErrorCode(record)
+		span.SetAttributes(AttributeErrorCode.String(code))
+		span.RecordError(event.Err, trace.WithTimestamp(end))
+		span.SetStatus(codes.Error, strings.TrimSpace(event.Err.Error()))
+		attributes = append(attributes, AttributeErrorCode.String(code))
+		instrumentation.errors.Add(cmd.ctx, 1, metric.WithAttributes(attributes...))
+	}
+	span.End(trace.WithTimestamp(end))
+
+	instrumentation.duration.Record(cmd.ctx, record.TotalDuration.Seconds(), metric.WithAttributes(attributes...))
+	instrumentation.inFlight.Add(cmd.ctx, -1, metric.WithAttributes(instrumentation.device))
+}
+
+// attemptSpan 一次请求的子span
+func (instrumentation *Instrumentation) attemptSpan(cmd *command, record *WdaGo.CommandRecord, err error) {
+	_, span := instrumentation.tracer.Start(cmd.ctx, "attempt "+strconv.Itoa(record.Attempt),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithTimestamp(record.Started),
+		trace.WithAttributes(AttributeAttempt.Int(record.Attempt)))
+	if record.StatusCode != 0 {
+		span.SetAttributes(AttributeHttpStatus.Int(record.StatusCode))
+	}
+	end := record.Started.Add(record.Duration)
+	if err != nil {
+		span.RecordError(err, trace.WithTimestamp(end))
+		span.SetStatus(codes.Error, strings.TrimSpace(err.Error()))
+	}
+	span.End(trace.WithTimestamp(end))
+}
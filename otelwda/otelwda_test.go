@@ -0,0 +1,335 @@
+package otelwda
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	WdaGo "github.com/Ning9527fff/WdaGo"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const testSessionId = "test-session"
+
+// testWda 模拟wda，/status 的前failStatus次请求直接断开连接，查找missing元素时返回no such element
+type testWda struct {
+	failStatus atomic.Int32
+}
+
+func (fake *testWda) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/session/"+testSessionId)
+	if path == "/status" && fake.failStatus.Add(-1) >= 0 {
+		conn, _, _ := w.(http.Hijacker).Hijack()
+		conn.Close()
+		return
+	}
+
+	value := "null"
+	switch path {
+	case "/status":
+		value = `{"ready":true}`
+	case "/elements":
+		if body, _ := io.ReadAll(r.Body); strings.Contains(string(body), "missing") {
+			w.WriteHeader(http.StatusNotFound)
+			value = `{"error":"no such element","message":"not found"}`
+		} else {
+			value = `[{"ELEMENT":"e1"}]`
+		}
+	}
+	w.Header().Set("Content-Type", WdaGo.ContentTypeJson)
+	w.Write([]byte(`{"value":` + value + `,"sessionId":"` + testSessionId + `"}`))
+}
+
+// setup 启动模拟wda并使用内存exporter和手动reader记录链路和指标
+func setup(t *testing.T) (*WdaGo.WdaSession, *testWda, *Instrumentation, *tracetest.InMemoryExporter, *sdkmetric.ManualReader) {
+	t.Helper()
+	fake := &testWda{}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	session := WdaGo.GetWdaSession(server.URL)
+	session.AttachSession(testSessionId)
+
+	exporter := tracetest.NewInMemoryExporter()
+	reader := sdkmetric.NewManualReader()
+	instrumentation, err := Instrument(session, Config{
+		TracerProvider: sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)),
+		MeterProvider:  sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)),
+		Device:         "iPhone-15",
+	})
+	if err != nil {
+		t.Fatalf("Instrument() error = %v", err)
+	}
+	t.Cleanup(instrumentation.Close)
+	return session, fake, instrumentation, exporter, reader
+}
+
+func attributes(span tracetest.SpanStub) map[attribute.Key]attribute.Value {
+	result := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes {
+		result[kv.Key] = kv.Value
+	}
+	return result
+}
+
+// spanTree 按名称索引span，parent返回父span的名称
+type spanTree struct {
+	spans  []tracetest.SpanStub
+	byName map[string][]tracetest.SpanStub
+	byId   map[trace.SpanID]tracetest.SpanStub
+}
+
+func newSpanTree(spans []tracetest.SpanStub) *spanTree {
+	tree := &spanTree{spans: spans, byName: make(map[string][]tracetest.SpanStub), byId: make(map[trace.SpanID]tracetest.SpanStub)}
+	for _, span := range spans {
+		tree.byName[span.Name] = append(tree.byName[span.Name], span)
+		tree.byId[span.SpanContext.SpanID()] = span
+	}
+	return tree
+}
+
+func (tree *spanTree) parent(span tracetest.SpanStub) string {
+	if !span.Parent.IsValid() {
+		return ""
+	}
+	return tree.byId[span.Parent.SpanID()].Name
+}
+
+func TestSpans(t *testing.T) {
+	session, _, _, exporter, _ := setup(t)
+
+	if err := session.Element(WdaGo.Locator{Using: WdaGo.StrategyAccessibilityId, Value: "login"}).Click(); err != nil {
+		t.Fatalf("Click() error = %v", err)
+	}
+	if err := session.TapWithLocation(WdaGo.ElementLocation{X: 1, Y: 2}); err != nil {
+		t.Fatalf("TapWithLocation() error = %v", err)
+	}
+
+	// 每次方法调用一个span，方法内的请求为子span，Element.Click 中调用的 FindElements、ClickElement 不生成span
+	tests := []struct {
+		name      string
+		parent    string
+		endpoint  string
+		operation string
+	}{
+		{"Element.Click", "", "", "Element.Click"},
+		{"POST /elements", "Element.Click", "/elements", "Element.Click"},
+		{"POST /element/{elementId}/click", "Element.Click", "/element/{elementId}/click", "Element.Click"},
+		{"WdaSession.TapWithLocation", "", "", "WdaSession.TapWithLocation"},
+		{"POST /wda/tap", "WdaSession.TapWithLocation", "/wda/tap", "WdaSession.TapWithLocation"},
+	}
+	tree := newSpanTree(exporter.GetSpans())
+	if len(tree.spans) != len(tests) {
+		t.Fatalf("got %d spans, want %d", len(tree.spans), len(tests))
+	}
+	for _, tt := range tests {
+		spans := tree.byName[tt.name]
+		if len(spans) != 1 {
+			t.Fatalf("got %d %q spans, want 1", len(spans), tt.name)
+		}
+		span := spans[0]
+		if got := tree.parent(span); got != tt.parent {
+			t.Errorf("span %s parent = %q, want %q", span.Name, got, tt.parent)
+		}
+		attrs := attributes(span)
+		want := map[attribute.Key]string{
+			AttributeDevice:    "iPhone-15",
+			AttributeSessionId: testSessionId,
+			AttributeOperation: tt.operation,
+			AttributeEndpoint:  tt.endpoint,
+		}
+		if tt.endpoint != "" {
+			want[AttributeHttpMethod] = http.MethodPost
+		}
+		for key, value := range want {
+			if value == "" {
+				if _, ok := attrs[key]; ok {
+					t.Errorf("span %s has %s = %v, want none", span.Name, key, attrs[key].Emit())
+				}
+				continue
+			}
+			if got := attrs[key].AsString(); got != value {
+				t.Errorf("span %s %s = %q, want %q", span.Name, key, got, value)
+			}
+		}
+		if got := attrs[AttributeHttpStatus].AsInt64(); got != http.StatusOK {
+			t.Errorf("span %s status code = %d, want 200", span.Name, got)
+		}
+		if span.Status.Code == codes.Error {
+			t.Errorf("span %s has error status", span.Name)
+		}
+	}
+}
+
+func TestRetryAndErrorSpans(t *testing.T) {
+	session, fake, instrumentation, exporter, _ := setup(t)
+	session.SetRetry(WdaGo.RetryOption{Count: 2})
+	fake.failStatus.Store(2)
+
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)).Tracer("test")
+	ctx, parent := tracer.Start(context.Background(), "test case")
+	instrumentation.SetParent(ctx)
+
+	if _, err := session.GetStatus(); err != nil {
+		t.Fatalf("GetStatus() error = %v", err)
+	}
+	if _, err := session.FindElement(WdaGo.Locator{Using: WdaGo.StrategyAccessibilityId, Value: "missing"}); err == nil {
+		t.Fatal("FindElement(missing) want error")
+	}
+	parent.End()
+	tree := newSpanTree(exporter.GetSpans())
+
+	status := tree.byName["WdaSession.GetStatus"]
+	if len(status) != 1 {
+		t.Fatalf("got %d GetStatus spans, want 1", len(status))
+	}
+	if tree.parent(status[0]) != "test case" {
+		t.Error("method span is not a child of the parent set by SetParent")
+	}
+	request := tree.byName["GET /status"]
+	if len(request) != 1 || tree.parent(request[0]) != "WdaSession.GetStatus" {
+		t.Fatalf("GET /status spans = %v, want one child of WdaSession.GetStatus", request)
+	}
+	if got := attributes(request[0])[AttributeAttempt].AsInt64(); got != 3 {
+		t.Errorf("GET /status attempts = %d, want 3", got)
+	}
+
+	// 每次请求一个子span，前两次失败
+	for i, name := range []string{"attempt 1", "attempt 2", "attempt 3"} {
+		attempts := tree.byName[name]
+		if len(attempts) != 1 {
+			t.Fatalf("got %d %q spans, want 1", len(attempts), name)
+		}
+		attempt := attempts[0]
+		if tree.parent(attempt) != "GET /status" {
+			t.Errorf("%s is not a child of the request span", name)
+		}
+		if failed := attempt.Status.Code == codes.Error; failed != (i < 2) {
+			t.Errorf("%s error status = %v, want %v", name, failed, i < 2)
+		}
+	}
+
+	// 请求失败时请求span和方法span都标记为失败
+	for _, name := range []string{"WdaSession.FindElement", "POST /elements"} {
+		spans := tree.byName[name]
+		if len(spans) != 1 {
+			t.Fatalf("got %d %q spans, want 1", len(spans), name)
+		}
+		attrs := attributes(spans[0])
+		if spans[0].Status.Code != codes.Error || attrs[AttributeErrorCode].AsString() != "no such element" ||
+			attrs[AttributeHttpStatus].AsInt64() != http.StatusNotFound {
+			t.Errorf("%s span status %v attributes %v, want no such element error", name, spans[0].Status, spans[0].Attributes)
+		}
+	}
+	if got := tree.parent(tree.byName["POST /elements"][0]); got != "WdaSession.FindElement" {
+		t.Errorf("POST /elements parent = %q, want WdaSession.FindElement", got)
+	}
+}
+
+func TestConcurrentSpans(t *testing.T) {
+	session, _, _, exporter, _ := setup(t)
+
+	// 同一个session同时调用多个方法，请求span的父span为发送请求的方法
+	const calls = 10
+	var wg sync.WaitGroup
+	for i := 0; i < calls; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			session.GetStatus()
+		}()
+		go func() {
+			defer wg.Done()
+			session.TapWithLocation(WdaGo.ElementLocation{X: 1, Y: 2})
+		}()
+	}
+	wg.Wait()
+
+	tree := newSpanTree(exporter.GetSpans())
+	want := map[string]string{"GET /status": "WdaSession.GetStatus", "POST /wda/tap": "WdaSession.TapWithLocation"}
+	parents := make(map[trace.SpanID]bool)
+	for name, parent := range want {
+		if len(tree.byName[name]) != calls || len(tree.byName[parent]) != calls {
+			t.Fatalf("got %d %q and %d %q spans, want %d", len(tree.byName[name]), name, len(tree.byName[parent]), parent, calls)
+		}
+		for _, span := range tree.byName[name] {
+			if got := tree.parent(span); got != parent {
+				t.Errorf("%s parent = %q, want %q", name, got, parent)
+			}
+			parents[span.Parent.SpanID()] = true
+		}
+	}
+	if len(parents) != 2*calls {
+		t.Errorf("requests share %d method spans, want one method span per request", len(parents))
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	session, fake, _, _, reader := setup(t)
+	session.SetRetry(WdaGo.RetryOption{Count: 1})
+	fake.failStatus.Store(1)
+
+	if _, err := session.GetStatus(); err != nil {
+		t.Fatalf("GetStatus() error = %v", err)
+	}
+	if err := session.TapWithLocation(WdaGo.ElementLocation{X: 1, Y: 2}); err != nil {
+		t.Fatalf("TapWithLocation() error = %v", err)
+	}
+	session.FindElements(WdaGo.StrategyAccessibilityId, "missing")
+
+	var data metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &data); err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+	metrics := make(map[string]metricdata.Aggregation)
+	for _, scope := range data.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			metrics[m.Name] = m.Data
+		}
+	}
+
+	duration, ok := metrics["wda.command.duration"].(metricdata.Histogram[float64])
+	if !ok {
+		t.Fatal("wda.command.duration not recorded")
+	}
+	var commands uint64
+	for _, point := range duration.DataPoints {
+		commands += point.Count
+	}
+	if commands != 3 {
+		t.Errorf("duration recorded %d commands, want 3", commands)
+	}
+
+	sums := map[string]int64{}
+	for _, name := range []string{"wda.command.errors", "wda.command.retries", "wda.command.in_flight"} {
+		sum, ok := metrics[name].(metricdata.Sum[int64])
+		if !ok {
+			t.Fatalf("%s not recorded", name)
+		}
+		for _, point := range sum.DataPoints {
+			sums[name] += point.Value
+			if name == "wda.command.errors" {
+				if code, _ := point.Attributes.Value(AttributeErrorCode); code.AsString() != "no such element" {
+					t.Errorf("error code = %q, want no such element", code.AsString())
+				}
+			}
+		}
+	}
+	want := map[string]int64{"wda.command.errors": 1, "wda.command.retries": 1, "wda.command.in_flight": 0}
+	for name, value := range want {
+		if sums[name] != value {
+			t.Errorf("%s = %d, want %d", name, sums[name], value)
+		}
+	}
+}
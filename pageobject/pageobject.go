@@ -0,0 +1,401 @@
+// Package pageobject 根据页面树生成page object代码，元素使用WdaGo.Element句柄，
+// 定位方式由WdaGo.SuggestLocators生成
+//
+//	root, _ := session.GetSourceTree()
+//	code, _ := pageobject.Generate(root, pageobject.Config{Package: "pages", Name: "LoginPage"})
+package pageobject
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	WdaGo "github.com/Ning9527fff/WdaGo"
+	"gopkg.in/yaml.v3"
+)
+
+// 元素生成的操作方法
+const (
+	ActionTap   = "tap"
+	ActionInput = "input"
+	ActionRead  = "read"
+)
+
+// Config 生成配置
+//
+//	Types             生成的元素类型，为空时使用DefaultKinds中的类型
+//	Include/Exclude   正则表达式，匹配元素的name或label，Include不为空时只生成匹配的元素
+//	Names             指定字段名，key为元素的name或label，值为"-"时不生成该元素
+//	RequireIdentifier 只生成有name的元素
+//	IncludeInvisible  是否生成不可见的元素
+//	AllowIndexed      是否生成只能通过序号定位的元素
+//	Timeout           元素句柄查找时的等待时间
+type Config struct {
+	Package           string            `yaml:"package"`
+	Name              string            `yaml:"name"`
+	Types             []string          `yaml:"types"`
+	Include           []string          `yaml:"include"`
+	Exclude           []string          `yaml:"exclude"`
+	Names             map[string]string `yaml:"names"`
+	RequireIdentifier bool              `yaml:"requireIdentifier"`
+	IncludeInvisible  bool              `yaml:"includeInvisible"`
+	AllowIndexed      bool              `yaml:"allowIndexed"`
+	Timeout           time.Duration     `yaml:"timeout"`
+}
+
+// Kind 元素类型生成的字段后缀和操作方法，NeedIdentifier为true时只生成有独立标识的元素
+type Kind struct {
+	Suffix         string
+	Action         string
+	NeedIdentifier bool
+}
+
+// PageElement 生成到page object中的元素
+type PageElement struct {
+	Field   string
+	Kind    Kind
+	Node    *WdaGo.SourceNode
+	Locator WdaGo.Locator
+}
+
+// DefaultKinds 默认生成的元素类型，静态文本和cell只生成有标识的，避免生成大量无意义的字段
+var DefaultKinds = map[string]Kind{
+	"XCUIElementTypeButton":          {Suffix: "Button", Action: ActionTap},
+	"XCUIElementTypeLink":            {Suffix: "Link", Action: ActionTap},
+	"XCUIElementTypeSwitch":          {Suffix: "Switch", Action: ActionTap},
+	"XCUIElementTypeCell":            {Suffix: "Cell", Action: ActionTap, NeedIdentifier: true},
+	"XCUIElementTypeTextField":       {Suffix: "Field", Action: ActionInput},
+	"XCUIElementTypeSecureTextField": {Suffix: "Field", Action: ActionInput},
+	"XCUIElementTypeSearchField":     {Suffix: "Field", Action: ActionInput},
+	"XCUIElementTypeTextView":        {Suffix: "TextView", Action: ActionInput},
+	"XCUIElementTypeStaticText":      {Suffix: "Text", Action: ActionRead, NeedIdentifier: true},
+}
+
+// strategyConstants 生成代码中使用的查找策略常量名
+var strategyConstants = map[string]string{
+	WdaGo.StrategyAccessibilityId: "WdaGo.StrategyAccessibilityId",
+	WdaGo.StrategyId:              "WdaGo.StrategyId",
+	WdaGo.StrategyName:            "WdaGo.StrategyName",
+	WdaGo.StrategyClassName:       "WdaGo.StrategyClassName",
+	WdaGo.StrategyClassChain:      "WdaGo.StrategyClassChain",
+	WdaGo.StrategyPredicate:       "WdaGo.StrategyPredicate",
+	WdaGo.StrategyXpath:           "WdaGo.StrategyXpath",
+}
+
+var wordPattern = regexp.MustCompile(`[A-Z]+[a-z0-9]*|[a-z0-9]+`)
+
+// LoadConfig 读取yaml格式的生成配置
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf(" Read page object config failed :%v", err)
+	}
+
+	var config Config
+	if err = yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf(" Parse page object config failed :%v", err)
+	}
+	return &config, nil
+}
+
+// GenerateFromSource 解析/source返回的xml并生成page object代码
+func GenerateFromSource(source string, config Config) ([]byte, error) {
+	root, err := WdaGo.ParseSource(source)
+	if err != nil {
+		return nil, err
+	}
+	return Generate(root, config)
+}
+
+// Generate 根据页面树生成page object代码
+func Generate(root *WdaGo.SourceNode, config Config) ([]byte, error) {
+	if config.Package == "" {
+		config.Package = "pages"
+	}
+	if config.Name == "" {
+		config.Name = "Page"
+	}
+	if !isIdentifier(config.Name) {
+		return nil, fmt.Errorf(" Invalid page object name %q ", config.Name)
+	}
+
+	elements, err := Collect(root, config)
+	if err != nil {
+		return nil, err
+	}
+	if len(elements) == 0 {
+		return nil, fmt.Errorf(" No element found for page object ")
+	}
+
+	code := render(elements, config)
+	formatted, err := format.Source(code)
+	if err != nil {
+		return nil, fmt.Errorf(" Format page object failed :%v", err)
+	}
+	return formatted, nil
+}
+
+// Collect 按配置筛选页面树中的元素，生成字段名和定位方式
+func Collect(root *WdaGo.SourceNode, config Config) ([]PageElement, error) {
+	include, err := compilePatterns(config.Include)
+	if err != nil {
+		return nil, err
+	}
+	exclude, err := compilePatterns(config.Exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	kinds := DefaultKinds
+	if len(config.Types) > 0 {
+		kinds = make(map[string]Kind, len(config.Types))
+		for _, t := range config.Types {
+			if !strings.HasPrefix(t, "XCUIElementType") {
+				t = "XCUIElementType" + t
+			}
+			kind, ok := DefaultKinds[t]
+			if !ok {
+				kind = Kind{Suffix: strings.TrimPrefix(t, "XCUIElementType"), Action: ActionTap}
+			}
+			kinds[t] = kind
+		}
+	}
+
+	var elements []PageElement
+	used := make(map[string]int)
+	for _, node := range root.FindAll(func(n *WdaGo.SourceNode) bool { return n != root }) {
+		kind, ok := kinds[node.Type]
+		if !ok {
+			continue
+		}
+		if !node.Visible && !config.IncludeInvisible {
+			continue
+		}
+
+		custom, hasCustom := customName(config.Names, node)
+		if custom == "-" {
+			continue
+		}
+		if !hasCustom {
+			if (kind.NeedIdentifier || config.RequireIdentifier) && !hasIdentifier(node, kind) {
+				continue
+			}
+			if len(include) > 0 && !matchAny(include, node) {
+				continue
+			}
+			if matchAny(exclude, node) {
+				continue
+			}
+		}
+
+		best, ok := chooseLocator(WdaGo.SuggestLocators(root, node), config.AllowIndexed)
+		if !ok {
+			continue
+		}
+
+		field := custom
+		if !hasCustom {
+			field = fieldName(node, kind)
+		}
+		if !isIdentifier(field) {
+			return nil, fmt.Errorf(" Invalid field name %q for %s ", field, node.Title())
+		}
+		used[field]++
+		if used[field] > 1 {
+			field += strconv.Itoa(used[field])
+		}
+
+		elements = append(elements, PageElement{
+			Field:   field,
+			Kind:    kind,
+			Node:    node,
+			Locator: best.Locator,
+		})
+	}
+	return elements, nil
+}
+
+func render(elements []PageElement, config Config) []byte {
+	var code bytes.Buffer
+	name := config.Name
+
+	fmt.Fprintf(&code, "// Code generated by wdago pageobject; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&code, "package %s\n\n", config.Package)
+	fmt.Fprintf(&code, "import (\n\"time\"\n\nWdaGo \"github.com/Ning9527fff/WdaGo\"\n)\n\n")
+
+	fmt.Fprintf(&code, "// %s page object\ntype %s struct {\nsession *WdaGo.WdaSession\n\n", name, name)
+	for _, element := range elements {
+		fmt.Fprintf(&code, "// %s %s\n%s *WdaGo.Element\n", element.Field, element.Node.Title(), element.Field)
+	}
+	fmt.Fprintf(&code, "}\n\n")
+
+	fmt.Fprintf(&code, "func New%s(session *WdaGo.WdaSession) *%s {\nreturn &%s{\nsession: session,\n", name, name, name)
+	for _, element := range elements {
+		fmt.Fprintf(&code, "%s: session.Element(%s)%s,\n", element.Field, LocatorExpr(element.Locator), timeoutExpr(config.Timeout))
+	}
+	fmt.Fprintf(&code, "}\n}\n\n")
+
+	// 使用第一个元素判断页面是否加载完成
+	fmt.Fprintf(&code, "// WaitLoaded 等待页面加载完成\nfunc (page *%s) WaitLoaded(timeout time.Duration) error {\nreturn page.%s.WaitFor(timeout)\n}\n",
+		name, elements[0].Field)
+
+	for _, element := range elements {
+		field := element.Field
+		switch element.Kind.Action {
+		case ActionTap:
+			fmt.Fprintf(&code, "\n// Tap%s 点击%s\nfunc (page *%s) Tap%s() error {\nreturn page.%s.Click()\n}\n",
+				field, field, name, field, field)
+		case ActionInput:
+			fmt.Fprintf(&code, "\n// Enter%s 清空%s后输入text\nfunc (page *%s) Enter%s(text string) error {\nreturn page.%s.SetText(text)\n}\n",
+				field, field, name, field, field)
+		case ActionRead:
+			fmt.Fprintf(&code, "\n// Get%s 获取%s的文本\nfunc (page *%s) Get%s() (string, error) {\nreturn page.%s.Text()\n}\n",
+				field, field, name, field, field)
+		}
+	}
+	return code.Bytes()
+}
+
+// LocatorExpr 生成Locator的go代码，查找策略使用WdaGo中的常量
+func LocatorExpr(locator WdaGo.Locator) string {
+	using, ok := strategyConstants[locator.Using]
+	if !ok {
+		using = strconv.Quote(locator.Using)
+	}
+	if locator.Index > 0 {
+		return fmt.Sprintf("WdaGo.Locator{Using: %s, Value: %s, Index: %d}", using, quote(locator.Value), locator.Index)
+	}
+	return fmt.Sprintf("WdaGo.Locator{Using: %s, Value: %s}", using, quote(locator.Value))
+}
+
+// quote 值中包含双引号或反斜杠时使用反引号，便于阅读
+func quote(value string) string {
+	if strings.ContainsAny(value, "\"\\") && !strings.ContainsAny(value, "`\n") {
+		return "`" + value + "`"
+	}
+	return strconv.Quote(value)
+}
+
+func timeoutExpr(timeout time.Duration) string {
+	if timeout <= 0 {
+		return ""
+	}
+	if timeout%time.Second == 0 {
+		return fmt.Sprintf(".WithTimeout(%d * time.Second)", timeout/time.Second)
+	}
+	return fmt.Sprintf(".WithTimeout(%d * time.Millisecond)", timeout/time.Millisecond)
+}
+
+// fieldName 由元素的name或label生成字段名，如 login_button -> LoginButton，
+// 无法生成英文名称时使用类型名
+func fieldName(node *WdaGo.SourceNode, kind Kind) string {
+	text := node.Name
+	if text == "" {
+		text = node.Label
+	}
+	if text == "" {
+		text = node.Value
+	}
+
+	var name strings.Builder
+	for _, word := range wordPattern.FindAllString(text, -1) {
+		name.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+
+	field := name.String()
+	if field == "" {
+		return kind.Suffix
+	}
+	if unicode.IsDigit(rune(field[0])) {
+		field = kind.Suffix + field
+	}
+	if !strings.HasSuffix(strings.ToLower(field), strings.ToLower(kind.Suffix)) {
+		field += kind.Suffix
+	}
+	return field
+}
+
+// customName 根据name或label查找配置中指定的字段名
+func customName(names map[string]string, node *WdaGo.SourceNode) (string, bool) {
+	for _, key := range []string{node.Name, node.Label} {
+		if key == "" {
+			continue
+		}
+		if name, ok := names[key]; ok {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// hasIdentifier 元素是否有独立的标识，静态文本的name默认等于显示的文本，不算作标识
+func hasIdentifier(node *WdaGo.SourceNode, kind Kind) bool {
+	if node.Name == "" {
+		return false
+	}
+	if kind.Action == ActionRead {
+		return node.Name != node.Label && node.Name != node.Value
+	}
+	return true
+}
+
+// chooseLocator 选择第一个唯一且不只依赖序号的定位方式，
+// allowIndexed为true时没有这样的定位方式则使用分数最高的定位方式，包括匹配多个元素时带Index的定位方式
+func chooseLocator(suggestions []WdaGo.LocatorSuggestion, allowIndexed bool) (WdaGo.LocatorSuggestion, bool) {
+	for _, suggestion := range suggestions {
+		if suggestion.Unique && !isIndexOnly(suggestion.Locator) {
+			return suggestion, true
+		}
+	}
+	if allowIndexed && len(suggestions) > 0 {
+		return suggestions[0], true
+	}
+	return WdaGo.LocatorSuggestion{}, false
+}
+
+// isIndexOnly 定位方式是否只依赖序号，如 **/XCUIElementTypeButton[2]
+func isIndexOnly(locator WdaGo.Locator) bool {
+	if locator.Using != WdaGo.StrategyClassChain && locator.Using != WdaGo.StrategyXpath {
+		return false
+	}
+	return !strings.ContainsAny(locator.Value, "=`@")
+}
+
+func compilePatterns(patterns []string) ([]*regexp.Regexp, error) {
+	var result []*regexp.Regexp
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf(" Invalid pattern %q :%v", pattern, err)
+		}
+		result = append(result, re)
+	}
+	return result, nil
+}
+
+func matchAny(patterns []*regexp.Regexp, node *WdaGo.SourceNode) bool {
+	for _, re := range patterns {
+		if (node.Name != "" && re.MatchString(node.Name)) || (node.Label != "" && re.MatchString(node.Label)) {
+			return true
+		}
+	}
+	return false
+}
+
+func isIdentifier(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		if !(r == '_' || unicode.IsLetter(r) || (i > 0 && unicode.IsDigit(r))) {
+			return false
+		}
+	}
+	return unicode.IsUpper([]rune(name)[0])
+}
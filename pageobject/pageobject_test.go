@@ -0,0 +1,226 @@
+package pageobject
+
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+	"time"
+
+	WdaGo "github.com/Ning9527fff/WdaGo"
+)
+
+const loginSource = `<?xml version="1.0" encoding="UTF-8"?>
+<AppiumAUT>
+<XCUIElementTypeApplication type="XCUIElementTypeApplication" name="Demo" visible="true">
+  <XCUIElementTypeWindow type="XCUIElementTypeWindow" visible="true">
+    <XCUIElementTypeTextField type="XCUIElementTypeTextField" name="user_name" visible="true"/>
+    <XCUIElementTypeSecureTextField type="XCUIElementTypeSecureTextField" name="password" visible="true"/>
+    <XCUIElementTypeButton type="XCUIElementTypeButton" name="login" label="Log In" visible="true"/>
+    <XCUIElementTypeButton type="XCUIElementTypeButton" name="hidden" visible="false"/>
+    <XCUIElementTypeButton type="XCUIElementTypeButton" visible="true"/>
+    <XCUIElementTypeStaticText type="XCUIElementTypeStaticText" name="Welcome" label="Welcome" visible="true"/>
+    <XCUIElementTypeStaticText type="XCUIElementTypeStaticText" name="error_text" label="Wrong password" visible="true"/>
+    <XCUIElementTypeCell type="XCUIElementTypeCell" visible="true"/>
+  </XCUIElementTypeWindow>
+</XCUIElementTypeApplication>
+</AppiumAUT>`
+
+func TestCollect(t *testing.T) {
+	root, err := WdaGo.ParseSource(loginSource)
+	if err != nil {
+		t.Fatalf("ParseSource() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		config Config
+		// want 字段名到定位方式
+		want map[string]WdaGo.Locator
+	}{
+		{
+			name: "default kinds",
+			want: map[string]WdaGo.Locator{
+				"UserNameField": {Using: WdaGo.StrategyAccessibilityId, Value: "user_name"},
+				"PasswordField": {Using: WdaGo.StrategyAccessibilityId, Value: "password"},
+				"LoginButton":   {Using: WdaGo.StrategyAccessibilityId, Value: "login"},
+				"ErrorText":     {Using: WdaGo.StrategyAccessibilityId, Value: "error_text"},
+			},
+		},
+		{
+			name:   "indexed and invisible elements",
+			config: Config{AllowIndexed: true, IncludeInvisible: true, Types: []string{"Button"}},
+			want: map[string]WdaGo.Locator{
+				"LoginButton":  {Using: WdaGo.StrategyAccessibilityId, Value: "login"},
+				"HiddenButton": {Using: WdaGo.StrategyAccessibilityId, Value: "hidden"},
+				"Button":       {Using: WdaGo.StrategyClassChain, Value: "**/XCUIElementTypeButton[3]"},
+			},
+		},
+		{
+			name:   "include, exclude and custom names",
+			config: Config{Include: []string{"^(user|pass)"}, Exclude: []string{"password"}, Names: map[string]string{"Log In": "Submit", "error_text": "-"}},
+			want: map[string]WdaGo.Locator{
+				"UserNameField": {Using: WdaGo.StrategyAccessibilityId, Value: "user_name"},
+				"Submit":        {Using: WdaGo.StrategyAccessibilityId, Value: "login"},
+			},
+		},
+		{
+			name:   "require identifier",
+			config: Config{RequireIdentifier: true, Types: []string{"XCUIElementTypeButton", "Cell"}},
+			want: map[string]WdaGo.Locator{
+				"LoginButton": {Using: WdaGo.StrategyAccessibilityId, Value: "login"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			elements, err := Collect(root, tt.config)
+			if err != nil {
+				t.Fatalf("Collect() error = %v", err)
+			}
+			got := make(map[string]WdaGo.Locator, len(elements))
+			for _, element := range elements {
+				got[element.Field] = element.Locator
+			}
+			if len(got) != len(tt.want) {
+				t.Errorf("Collect() got fields %v, want %v", got, tt.want)
+			}
+			for field, locator := range tt.want {
+				if got[field] != locator {
+					t.Errorf("field %s locator = %v, want %v", field, got[field], locator)
+				}
+			}
+		})
+	}
+}
+
+func TestCollectDuplicatedFields(t *testing.T) {
+	root, err := WdaGo.ParseSource(`<XCUIElementTypeApplication type="XCUIElementTypeApplication" visible="true">
+		<XCUIElementTypeButton type="XCUIElementTypeButton" name="ok" visible="true"/>
+		<XCUIElementTypeOther type="XCUIElementTypeOther" name="sheet" visible="true">
+			<XCUIElementTypeButton type="XCUIElementTypeButton" name="ok" visible="true"/>
+		</XCUIElementTypeOther>
+	</XCUIElementTypeApplication>`)
+	if err != nil {
+		t.Fatalf("ParseSource() error = %v", err)
+	}
+
+	// 第一个按钮只能通过序号定位，默认不生成
+	elements, err := Collect(root, Config{})
+	if err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+	if len(elements) != 1 || elements[0].Locator.Using != WdaGo.StrategyClassChain || !strings.Contains(elements[0].Locator.Value, "sheet") {
+		t.Fatalf("Collect() got %v, want button anchored by sheet", elements)
+	}
+
+	elements, err = Collect(root, Config{AllowIndexed: true})
+	if err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+	if len(elements) != 2 || elements[0].Field != "OkButton" || elements[1].Field != "OkButton2" {
+		t.Fatalf("Collect() got %v, want OkButton and OkButton2", elements)
+	}
+	if first := elements[0].Locator; first != (WdaGo.Locator{Using: WdaGo.StrategyClassChain, Value: "**/XCUIElementTypeButton[1]"}) {
+		t.Errorf("first button locator = %v, want index class chain", first)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	code, err := GenerateFromSource(loginSource, Config{Package: "pages", Name: "LoginPage", Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("GenerateFromSource() error = %v", err)
+	}
+	if _, err = parser.ParseFile(token.NewFileSet(), "login_page.go", code, 0); err != nil {
+		t.Fatalf("generated code does not parse: %v\n%s", err, code)
+	}
+
+	for _, want := range []string{
+		"package pages",
+		"func NewLoginPage(session *WdaGo.WdaSession) *LoginPage",
+		`session.Element(WdaGo.Locator{Using: WdaGo.StrategyAccessibilityId, Value: "login"}).WithTimeout(5 * time.Second)`,
+		"func (page *LoginPage) WaitLoaded(timeout time.Duration) error {\n\treturn page.UserNameField.WaitFor(timeout)",
+		"func (page *LoginPage) TapLoginButton() error",
+		"func (page *LoginPage) EnterPasswordField(text string) error",
+		"func (page *LoginPage) GetErrorText() (string, error)",
+	} {
+		if !strings.Contains(string(code), want) {
+			t.Errorf("generated code missing %q\n%s", want, code)
+		}
+	}
+
+	if _, err = GenerateFromSource(loginSource, Config{Name: "loginPage"}); err == nil {
+		t.Error("Generate() with unexported name: want error")
+	}
+	if _, err = GenerateFromSource(loginSource, Config{Include: []string{"nothing"}}); err == nil {
+		t.Error("Generate() without elements: want error")
+	}
+	if _, err = GenerateFromSource(loginSource, Config{Include: []string{"("}}); err == nil {
+		t.Error("Generate() with invalid pattern: want error")
+	}
+}
+
+func TestLocatorExpr(t *testing.T) {
+	tests := []struct {
+		locator WdaGo.Locator
+		want    string
+	}{
+		{WdaGo.Locator{Using: WdaGo.StrategyAccessibilityId, Value: "login"},
+			`WdaGo.Locator{Using: WdaGo.StrategyAccessibilityId, Value: "login"}`},
+		{WdaGo.Locator{Using: WdaGo.StrategyPredicate, Value: `label == "OK"`, Index: 2},
+			"WdaGo.Locator{Using: WdaGo.StrategyPredicate, Value: `label == \"OK\"`, Index: 2}"},
+		{WdaGo.Locator{Using: "custom", Value: "a`\"b"},
+			`WdaGo.Locator{Using: "custom", Value: "a` + "`" + `\"b"}`},
+	}
+	for _, tt := range tests {
+		if got := LocatorExpr(tt.locator); got != tt.want {
+			t.Errorf("LocatorExpr(%v) = %s, want %s", tt.locator, got, tt.want)
+		}
+	}
+}
+
+func TestFieldName(t *testing.T) {
+	button := DefaultKinds["XCUIElementTypeButton"]
+	tests := []struct {
+		node WdaGo.SourceNode
+		want string
+	}{
+		{WdaGo.SourceNode{Name: "login_button"}, "LoginButton"},
+		{WdaGo.SourceNode{Name: "submitOrder"}, "SubmitOrderButton"},
+		{WdaGo.SourceNode{Label: "2 items"}, "Button2ItemsButton"},
+		{WdaGo.SourceNode{Label: "确定"}, "Button"},
+		{WdaGo.SourceNode{Value: "next"}, "NextButton"},
+	}
+	for _, tt := range tests {
+		if got := fieldName(&tt.node, button); got != tt.want {
+			t.Errorf("fieldName(%+v) = %q, want %q", tt.node, got, tt.want)
+		}
+	}
+}
+
+func TestChooseLocator(t *testing.T) {
+	byId := WdaGo.LocatorSuggestion{Locator: WdaGo.Locator{Using: WdaGo.StrategyAccessibilityId, Value: "ok", Index: 1}, Count: 2}
+	byIndex := WdaGo.LocatorSuggestion{Locator: WdaGo.Locator{Using: WdaGo.StrategyClassChain, Value: "**/XCUIElementTypeButton[2]"}, Count: 1, Unique: true}
+	byAnchor := WdaGo.LocatorSuggestion{Locator: WdaGo.Locator{Using: WdaGo.StrategyClassChain, Value: "**/XCUIElementTypeOther[`name == \"sheet\"`]/**/XCUIElementTypeButton[1]"}, Count: 1, Unique: true}
+
+	tests := []struct {
+		name         string
+		suggestions  []WdaGo.LocatorSuggestion
+		allowIndexed bool
+		want         WdaGo.LocatorSuggestion
+		ok           bool
+	}{
+		{"unique attribute locator preferred", []WdaGo.LocatorSuggestion{byId, byIndex, byAnchor}, false, byAnchor, true},
+		{"index only rejected", []WdaGo.LocatorSuggestion{byId, byIndex}, false, WdaGo.LocatorSuggestion{}, false},
+		{"index allowed falls back to best score", []WdaGo.LocatorSuggestion{byId, byIndex}, true, byId, true},
+		{"empty", nil, true, WdaGo.LocatorSuggestion{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := chooseLocator(tt.suggestions, tt.allowIndexed)
+			if ok != tt.ok || got.Locator != tt.want.Locator {
+				t.Errorf("chooseLocator() = %v, %v, want %v, %v", got.Locator, ok, tt.want.Locator, tt.ok)
+			}
+		})
+	}
+}
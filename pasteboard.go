@@ -0,0 +1,140 @@
+package WdaGo
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/png"
+
+	"github.com/tidwall/gjson"
+)
+
+// PasteboardType 剪贴板内容类型
+type PasteboardType string
+
+const (
+	PasteboardPlainText PasteboardType = "plaintext"
+	PasteboardUrl       PasteboardType = "url"
+	PasteboardImage     PasteboardType = "image"
+)
+
+// SetPasteboard 设置剪贴板内容，content为原始数据，base64编码由该方法处理
+func (session *WdaSession) SetPasteboard(contentType PasteboardType, content []byte) error {
+	session, end := session.begin("WdaSession.SetPasteboard")
+	defer end()
+
+	api := session.url + "/session/" + session.SessionId() + "/wda/setPasteboard"
+
+	body, err := session.post(api, PasteboardRequest{
+		Content:     base64.StdEncoding.EncodeToString(content),
+		ContentType: string(contentType),
+	})
+	if err != nil {
+		return fmt.Errorf(" Set pasteboard failed from api :%w", err)
+	}
+
+	if JudgeResponseCorrect(body, session.SessionId()) {
+		return nil
+	} else {
+		return fmt.Errorf(" Set pasteboard failed ")
+	}
+}
+
+// GetPasteboard 获取剪贴板内容，返回base64解码后的原始数据
+func (session *WdaSession) GetPasteboard(contentType PasteboardType) ([]byte, error) {
+	session, end := session.begin("WdaSession.GetPasteboard")
+	defer end()
+
+	api := session.url + "/session/" + session.SessionId() + "/wda/getPasteboard"
+
+	body, err := session.post(api, PasteboardRequest{
+		ContentType: string(contentType),
+	})
+	if err != nil {
+		return nil, fmt.Errorf(" Get pasteboard failed from api :%w", err)
+	}
+
+	value := gjson.Get(string(body), "value")
+	if !value.Exists() {
+		return nil, fmt.Errorf(" Get pasteboard failed, there is no vaild data ")
+	}
+
+	content, err := base64.StdEncoding.DecodeString(value.String())
+	if err != nil {
+		return nil, fmt.Errorf(" Decode pasteboard data with base64 failed :%w", err)
+	}
+	return content, nil
+}
+
+// SetPasteboardText 设置剪贴板文本
+func (session *WdaSession) SetPasteboardText(text string) error {
+	session, end := session.begin("WdaSession.SetPasteboardText")
+	defer end()
+
+	return session.SetPasteboard(PasteboardPlainText, []byte(text))
+}
+
+// GetPasteboardText 获取剪贴板文本
+func (session *WdaSession) GetPasteboardText() (string, error) {
+	session, end := session.begin("WdaSession.GetPasteboardText")
+	defer end()
+
+	content, err := session.GetPasteboard(PasteboardPlainText)
+	if err != nil {
+		return StringNull, err
+	}
+	return string(content), nil
+}
+
+// SetPasteboardUrl 设置剪贴板url
+func (session *WdaSession) SetPasteboardUrl(url string) error {
+	session, end := session.begin("WdaSession.SetPasteboardUrl")
+	defer end()
+
+	return session.SetPasteboard(PasteboardUrl, []byte(url))
+}
+
+// GetPasteboardUrl 获取剪贴板url
+func (session *WdaSession) GetPasteboardUrl() (string, error) {
+	session, end := session.begin("WdaSession.GetPasteboardUrl")
+	defer end()
+
+	content, err := session.GetPasteboard(PasteboardUrl)
+	if err != nil {
+		return StringNull, err
+	}
+	return string(content), nil
+}
+
+// SetPasteboardImage 将图片以png格式写入剪贴板
+func (session *WdaSession) SetPasteboardImage(img image.Image) error {
+	session, end := session.begin("WdaSession.SetPasteboardImage")
+	defer end()
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return fmt.Errorf(" Encode pasteboard image failed :%w", err)
+	}
+	return session.SetPasteboard(PasteboardImage, buf.Bytes())
+}
+
+// GetPasteboardImage 获取剪贴板中的图片，剪贴板中没有图片时返回错误
+func (session *WdaSession) GetPasteboardImage() (image.Image, error) {
+	session, end := session.begin("WdaSession.GetPasteboardImage")
+	defer end()
+
+	content, err := session.GetPasteboard(PasteboardImage)
+	if err != nil {
+		return nil, err
+	}
+	if len(content) == 0 {
+		return nil, fmt.Errorf(" No image in pasteboard ")
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(content))
+	if err != nil {
+		return nil, fmt.Errorf(" Decode pasteboard image failed :%w", err)
+	}
+	return img, nil
+}
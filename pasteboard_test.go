@@ -0,0 +1,102 @@
+package WdaGo
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"image"
+	"image/color"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakePasteboard 模拟wda的剪贴板，按contentType保存请求中的base64内容
+type fakePasteboard struct {
+	mu       sync.Mutex
+	contents map[string]string
+}
+
+func (fake *fakePasteboard) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	var request PasteboardRequest
+	json.NewDecoder(r.Body).Decode(&request)
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/wda/setPasteboard"):
+		fake.contents[request.ContentType] = request.Content
+		writeValue(w, "null")
+	case strings.HasSuffix(r.URL.Path, "/wda/getPasteboard"):
+		data, _ := json.Marshal(fake.contents[request.ContentType])
+		writeValue(w, string(data))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (fake *fakePasteboard) content(contentType PasteboardType) string {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	return fake.contents[string(contentType)]
+}
+
+func TestPasteboardRoundTrip(t *testing.T) {
+	fake := &fakePasteboard{contents: map[string]string{}}
+	session := newTestSession(t, fake)
+
+	// 发送给wda的内容是base64编码的原始数据
+	text := "你好, pasteboard\n"
+	if err := session.SetPasteboardText(text); err != nil {
+		t.Fatalf("SetPasteboardText() error = %v", err)
+	}
+	if got, want := fake.content(PasteboardPlainText), base64.StdEncoding.EncodeToString([]byte(text)); got != want {
+		t.Errorf("sent text = %q, want %q", got, want)
+	}
+	if got, err := session.GetPasteboardText(); err != nil || got != text {
+		t.Errorf("GetPasteboardText() = %q, %v, want %q", got, err, text)
+	}
+
+	url := "https://example.com/a?b=c&d=e"
+	if err := session.SetPasteboardUrl(url); err != nil {
+		t.Fatalf("SetPasteboardUrl() error = %v", err)
+	}
+	if got, want := fake.content(PasteboardUrl), base64.StdEncoding.EncodeToString([]byte(url)); got != want {
+		t.Errorf("sent url = %q, want %q", got, want)
+	}
+	if got, err := session.GetPasteboardUrl(); err != nil || got != url {
+		t.Errorf("GetPasteboardUrl() = %q, %v, want %q", got, err, url)
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, 3, 2))
+	img.Set(1, 1, color.RGBA{R: 255, A: 255})
+	if err := session.SetPasteboardImage(img); err != nil {
+		t.Fatalf("SetPasteboardImage() error = %v", err)
+	}
+	got, err := session.GetPasteboardImage()
+	if err != nil {
+		t.Fatalf("GetPasteboardImage() error = %v", err)
+	}
+	if got.Bounds() != img.Bounds() {
+		t.Fatalf("image bounds = %v, want %v", got.Bounds(), img.Bounds())
+	}
+	for _, point := range []image.Point{{0, 0}, {1, 1}, {2, 1}} {
+		r1, g1, b1, a1 := got.At(point.X, point.Y).RGBA()
+		r2, g2, b2, a2 := img.At(point.X, point.Y).RGBA()
+		if r1 != r2 || g1 != g2 || b1 != b2 || a1 != a2 {
+			t.Errorf("pixel %v = %v, want %v", point, got.At(point.X, point.Y), img.At(point.X, point.Y))
+		}
+	}
+}
+
+func TestGetPasteboardErrors(t *testing.T) {
+	fake := &fakePasteboard{contents: map[string]string{"plaintext": "not base64!"}}
+	session := newTestSession(t, fake)
+
+	if _, err := session.GetPasteboardText(); err == nil {
+		t.Error("GetPasteboardText() with invalid base64 want error")
+	}
+	// 剪贴板中没有图片时wda返回空内容
+	if _, err := session.GetPasteboardImage(); err == nil || !strings.Contains(err.Error(), "No image") {
+		t.Errorf("GetPasteboardImage() error = %v, want no image", err)
+	}
+}
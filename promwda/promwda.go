@@ -0,0 +1,232 @@
+// Package promwda 将设备状态、wda命令统计和session数量导出为Prometheus指标
+//
+// 设备状态在每次抓取时获取：wda是否就绪、电量、温度状态以及是否锁屏，
+// 命令次数、错误和耗时通过session的事件统计，抓取时获取设备状态的命令同样计入统计
+//
+//	collector := promwda.NewCollector()
+//	collector.AddDevice("iPhone-15", session)
+//	http.Handle("/metrics", collector.Handler())
+package promwda
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+
+	log "github.com/Ning9527fff/MyLog"
+	WdaGo "github.com/Ning9527fff/WdaGo"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Namespace 指标名前缀
+const Namespace = "wda"
+
+var (
+	readyDesc = prometheus.NewDesc(Namespace+"_device_ready",
+		"Whether wda on the device is ready (1) or not ready or unreachable (0).", []string{"device"}, nil)
+	batteryDesc = prometheus.NewDesc(Namespace+"_device_battery_level",
+		"Battery level of the device from 0 to 1, -1 when unknown.", []string{"device"}, nil)
+	batteryStateDesc = prometheus.NewDesc(Namespace+"_device_battery_state",
+		"Battery state of the device: 0 unknown, 1 unplugged, 2 charging, 3 full.", []string{"device"}, nil)
+	thermalDesc = prometheus.NewDesc(Namespace+"_device_thermal_state",
+		"Thermal state of the device: 0 nominal, 1 fair, 2 serious, 3 critical, absent when unknown.", []string{"device"}, nil)
+	lockedDesc = prometheus.NewDesc(Namespace+"_device_locked",
+		"Whether the device screen is locked.", []string{"device"}, nil)
+	sessionsDesc = prometheus.NewDesc(Namespace+"_sessions",
+		"Number of wda sessions created through the library and not deleted.", []string{"device"}, nil)
+)
+
+// Collector 多个设备的指标，实现prometheus.Collector
+type Collector struct {
+	registry *prometheus.Registry
+
+	commands *prometheus.CounterVec
+	errors   *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+
+	mu      sync.Mutex
+	devices map[string]*device
+}
+
+// device 一个设备的session和打开的session数
+type device struct {
+	session      *WdaGo.WdaSession
+	subscription *WdaGo.Subscription
+
+	mu       sync.Mutex
+	sessions map[string]bool
+}
+
+// NewCollector 创建指标收集器，Handler使用的registry中已注册该收集器
+func NewCollector() *Collector {
+	collector := &Collector{
+		registry: prometheus.NewRegistry(),
+		commands: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "commands_total",
+			Help:      "Number of wda commands by endpoint and http status.",
+		}, []string{"device", "method", "endpoint", "status"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "command_errors_total",
+			Help:      "Number of failed wda commands by wda error code.",
+		}, []string{"device", "endpoint", "error"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Name:      "command_duration_seconds",
+			Help:      "Duration of wda commands including retries.",
+			Buckets:   []float64{.01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+		}, []string{"device", "method", "endpoint"}),
+		devices: make(map[string]*device),
+	}
+	collector.registry.MustRegister(collector)
+	return collector
+}
+
+// AddDevice 添加设备，name为指标中的device标签，为空时使用wda地址，已存在时替换
+func (collector *Collector) AddDevice(name string, session *WdaGo.WdaSession) {
+	if name == "" {
+		name = WdaGo.DeviceName(session.Url())
+	}
+	dev := &device{session: session, sessions: make(map[string]bool)}
+	if sessionId := session.SessionId(); sessionId != "" {
+		dev.sessions[sessionId] = true
+	}
+	dev.subscription = session.Events().Subscribe(func(event WdaGo.Event) {
+		collector.observe(name, dev, event)
+	}, WdaGo.EventCommandFinished, WdaGo.EventSessionCreated, WdaGo.EventSessionDeleted)
+
+	collector.mu.Lock()
+	old := collector.devices[name]
+	collector.devices[name] = dev
+	collector.mu.Unlock()
+	if old != nil {
+		old.subscription.Unsubscribe()
+	}
+}
+
+// RemoveDevice 移除设备，已统计的命令指标同时删除
+func (collector *Collector) RemoveDevice(name string) {
+	collector.mu.Lock()
+	dev := collector.devices[name]
+	delete(collector.devices, name)
+	collector.mu.Unlock()
+	if dev == nil {
+		return
+	}
+
+	dev.subscription.Unsubscribe()
+	labels := prometheus.Labels{"device": name}
+	collector.commands.DeletePartialMatch(labels)
+	collector.errors.DeletePartialMatch(labels)
+	collector.duration.DeletePartialMatch(labels)
+}
+
+// Handler 输出指标的http handler
+func (collector *Collector) Handler() http.Handler {
+	return promhttp.HandlerFor(collector.registry, promhttp.HandlerOpts{})
+}
+
+// Describe 实现prometheus.Collector
+func (collector *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- readyDesc
+	ch <- batteryDesc
+	ch <- batteryStateDesc
+	ch <- thermalDesc
+	ch <- lockedDesc
+	ch <- sessionsDesc
+	collector.commands.Describe(ch)
+	collector.errors.Describe(ch)
+	collector.duration.Describe(ch)
+}
+
+// Collect 实现prometheus.Collector，并发获取每个设备的状态
+func (collector *Collector) Collect(ch chan<- prometheus.Metric) {
+	collector.mu.Lock()
+	devices := make(map[string]*device, len(collector.devices))
+	for name, dev := range collector.devices {
+		devices[name] = dev
+	}
+	collector.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for name, dev := range devices {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			dev.collect(name, ch)
+		}()
+	}
+	wg.Wait()
+
+	collector.commands.Collect(ch)
+	collector.errors.Collect(ch)
+	collector.duration.Collect(ch)
+}
+
+// collect 获取设备状态，wda不可用时只输出ready和session数
+func (dev *device) collect(name string, ch chan<- prometheus.Metric) {
+	dev.mu.Lock()
+	sessions := len(dev.sessions)
+	dev.mu.Unlock()
+	ch <- prometheus.MustNewConstMetric(sessionsDesc, prometheus.GaugeValue, float64(sessions), name)
+
+	status, err := dev.session.GetStatus()
+	ready := err == nil && status.IsReady
+	ch <- prometheus.MustNewConstMetric(readyDesc, prometheus.GaugeValue, boolValue(ready), name)
+	// 电量、温度和锁屏状态需要session
+	if !ready || dev.session.SessionId() == "" {
+		return
+	}
+
+	if battery, err := dev.session.GetBatteryInfo(); err == nil {
+		ch <- prometheus.MustNewConstMetric(batteryDesc, prometheus.GaugeValue, battery.Level, name)
+		ch <- prometheus.MustNewConstMetric(batteryStateDesc, prometheus.GaugeValue, float64(battery.State), name)
+	} else {
+		log.DebugF("Collect battery info of %v failed: %v", name, err)
+	}
+	if info, err := dev.session.GetDeviceInfo(); err != nil {
+		log.DebugF("Collect device info of %v failed: %v", name, err)
+	} else if info.ThermalState != WdaGo.ThermalStateUnknown {
+		// wda没有返回温度状态时不输出该指标，避免被当作nominal
+		ch <- prometheus.MustNewConstMetric(thermalDesc, prometheus.GaugeValue, float64(info.ThermalState), name)
+	}
+	if locked, err := dev.session.IsLocked(); err == nil {
+		ch <- prometheus.MustNewConstMetric(lockedDesc, prometheus.GaugeValue, boolValue(locked), name)
+	} else {
+		log.DebugF("Collect locked state of %v failed: %v", name, err)
+	}
+}
+
+// observe 统计命令和session数
+func (collector *Collector) observe(name string, dev *device, event WdaGo.Event) {
+	switch event.Type {
+	case WdaGo.EventSessionCreated:
+		dev.mu.Lock()
+		dev.sessions[event.SessionId] = true
+		dev.mu.Unlock()
+	case WdaGo.EventSessionDeleted:
+		dev.mu.Lock()
+		delete(dev.sessions, event.SessionId)
+		dev.mu.Unlock()
+	case WdaGo.EventCommandFinished:
+		record := event.Command
+		status := "error"
+		if record.StatusCode != 0 {
+			status = strconv.Itoa(record.StatusCode)
+		}
+		collector.commands.WithLabelValues(name, record.Method, event.Endpoint, status).Inc()
+		collector.duration.WithLabelValues(name, record.Method, event.Endpoint).Observe(record.TotalDuration.Seconds())
+		if event.Err != nil {
+			collector.errors.WithLabelValues(name, event.Endpoint, WdaGo.ErrorCode(record)).Inc()
+		}
+	}
+}
+
+func boolValue(value bool) float64 {
+	if value {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,153 @@
+package promwda
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	WdaGo "github.com/Ning9527fff/WdaGo"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+const testSessionId = "test-session"
+
+// testWda 模拟wda，/status 的前failStatus次请求直接断开连接，其他接口返回no such element
+type testWda struct {
+	failStatus atomic.Int32
+}
+
+func (fake *testWda) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", WdaGo.ContentTypeJson)
+	if r.URL.Path == "/status" {
+		if fake.failStatus.Add(-1) >= 0 {
+			conn, _, _ := w.(http.Hijacker).Hijack()
+			conn.Close()
+			return
+		}
+		w.Write([]byte(`{"value":{"ready":false},"sessionId":"` + testSessionId + `"}`))
+		return
+	}
+	w.WriteHeader(http.StatusNotFound)
+	w.Write([]byte(`{"value":{"error":"no such element","message":""},"sessionId":"` + testSessionId + `"}`))
+}
+
+func TestCollectorCommands(t *testing.T) {
+	fake := &testWda{}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	session := WdaGo.GetWdaSession(server.URL)
+	session.AttachSession(testSessionId)
+	const interval = 50 * time.Millisecond
+	session.SetRetry(WdaGo.RetryOption{Count: 1, Interval: interval})
+
+	collector := NewCollector()
+	collector.AddDevice("", session)
+	device := WdaGo.DeviceName(server.URL)
+
+	fake.failStatus.Store(1)
+	if _, err := session.GetStatus(); err != nil {
+		t.Fatalf("GetStatus() error = %v", err)
+	}
+	if _, err := session.FindElements(WdaGo.StrategyAccessibilityId, "missing"); err == nil {
+		t.Fatal("FindElements(missing) want error")
+	}
+
+	tests := []struct {
+		name  string
+		value float64
+		want  float64
+	}{
+		{"status command", testutil.ToFloat64(collector.commands.WithLabelValues(device, "GET", "/status", "200")), 1},
+		{"failed command", testutil.ToFloat64(collector.commands.WithLabelValues(device, "POST", "/elements", "404")), 1},
+		{"error code", testutil.ToFloat64(collector.errors.WithLabelValues(device, "/elements", "no such element")), 1},
+	}
+	for _, tt := range tests {
+		if tt.value != tt.want {
+			t.Errorf("%s = %v, want %v", tt.name, tt.value, tt.want)
+		}
+	}
+
+	// 耗时包括重试前的等待
+	families, err := collector.registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+	found := false
+	for _, family := range families {
+		if family.GetName() != "wda_command_duration_seconds" {
+			continue
+		}
+		for _, m := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, label := range m.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			if labels["endpoint"] != "/status" {
+				continue
+			}
+			found = true
+			if sum := m.GetHistogram().GetSampleSum(); sum < interval.Seconds() {
+				t.Errorf("/status duration = %vs, want at least %vs including retry", sum, interval.Seconds())
+			}
+		}
+	}
+	if !found {
+		t.Error("duration of /status not recorded")
+	}
+
+	collector.RemoveDevice(device)
+	if count := testutil.CollectAndCount(collector.commands); count != 0 {
+		t.Errorf("commands after RemoveDevice = %d, want 0", count)
+	}
+	if !strings.HasPrefix(device, "127.0.0.1:") {
+		t.Errorf("device label = %q, want wda host", device)
+	}
+}
+
+func TestCollectorThermalState(t *testing.T) {
+	tests := []struct {
+		name       string
+		deviceInfo string
+		want       int
+	}{
+		{"serious", `{"thermalState":"serious"}`, 1},
+		{"missing", `{"model":"iPhone"}`, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				value := "null"
+				switch {
+				case r.URL.Path == "/status":
+					value = `{"ready":true}`
+				case strings.HasSuffix(r.URL.Path, "/wda/batteryInfo"):
+					value = `{"level":0.5,"state":2}`
+				case strings.HasSuffix(r.URL.Path, "/wda/device/info"):
+					value = tt.deviceInfo
+				case strings.HasSuffix(r.URL.Path, "/wda/locked"):
+					value = "false"
+				}
+				w.Header().Set("Content-Type", WdaGo.ContentTypeJson)
+				w.Write([]byte(`{"value":` + value + `,"sessionId":"` + testSessionId + `"}`))
+			}))
+			defer server.Close()
+
+			session := WdaGo.GetWdaSession(server.URL)
+			session.AttachSession(testSessionId)
+			collector := NewCollector()
+			collector.AddDevice("phone", session)
+
+			// wda没有返回温度状态时不输出温度指标，其他设备状态照常输出
+			if got := testutil.CollectAndCount(collector, "wda_device_thermal_state"); got != tt.want {
+				t.Errorf("thermal state metrics = %d, want %d", got, tt.want)
+			}
+			if got := testutil.CollectAndCount(collector, "wda_device_battery_level"); got != 1 {
+				t.Errorf("battery level metrics = %d, want 1", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,441 @@
+package WdaGo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image/jpeg"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+type RecordFormat int
+
+const (
+	// RecordFrames 每帧保存为一个jpg文件
+	RecordFrames RecordFormat = iota
+	// RecordMjpeg 所有帧依次写入一个.mjpeg文件
+	RecordMjpeg
+	// RecordAvi 写入MJPG编码的avi文件
+	RecordAvi
+)
+
+const DefaultRecordFps = 10
+
+// RecordOption 录屏参数
+//
+//	OutputPath RecordFrames时为目录，其他格式为文件路径
+//	MjpegUrl   mjpeg服务地址，为空时根据wda地址和MjpegPort生成
+//	Settings   不为nil时在开始录屏前设置mjpeg视频流参数
+type RecordOption struct {
+	Format     RecordFormat
+	OutputPath string
+	MjpegUrl   string
+	MjpegPort  int
+	Settings   *MjpegSettings
+}
+
+// RecordResult 录屏结果
+type RecordResult struct {
+	OutputPath string
+	Frames     int64
+	Dropped    int64
+	Duration   time.Duration
+}
+
+// MjpegRecorder 读取mjpeg视频流并保存到磁盘
+type MjpegRecorder struct {
+	option  RecordOption
+	stream  *MjpegStream
+	writer  frameWriter
+	started time.Time
+	frames  int64
+	err     error
+	wg      sync.WaitGroup
+}
+
+type frameWriter interface {
+	WriteFrame(data []byte) error
+	Close(duration time.Duration) error
+}
+
+// NewMjpegRecorder 创建录屏器，option.MjpegUrl不能为空
+func NewMjpegRecorder(option RecordOption) (*MjpegRecorder, error) {
+	if option.MjpegUrl == "" {
+		return nil, fmt.Errorf(" Mjpeg url is empty ")
+	}
+	if option.OutputPath == "" {
+		return nil, fmt.Errorf(" Record output path is empty ")
+	}
+
+	return &MjpegRecorder{
+		option: option,
+		stream: NewMjpegStream(option.MjpegUrl),
+	}, nil
+}
+
+// Start 开始录屏
+func (recorder *MjpegRecorder) Start() error {
+	writer, err := newFrameWriter(recorder.option.Format, recorder.option.OutputPath)
+	if err != nil {
+		return err
+	}
+
+	frames, err := recorder.stream.Start()
+	if err != nil {
+		writer.Close(0)
+		return err
+	}
+
+	recorder.writer = writer
+	recorder.started = time.Now()
+	recorder.wg.Add(1)
+	go func() {
+		defer recorder.wg.Done()
+		for frame := range frames {
+			if recorder.err != nil {
+				continue
+			}
+			if err := writer.WriteFrame(frame.Data); err != nil {
+				recorder.err = err
+				continue
+			}
+			recorder.frames++
+		}
+	}()
+	return nil
+}
+
+// Stop 停止录屏并关闭输出文件
+func (recorder *MjpegRecorder) Stop() (*RecordResult, error) {
+	if recorder.writer == nil {
+		return nil, fmt.Errorf(" Recorder is not started ")
+	}
+
+	stopErr := recorder.stream.Stop()
+	recorder.wg.Wait()
+	duration := time.Since(recorder.started)
+
+	closeErr := recorder.writer.Close(duration)
+	recorder.writer = nil
+
+	result := &RecordResult{
+		OutputPath: recorder.option.OutputPath,
+		Frames:     recorder.frames,
+		Dropped:    recorder.stream.Dropped(),
+		Duration:   duration,
+	}
+
+	for _, err := range []error{recorder.err, recorder.stream.Err(), closeErr, stopErr} {
+		if err != nil {
+			return result, fmt.Errorf(" Record mjpeg stream failed :%w", err)
+		}
+	}
+	return result, nil
+}
+
+// StartRecording 开始录制当前设备屏幕，同一个session同时只能有一个录屏，
+// DeleteSession时会自动停止
+func (session *WdaSession) StartRecording(option RecordOption) (*MjpegRecorder, error) {
+	session, end := session.begin("WdaSession.StartRecording")
+	defer end()
+
+	if session.recorder != nil {
+		return nil, fmt.Errorf(" Recording already started in this session ")
+	}
+
+	if option.Settings != nil {
+		if err := session.SetMjpegSettings(*option.Settings); err != nil {
+			return nil, err
+		}
+	}
+
+	if option.MjpegUrl == "" {
+		streamUrl, err := session.MjpegUrl(option.MjpegPort)
+		if err != nil {
+			return nil, err
+		}
+		option.MjpegUrl = streamUrl
+	}
+
+	recorder, err := NewMjpegRecorder(option)
+	if err != nil {
+		return nil, err
+	}
+	if err = recorder.Start(); err != nil {
+		return nil, err
+	}
+
+	session.recorder = recorder
+	return recorder, nil
+}
+
+// StopRecording 停止当前session的录屏
+func (session *WdaSession) StopRecording() (*RecordResult, error) {
+	if session.recorder == nil {
+		return nil, fmt.Errorf(" No recording in this session ")
+	}
+
+	recorder := session.recorder
+	session.recorder = nil
+	return recorder.Stop()
+}
+
+func newFrameWriter(format RecordFormat, path string) (frameWriter, error) {
+	switch format {
+	case RecordFrames:
+		if err := os.MkdirAll(path, 0755); err != nil {
+			return nil, fmt.Errorf(" Create record dir failed :%w", err)
+		}
+		return &frameSequenceWriter{dir: path}, nil
+	case RecordMjpeg, RecordAvi:
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return nil, fmt.Errorf(" Create record dir failed :%w", err)
+		}
+		file, err := os.Create(path)
+		if err != nil {
+			return nil, fmt.Errorf(" Create record file failed :%w", err)
+		}
+		if format == RecordMjpeg {
+			return &mjpegFileWriter{file: file}, nil
+		}
+		return &aviWriter{file: file}, nil
+	default:
+		return nil, fmt.Errorf(" Not supported record format %d ", format)
+	}
+}
+
+// frameSequenceWriter 每帧保存为 frame_000000.jpg
+type frameSequenceWriter struct {
+	dir   string
+	index int
+}
+
+func (writer *frameSequenceWriter) WriteFrame(data []byte) error {
+	path := filepath.Join(writer.dir, fmt.Sprintf("frame_%06d.jpg", writer.index))
+	writer.index++
+	return os.WriteFile(path, data, 0644)
+}
+
+func (writer *frameSequenceWriter) Close(time.Duration) error {
+	return nil
+}
+
+// mjpegFileWriter 所有帧首尾相接写入同一个文件，ffmpeg等工具可直接读取
+type mjpegFileWriter struct {
+	file *os.File
+}
+
+func (writer *mjpegFileWriter) WriteFrame(data []byte) error {
+	_, err := writer.file.Write(data)
+	return err
+}
+
+func (writer *mjpegFileWriter) Close(time.Duration) error {
+	return writer.file.Close()
+}
+
+// aviWriter MJPG编码的avi文件，帧率在关闭时根据实际帧数和录制时长回填
+type aviWriter struct {
+	file       *os.File
+	width      uint32
+	height     uint32
+	moviStart  int64
+	index      []aviIndexEntry
+	maxFrame   uint32
+	headerDone bool
+}
+
+type aviIndexEntry struct {
+	offset uint32
+	size   uint32
+}
+
+const (
+	aviHeaderSize = 224
+	// avi头中需要回填的字段偏移
+	aviRiffSizeOffset     = 4
+	aviMicroSecOffset     = 32
+	aviTotalFramesOffset  = 48
+	aviMaxBytesOffset     = 36
+	aviStreamRateOffset   = 132
+	aviStreamLengthOffset = 140
+	aviMoviSizeOffset     = 216
+)
+
+func (writer *aviWriter) WriteFrame(data []byte) error {
+	if !writer.headerDone {
+		config, err := jpeg.DecodeConfig(bytes.NewReader(data))
+		if err != nil {
+			return fmt.Errorf(" Decode first frame failed :%w", err)
+		}
+		writer.width = uint32(config.Width)
+		writer.height = uint32(config.Height)
+		if err = writer.writeHeader(); err != nil {
+			return err
+		}
+		writer.headerDone = true
+	}
+
+	offset, err := writer.file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+
+	size := uint32(len(data))
+	buf := new(bytes.Buffer)
+	buf.WriteString("00dc")
+	binary.Write(buf, binary.LittleEndian, size)
+	buf.Write(data)
+	if size%2 == 1 {
+		buf.WriteByte(0)
+	}
+	if _, err = writer.file.Write(buf.Bytes()); err != nil {
+		return err
+	}
+
+	// idx1中的偏移相对于movi标记
+	writer.index = append(writer.index, aviIndexEntry{
+		offset: uint32(offset - writer.moviStart),
+		size:   size,
+	})
+	if size > writer.maxFrame {
+		writer.maxFrame = size
+	}
+	return nil
+}
+
+func (writer *aviWriter) writeHeader() error {
+	buf := new(bytes.Buffer)
+	le := func(v interface{}) { binary.Write(buf, binary.LittleEndian, v) }
+
+	buf.WriteString("RIFF")
+	le(uint32(0))
+	buf.WriteString("AVI ")
+
+	buf.WriteString("LIST")
+	le(uint32(192))
+	buf.WriteString("hdrl")
+
+	// avih
+	buf.WriteString("avih")
+	le(uint32(56))
+	le(uint32(1000000 / DefaultRecordFps)) // microSecPerFrame
+	le(uint32(0))                          // maxBytesPerSec
+	le(uint32(0))                          // paddingGranularity
+	le(uint32(0x10))                       // flags: AVIF_HASINDEX
+	le(uint32(0))                          // totalFrames
+	le(uint32(0))                          // initialFrames
+	le(uint32(1))                          // streams
+	le(uint32(0))                          // suggestedBufferSize
+	le(writer.width)
+	le(writer.height)
+	le([4]uint32{})
+
+	buf.WriteString("LIST")
+	le(uint32(116))
+	buf.WriteString("strl")
+
+	// strh
+	buf.WriteString("strh")
+	le(uint32(56))
+	buf.WriteString("vids")
+	buf.WriteString("MJPG")
+	le(uint32(0))                // flags
+	le(uint16(0))                // priority
+	le(uint16(0))                // language
+	le(uint32(0))                // initialFrames
+	le(uint32(1))                // scale
+	le(uint32(DefaultRecordFps)) // rate
+	le(uint32(0))                // start
+	le(uint32(0))                // length
+	le(uint32(0))                // suggestedBufferSize
+	le(int32(-1))                // quality
+	le(uint32(0))                // sampleSize
+	le([4]uint16{0, 0, uint16(writer.width), uint16(writer.height)})
+
+	// strf BITMAPINFOHEADER
+	buf.WriteString("strf")
+	le(uint32(40))
+	le(uint32(40))
+	le(int32(writer.width))
+	le(int32(writer.height))
+	le(uint16(1))
+	le(uint16(24))
+	buf.WriteString("MJPG")
+	le(writer.width * writer.height * 3)
+	le([4]uint32{})
+
+	buf.WriteString("LIST")
+	le(uint32(0))
+	buf.WriteString("movi")
+
+	if buf.Len() != aviHeaderSize {
+		return fmt.Errorf(" Build avi header failed, size is %d ", buf.Len())
+	}
+	if _, err := writer.file.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf(" Write avi header failed :%w", err)
+	}
+	writer.moviStart = aviHeaderSize - 4
+	return nil
+}
+
+func (writer *aviWriter) Close(duration time.Duration) error {
+	defer writer.file.Close()
+	if !writer.headerDone {
+		return nil
+	}
+
+	end, err := writer.file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	moviSize := uint32(end - writer.moviStart)
+
+	buf := new(bytes.Buffer)
+	buf.WriteString("idx1")
+	binary.Write(buf, binary.LittleEndian, uint32(16*len(writer.index)))
+	for _, entry := range writer.index {
+		buf.WriteString("00dc")
+		binary.Write(buf, binary.LittleEndian, uint32(0x10)) // AVIIF_KEYFRAME
+		binary.Write(buf, binary.LittleEndian, entry.offset)
+		binary.Write(buf, binary.LittleEndian, entry.size)
+	}
+	if _, err = writer.file.Write(buf.Bytes()); err != nil {
+		return err
+	}
+	fileSize := uint32(end) + uint32(buf.Len())
+
+	frames := uint32(len(writer.index))
+	fps := uint32(DefaultRecordFps)
+	if frames > 0 && duration > 0 {
+		fps = uint32(float64(frames)/duration.Seconds() + 0.5)
+		if fps == 0 {
+			fps = 1
+		}
+	}
+
+	patches := []struct {
+		offset int64
+		value  uint32
+	}{
+		{aviRiffSizeOffset, fileSize - 8},
+		{aviMicroSecOffset, 1000000 / fps},
+		{aviMaxBytesOffset, writer.maxFrame * fps},
+		{aviTotalFramesOffset, frames},
+		{aviStreamRateOffset, fps},
+		{aviStreamLengthOffset, frames},
+		{aviMoviSizeOffset, moviSize},
+	}
+	for _, patch := range patches {
+		value := make([]byte, 4)
+		binary.LittleEndian.PutUint32(value, patch.value)
+		if _, err = writer.file.WriteAt(value, patch.offset); err != nil {
+			return fmt.Errorf(" Update avi header failed :%w", err)
+		}
+	}
+	return nil
+}
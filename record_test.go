@@ -0,0 +1,180 @@
+package WdaGo
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"image/jpeg"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func jpegFrame(t *testing.T, w, h int, seed int64) []byte {
+	t.Helper()
+	buf := new(bytes.Buffer)
+	if err := jpeg.Encode(buf, noiseImage(w, h, seed), nil); err != nil {
+		t.Fatalf("jpeg.Encode() error = %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestAviWriter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "record.avi")
+	writer, err := newFrameWriter(RecordAvi, path)
+	if err != nil {
+		t.Fatalf("newFrameWriter() error = %v", err)
+	}
+
+	frames := [][]byte{jpegFrame(t, 32, 24, 1), jpegFrame(t, 32, 24, 2), jpegFrame(t, 32, 24, 3), jpegFrame(t, 32, 24, 4)}
+	// 保证有一帧为奇数长度，写入时需要补齐到偶数
+	if len(frames[1])%2 == 0 {
+		frames[1] = append(frames[1], 0)
+	}
+	for _, frame := range frames {
+		if err = writer.WriteFrame(frame); err != nil {
+			t.Fatalf("WriteFrame() error = %v", err)
+		}
+	}
+	if err = writer.Close(2 * time.Second); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u32 := func(offset int) uint32 { return binary.LittleEndian.Uint32(data[offset:]) }
+
+	if string(data[0:4]) != "RIFF" || string(data[8:12]) != "AVI " {
+		t.Fatalf("missing RIFF AVI header: %q", data[:12])
+	}
+	if got := u32(aviRiffSizeOffset); got != uint32(len(data)-8) {
+		t.Errorf("riff size = %d, want %d", got, len(data)-8)
+	}
+
+	const fps = 2
+	tests := []struct {
+		name   string
+		offset int
+		want   uint32
+	}{
+		{"microSecPerFrame", aviMicroSecOffset, 1000000 / fps},
+		{"totalFrames", aviTotalFramesOffset, uint32(len(frames))},
+		{"width", 64, 32},
+		{"height", 68, 24},
+		{"stream rate", aviStreamRateOffset, fps},
+		{"stream length", aviStreamLengthOffset, uint32(len(frames))},
+	}
+	for _, tt := range tests {
+		if got := u32(tt.offset); got != tt.want {
+			t.Errorf("%s = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+
+	moviStart := aviHeaderSize - 4
+	if string(data[moviStart:aviHeaderSize]) != "movi" {
+		t.Fatalf("movi list not at %d", moviStart)
+	}
+	idx := moviStart + int(u32(aviMoviSizeOffset))
+	if string(data[idx:idx+4]) != "idx1" {
+		t.Fatalf("idx1 not found after movi list, got %q", data[idx:idx+4])
+	}
+	if got := u32(idx + 4); got != uint32(16*len(frames)) {
+		t.Fatalf("idx1 size = %d, want %d", got, 16*len(frames))
+	}
+
+	for i, frame := range frames {
+		entry := idx + 8 + 16*i
+		offset := moviStart + int(u32(entry+8))
+		size := u32(entry + 12)
+		if string(data[offset:offset+4]) != "00dc" {
+			t.Errorf("frame %d: index offset does not point to 00dc chunk", i)
+			continue
+		}
+		if size != uint32(len(frame)) || u32(offset+4) != size {
+			t.Errorf("frame %d: size = %d, want %d", i, size, len(frame))
+		}
+		if !bytes.Equal(data[offset+8:offset+8+int(size)], frame) {
+			t.Errorf("frame %d: data mismatch", i)
+		}
+	}
+}
+
+func TestAviWriterWithoutFrames(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "empty.avi")
+	writer, err := newFrameWriter(RecordAvi, path)
+	if err != nil {
+		t.Fatalf("newFrameWriter() error = %v", err)
+	}
+	if err = writer.Close(time.Second); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if info, err := os.Stat(path); err != nil || info.Size() != 0 {
+		t.Errorf("empty recording should leave an empty file, got %v, %v", info, err)
+	}
+}
+
+func TestAviWriterRejectsNonJpeg(t *testing.T) {
+	writer, err := newFrameWriter(RecordAvi, filepath.Join(t.TempDir(), "bad.avi"))
+	if err != nil {
+		t.Fatalf("newFrameWriter() error = %v", err)
+	}
+	defer writer.Close(0)
+	if err = writer.WriteFrame([]byte("not a jpeg")); err == nil {
+		t.Error("WriteFrame() with invalid jpeg: want error")
+	}
+}
+
+func TestReadMjpegPart(t *testing.T) {
+	frame := jpegFrame(t, 8, 8, 5)
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"content length", "--BoundaryString\r\nContent-Type: image/jpeg\r\nContent-Length: " +
+			strconv.Itoa(len(frame)) + "\r\n\r\n" + string(frame) + "\r\n"},
+		{"jpeg end marker", "--BoundaryString\r\nContent-Type: image/jpeg\r\n\r\n" + string(frame) + "\r\n"},
+		{"header without boundary", "\r\nContent-type: image/jpeg\r\ncontent-length: " +
+			strconv.Itoa(len(frame)) + "\r\n\r\n" + string(frame)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 连续两帧，第二帧同样能读取
+			reader := bufio.NewReader(strings.NewReader(tt.input + tt.input))
+			for i := 0; i < 2; i++ {
+				data, err := readMjpegPart(reader)
+				if err != nil {
+					t.Fatalf("readMjpegPart() frame %d error = %v", i, err)
+				}
+				if !bytes.Equal(data, frame) {
+					t.Fatalf("readMjpegPart() frame %d got %d bytes, want %d", i, len(data), len(frame))
+				}
+				if _, err = jpeg.DecodeConfig(bytes.NewReader(data)); err != nil {
+					t.Errorf("frame %d is not a valid jpeg: %v", i, err)
+				}
+			}
+		})
+	}
+}
+
+func TestReadMjpegPartErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"invalid content length", "--b\r\nContent-Length: abc\r\n\r\n"},
+		{"too large", "--b\r\nContent-Length: 999999999\r\n\r\n"},
+		{"truncated", "--b\r\nContent-Length: 10\r\n\r\nabc"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := readMjpegPart(bufio.NewReader(strings.NewReader(tt.input))); err == nil {
+				t.Error("readMjpegPart() want error")
+			}
+		})
+	}
+}
@@ -0,0 +1,213 @@
+package recorder
+
+import (
+	"fmt"
+	"go/format"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Ning9527fff/WdaGo/pageobject"
+	"github.com/Ning9527fff/WdaGo/scenario"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultElementTimeout 导出代码中等待元素出现的时间
+const DefaultElementTimeout = 10 * time.Second
+
+// Scenario 将录制的操作转换为场景
+func (recorder *Recorder) Scenario(name string) *scenario.Scenario {
+	sc := &scenario.Scenario{Name: name}
+	for _, action := range recorder.Actions() {
+		step := scenario.Step{
+			Action:      action.Action,
+			Locator:     action.Locator,
+			Duration:    scenario.Duration(action.Duration),
+			Text:        action.Text,
+			Attribute:   action.Attribute,
+			Expected:    action.Expected,
+			BundleId:    action.BundleId,
+			Arguments:   action.Arguments,
+			Environment: action.Environment,
+			Button:      action.Button,
+		}
+		if action.Locator == nil && action.Point != nil {
+			step.Point = []float64{action.Point.X, action.Point.Y}
+		}
+		if action.Action == scenario.ActionSwipe {
+			step.Point = nil
+			step.From = []float64{action.Point.X, action.Point.Y}
+			step.To = []float64{action.To.X, action.To.Y}
+		}
+		// 没有定位方式的元素操作无法回放，跳过
+		if step.Locator == nil && step.Point == nil && needsElement(action.Action) {
+			continue
+		}
+		sc.Steps = append(sc.Steps, step)
+	}
+	return sc
+}
+
+// YAML 将录制的操作导出为yaml场景
+func (recorder *Recorder) YAML(name string) ([]byte, error) {
+	data, err := yaml.Marshal(recorder.Scenario(name))
+	if err != nil {
+		return nil, fmt.Errorf(" Format scenario failed :%v", err)
+	}
+	return data, nil
+}
+
+// GoTest 将录制的操作导出为go测试代码，元素操作使用WdaGo.Element句柄
+func (recorder *Recorder) GoTest(testName, wdaUrl string) ([]byte, error) {
+	var body strings.Builder
+	usesTime := false
+	for _, action := range recorder.Actions() {
+		comment, code := actionCode(action)
+		if code == "" {
+			continue
+		}
+		if strings.Contains(code, "time.") {
+			usesTime = true
+		}
+		fmt.Fprintf(&body, "\n// %s\n%s", comment, code)
+	}
+
+	imports := "\"testing\"\n"
+	if usesTime {
+		imports += "\"time\"\n"
+	}
+	source := fmt.Sprintf(`package wdatest
+
+import (
+	%s
+	WdaGo "github.com/Ning9527fff/WdaGo"
+)
+
+// %s generated by wdago recorder
+func %s(t *testing.T) {
+	session := WdaGo.GetWdaSession(%s)
+	if err := session.GetSession(""); err != nil {
+		t.Fatal(err)
+	}
+	defer session.DeleteSession()
+%s}
+`, imports, testName, testName, strconv.Quote(wdaUrl), body.String())
+
+	code, err := format.Source([]byte(source))
+	if err != nil {
+		return nil, fmt.Errorf(" Format generated code failed :%v", err)
+	}
+	return code, nil
+}
+
+// actionCode 生成单个操作的注释和代码，无法回放的操作生成TODO注释
+func actionCode(action Action) (string, string) {
+	comment := action.Action
+	switch {
+	case action.Element != "":
+		comment += " " + action.Element
+	case action.Point != nil:
+		comment += fmt.Sprintf(" %s,%s", float(action.Point.X), float(action.Point.Y))
+	case action.BundleId != "":
+		comment += " " + action.BundleId
+	}
+
+	element := ""
+	if action.Locator != nil {
+		element = fmt.Sprintf("session.Element(%s).WithTimeout(%d * time.Second)",
+			pageobject.LocatorExpr(*action.Locator), DefaultElementTimeout/time.Second)
+	}
+	if element == "" && needsElement(action.Action) && action.Point == nil {
+		return comment, fmt.Sprintf("// TODO element %s was not found by a recorded locator\n", action.Element)
+	}
+
+	switch action.Action {
+	case scenario.ActionTap:
+		if element != "" {
+			return comment, call(element + ".Click()")
+		}
+		return comment, call(fmt.Sprintf("session.TapWithLocation(WdaGo.ElementLocation{X: %s, Y: %s})", float(action.Point.X), float(action.Point.Y)))
+	case scenario.ActionDoubleTap, scenario.ActionLongPress:
+		point := fmt.Sprintf("%s, %s", float(action.Point.X), float(action.Point.Y))
+		if element != "" {
+			return comment, elementPoint(element, action)
+		}
+		if action.Action == scenario.ActionDoubleTap {
+			return comment, call("session.DoubleTapWithLocation(" + point + ")")
+		}
+		return comment, call(fmt.Sprintf("session.TouchAndHoldWithLocation(%s, %s)", point, float(action.Duration.Seconds())))
+	case scenario.ActionType:
+		return comment, call(fmt.Sprintf("%s.TypeText(%s)", element, strconv.Quote(action.Text)))
+	case scenario.ActionClear:
+		return comment, call(element + ".Clear()")
+	case scenario.ActionKeys:
+		return comment, call(fmt.Sprintf("session.SendKeys(%s)", strconv.Quote(action.Text)))
+	case scenario.ActionSwipe:
+		return comment, call(fmt.Sprintf("session.SwipeWithLocation(%s, %s, %s, %s, %s)",
+			float(action.Point.X), float(action.Point.Y), float(action.To.X), float(action.To.Y), float(action.Duration.Seconds())))
+	case scenario.ActionHome:
+		return comment, call("session.BackToHomePage()")
+	case scenario.ActionLaunch:
+		if len(action.Arguments) == 0 && len(action.Environment) == 0 {
+			return comment, call(fmt.Sprintf("session.LaunchApp(%s)", strconv.Quote(action.BundleId)))
+		}
+		option := "BundleId: " + strconv.Quote(action.BundleId)
+		if len(action.Arguments) > 0 {
+			option += fmt.Sprintf(", Arguments: %#v", action.Arguments)
+		}
+		if len(action.Environment) > 0 {
+			option += fmt.Sprintf(", Environment: %#v", action.Environment)
+		}
+		return comment, call("session.LaunchAppWithOption(WdaGo.AppLaunchOption{" + option + "})")
+	case scenario.ActionTerminate:
+		return comment, call(fmt.Sprintf("session.TerminateApp(%s)", strconv.Quote(action.BundleId)))
+	case scenario.ActionActivate:
+		return comment, call(fmt.Sprintf("session.ActivateApp(%s)", strconv.Quote(action.BundleId)))
+	case scenario.ActionAlert:
+		switch action.Button {
+		case "accept":
+			return comment, call("session.AlertAccept()")
+		case "dismiss":
+			return comment, call("session.AlertDismiss()")
+		default:
+			return comment, call(fmt.Sprintf("session.AlertAccept(%s)", strconv.Quote(action.Button)))
+		}
+	case scenario.ActionAssertText:
+		return comment, fmt.Sprintf("if text, err := %s.Text(); err != nil {\nt.Fatal(err)\n} else if text != %s {\nt.Errorf(\"text = %%q, want %%q\", text, %s)\n}\n",
+			element, strconv.Quote(action.Expected), strconv.Quote(action.Expected))
+	case scenario.ActionAssertAttribute:
+		attribute := strconv.Quote(action.Attribute)
+		return comment, fmt.Sprintf("if value, err := %s.Attribute(%s); err != nil {\nt.Fatal(err)\n} else if value != %s {\nt.Errorf(\"attribute %%s = %%q, want %%q\", %s, value, %s)\n}\n",
+			element, attribute, strconv.Quote(action.Expected), attribute, strconv.Quote(action.Expected))
+	case scenario.ActionAssertVisible:
+		return comment, fmt.Sprintf("if visible, err := %s.IsDisplayed(); err != nil {\nt.Fatal(err)\n} else if !visible {\nt.Error(\"element is not visible\")\n}\n", element)
+	}
+	return comment, ""
+}
+
+// elementPoint 对元素中心点双击或者长按
+func elementPoint(element string, action Action) string {
+	method := "session.DoubleTapWithLocation(center.X, center.Y)"
+	if action.Action == scenario.ActionLongPress {
+		method = fmt.Sprintf("session.TouchAndHoldWithLocation(center.X, center.Y, %s)", float(action.Duration.Seconds()))
+	}
+	return fmt.Sprintf("{\nrect, err := %s.Rect()\nif err != nil {\nt.Fatal(err)\n}\ncenter := rect.Center()\n%s}\n",
+		element, call(method))
+}
+
+func needsElement(action string) bool {
+	switch action {
+	case scenario.ActionTap, scenario.ActionDoubleTap, scenario.ActionLongPress, scenario.ActionType, scenario.ActionClear,
+		scenario.ActionAssertText, scenario.ActionAssertAttribute, scenario.ActionAssertVisible:
+		return true
+	}
+	return false
+}
+
+func call(expr string) string {
+	return fmt.Sprintf("if err := %s; err != nil {\nt.Fatal(err)\n}\n", expr)
+}
+
+func float(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
@@ -0,0 +1,331 @@
+// Package recorder 录制通过WdaSession执行的操作，导出为go测试代码或者yaml场景
+//
+// 录制基于WdaSession的命令回调，shell、查看器以及代码中执行的操作都会被记录：
+// 点击、输入等元素操作使用查找该元素时的定位方式，坐标点击在能匹配到元素时转换为元素定位，
+// 读取元素文本、属性和可见性的操作转换为断言
+//
+//	rec := recorder.New(session)
+//	rec.Start()
+//	...
+//	code, _ := rec.GoTest("TestLogin", "http://127.0.0.1:8100")
+package recorder
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	WdaGo "github.com/Ning9527fff/WdaGo"
+	"github.com/Ning9527fff/WdaGo/scenario"
+	"github.com/tidwall/gjson"
+)
+
+var (
+	sessionPathPattern = regexp.MustCompile(`^/session/[^/]+`)
+	elementPathPattern = regexp.MustCompile(`^/element/([^/]+)(/.*)?$`)
+)
+
+// Action 录制的一个操作，Action为scenario中的步骤类型
+//
+//	Locator  元素操作或者由坐标转换得到的元素定位，无法得到定位时为nil
+//	Element  元素描述，用于生成注释
+//	Point    坐标操作的坐标，swipe时为起点
+//	Duration 长按或者滑动前按下的时间
+type Action struct {
+	Action      string                 `json:"action"`
+	Locator     *WdaGo.Locator         `json:"locator,omitempty"`
+	Element     string                 `json:"element,omitempty"`
+	Point       *WdaGo.ElementLocation `json:"point,omitempty"`
+	To          *WdaGo.ElementLocation `json:"to,omitempty"`
+	Duration    time.Duration          `json:"duration,omitempty"`
+	Text        string                 `json:"text,omitempty"`
+	Attribute   string                 `json:"attribute,omitempty"`
+	Expected    string                 `json:"expected,omitempty"`
+	BundleId    string                 `json:"bundleId,omitempty"`
+	Arguments   []string               `json:"arguments,omitempty"`
+	Environment map[string]string      `json:"environment,omitempty"`
+	Button      string                 `json:"button,omitempty"`
+	Time        time.Time              `json:"time"`
+}
+
+// Recorder 操作录制，UpgradeTaps为true时将坐标点击转换为元素定位
+//
+// 坐标转换使用最近一次获取的页面树，操作执行后页面树视为过期，
+// 需要在点击前获取页面树(查看器刷新页面或者调用Snapshot)才能转换
+type Recorder struct {
+	UpgradeTaps bool
+
+	session   *WdaGo.WdaSession
+	mu        sync.Mutex
+	recording bool
+	actions   []Action
+	elements  map[string]elementInfo
+	tree      *WdaGo.SourceNode
+	treeFresh bool
+}
+
+// elementInfo 元素id对应的定位方式和描述
+type elementInfo struct {
+	locator WdaGo.Locator
+	title   string
+}
+
+// New 创建录制器并监听session的命令，需要调用Start开始录制
+func New(session *WdaGo.WdaSession) *Recorder {
+	recorder := &Recorder{
+		UpgradeTaps: true,
+		session:     session,
+		elements:    make(map[string]elementInfo),
+	}
+	session.AddCommandObserver(recorder.observe)
+	return recorder
+}
+
+// Start 开始录制
+func (recorder *Recorder) Start() {
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	recorder.recording = true
+}
+
+// Stop 停止录制，已录制的操作保留
+func (recorder *Recorder) Stop() {
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	recorder.recording = false
+}
+
+// Recording 是否正在录制
+func (recorder *Recorder) Recording() bool {
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	return recorder.recording
+}
+
+// Reset 清空已录制的操作
+func (recorder *Recorder) Reset() {
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	recorder.actions = nil
+}
+
+// Actions 已录制的操作
+func (recorder *Recorder) Actions() []Action {
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	return append([]Action(nil), recorder.actions...)
+}
+
+// Snapshot 获取当前页面树，之后的坐标点击使用该页面树转换为元素定位
+func (recorder *Recorder) Snapshot() error {
+	// 页面树在observe中更新
+	_, err := recorder.session.GetSource(WdaGo.SourceFormatXml)
+	return err
+}
+
+// observe 作为CommandObserver解析每条命令，失败的命令不录制
+func (recorder *Recorder) observe(record WdaGo.CommandRecord) {
+	if record.Err != nil {
+		return
+	}
+	value := gjson.GetBytes(record.Response, "value")
+	if value.Get("error").Exists() {
+		return
+	}
+
+	path := commandPath(record.Url)
+	payload := gjson.ParseBytes(record.Payload)
+
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+
+	switch {
+	case record.Method == "GET" && path == "/source":
+		// 查看器和Snapshot获取的页面树，不录制时也更新，便于开始录制后立即转换坐标
+		if source := value.String(); strings.HasPrefix(strings.TrimSpace(source), "<") {
+			if tree, err := WdaGo.ParseSource(source); err == nil {
+				recorder.tree = tree
+				recorder.treeFresh = true
+			}
+		}
+		return
+	case record.Method == "POST" && path == "/elements":
+		using, locatorValue := payload.Get("using").String(), payload.Get("value").String()
+		for i, element := range value.Array() {
+			recorder.rememberElement(element.Get("ELEMENT").String(), WdaGo.Locator{Using: using, Value: locatorValue, Index: i})
+		}
+		return
+	case record.Method == "POST" && path == "/element":
+		recorder.rememberElement(value.Get("ELEMENT").String(), WdaGo.Locator{
+			Using: payload.Get("using").String(),
+			Value: payload.Get("value").String(),
+		})
+		return
+	}
+
+	if !recorder.recording {
+		return
+	}
+
+	action, ok := recorder.parseAction(record.Method, path, payload, value)
+	if !ok {
+		return
+	}
+	action.Time = record.Started
+	recorder.actions = append(recorder.actions, action)
+
+	// 操作之后界面可能变化，需要重新获取页面树
+	if record.Method == "POST" {
+		recorder.treeFresh = false
+	}
+}
+
+func (recorder *Recorder) parseAction(method, path string, payload, value gjson.Result) (Action, bool) {
+	if match := elementPathPattern.FindStringSubmatch(path); match != nil {
+		return recorder.parseElementAction(method, match[1], match[2], payload, value)
+	}
+
+	point := func() *WdaGo.ElementLocation {
+		return &WdaGo.ElementLocation{X: payload.Get("x").Float(), Y: payload.Get("y").Float()}
+	}
+
+	switch method + " " + path {
+	case "POST /wda/tap":
+		return recorder.pointAction(scenario.ActionTap, point()), true
+	case "POST /wda/doubleTap":
+		return recorder.pointAction(scenario.ActionDoubleTap, point()), true
+	case "POST /wda/touchAndHold":
+		action := recorder.pointAction(scenario.ActionLongPress, point())
+		action.Duration = seconds(payload.Get("duration").Float())
+		return action, true
+	case "POST /wda/dragfromtoforduration":
+		return Action{
+			Action:   scenario.ActionSwipe,
+			Point:    &WdaGo.ElementLocation{X: payload.Get("fromX").Float(), Y: payload.Get("fromY").Float()},
+			To:       &WdaGo.ElementLocation{X: payload.Get("toX").Float(), Y: payload.Get("toY").Float()},
+			Duration: seconds(payload.Get("duration").Float()),
+		}, true
+	case "POST /wda/keys":
+		return Action{Action: scenario.ActionKeys, Text: joinValue(payload.Get("value"))}, true
+	case "POST /wda/homescreen":
+		return Action{Action: scenario.ActionHome}, true
+	case "POST /wda/apps/launch":
+		action := Action{Action: scenario.ActionLaunch, BundleId: payload.Get("bundleId").String()}
+		for _, argument := range payload.Get("arguments").Array() {
+			action.Arguments = append(action.Arguments, argument.String())
+		}
+		payload.Get("environment").ForEach(func(key, val gjson.Result) bool {
+			if action.Environment == nil {
+				action.Environment = make(map[string]string)
+			}
+			action.Environment[key.String()] = val.String()
+			return true
+		})
+		return action, true
+	case "POST /wda/apps/terminate":
+		return Action{Action: scenario.ActionTerminate, BundleId: payload.Get("bundleId").String()}, true
+	case "POST /wda/apps/activate":
+		return Action{Action: scenario.ActionActivate, BundleId: payload.Get("bundleId").String()}, true
+	case "POST /alert/accept", "POST /alert/dismiss":
+		button := strings.TrimPrefix(path, "/alert/")
+		if name := payload.Get("name").String(); name != "" {
+			button = name
+		}
+		return Action{Action: scenario.ActionAlert, Button: button}, true
+	}
+	return Action{}, false
+}
+
+// parseElementAction 元素操作，读取文本、属性和可见性转换为断言
+func (recorder *Recorder) parseElementAction(method, elementId, command string, payload, value gjson.Result) (Action, bool) {
+	action := Action{Element: elementId}
+	if info, ok := recorder.elements[elementId]; ok {
+		locator := info.locator
+		action.Locator = &locator
+		action.Element = info.title
+	}
+
+	switch {
+	case method == "POST" && command == "/click":
+		action.Action = scenario.ActionTap
+	case method == "POST" && command == "/value":
+		action.Action = scenario.ActionType
+		action.Text = joinValue(payload.Get("value"))
+	case method == "POST" && command == "/clear":
+		action.Action = scenario.ActionClear
+	case method == "GET" && command == "/text":
+		action.Action = scenario.ActionAssertText
+		action.Expected = value.String()
+	case method == "GET" && strings.HasPrefix(command, "/attribute/"):
+		action.Action = scenario.ActionAssertAttribute
+		action.Attribute, _ = url.PathUnescape(strings.TrimPrefix(command, "/attribute/"))
+		action.Expected = value.String()
+	case method == "GET" && command == "/displayed":
+		if !value.Bool() {
+			return action, false
+		}
+		action.Action = scenario.ActionAssertVisible
+	default:
+		return action, false
+	}
+	return action, true
+}
+
+// pointAction 坐标操作，页面树未过期且坐标处的元素有唯一定位时转换为元素定位
+func (recorder *Recorder) pointAction(name string, point *WdaGo.ElementLocation) Action {
+	action := Action{Action: name, Point: point}
+	if !recorder.UpgradeTaps || !recorder.treeFresh || recorder.tree == nil {
+		return action
+	}
+
+	node := recorder.tree.ElementAt(point.X, point.Y)
+	if node == nil || node == recorder.tree {
+		return action
+	}
+	suggestions := WdaGo.SuggestLocators(recorder.tree, node)
+	if len(suggestions) == 0 || !suggestions[0].Unique {
+		return action
+	}
+
+	locator := suggestions[0].Locator
+	action.Locator = &locator
+	action.Element = node.Title()
+	return action
+}
+
+// rememberElement 记录元素id的定位方式，同一个元素多次查找时使用序号较小的定位方式
+func (recorder *Recorder) rememberElement(elementId string, locator WdaGo.Locator) {
+	if elementId == "" {
+		return
+	}
+	if known, ok := recorder.elements[elementId]; ok && known.locator.Index < locator.Index {
+		return
+	}
+	recorder.elements[elementId] = elementInfo{locator: locator, title: locator.String()}
+}
+
+// commandPath 去掉wda地址和session，如 /session/abc/wda/tap -> /wda/tap
+func commandPath(rawUrl string) string {
+	path := rawUrl
+	if parsed, err := url.Parse(rawUrl); err == nil {
+		path = parsed.Path
+	}
+	return sessionPathPattern.ReplaceAllString(path, "")
+}
+
+func joinValue(value gjson.Result) string {
+	if !value.IsArray() {
+		return value.String()
+	}
+	var text strings.Builder
+	for _, item := range value.Array() {
+		text.WriteString(item.String())
+	}
+	return text.String()
+}
+
+func seconds(value float64) time.Duration {
+	return time.Duration(value * float64(time.Second))
+}
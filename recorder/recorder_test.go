@@ -0,0 +1,267 @@
+package recorder
+
+import (
+	"encoding/json"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	WdaGo "github.com/Ning9527fff/WdaGo"
+	"github.com/Ning9527fff/WdaGo/scenario"
+)
+
+const testSessionId = "test-session"
+
+const loginSource = `<XCUIElementTypeApplication type="XCUIElementTypeApplication" name="Demo" visible="true" x="0" y="0" width="400" height="800">
+  <XCUIElementTypeButton type="XCUIElementTypeButton" name="login" visible="true" x="20" y="100" width="200" height="44"/>
+  <XCUIElementTypeButton type="XCUIElementTypeButton" name="login" visible="true" x="20" y="300" width="200" height="44"/>
+</XCUIElementTypeApplication>`
+
+// elementIds 模拟wda按accessibility id返回的元素id
+var elementIds = map[string]string{"login": "e-login", "name": "e-name", "title": "e-title"}
+
+// fakeWda 模拟录制中用到的wda接口，元素操作和坐标操作都返回成功
+func fakeWda(t *testing.T) *WdaGo.WdaSession {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/session/"+testSessionId)
+		value := "null"
+		switch {
+		case path == "/source":
+			data, _ := json.Marshal(loginSource)
+			value = string(data)
+		case path == "/elements":
+			var payload struct{ Value string }
+			json.NewDecoder(r.Body).Decode(&payload)
+			value = `[{"ELEMENT":"` + elementIds[payload.Value] + `"}]`
+		case path == "/element/e-title/text":
+			value = `"Welcome"`
+		}
+		w.Header().Set("Content-Type", WdaGo.ContentTypeJson)
+		w.Write([]byte(`{"value":` + value + `,"sessionId":"` + testSessionId + `"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	session := WdaGo.GetWdaSession(server.URL)
+	session.AttachSession(testSessionId)
+	return session
+}
+
+func accessibilityId(value string) *WdaGo.Locator {
+	return &WdaGo.Locator{Using: WdaGo.StrategyAccessibilityId, Value: value}
+}
+
+func TestRecorder(t *testing.T) {
+	session := fakeWda(t)
+	rec := New(session)
+
+	// 开始录制前的操作不录制
+	if err := session.LaunchApp("com.demo.before"); err != nil {
+		t.Fatal(err)
+	}
+	rec.Start()
+
+	steps := []func() error{
+		func() error { return session.LaunchApp("com.demo") },
+		func() error { return session.Element(*accessibilityId("login")).Click() },
+		func() error { return session.Element(*accessibilityId("name")).SetText("bob") },
+		func() error { _, err := session.Element(*accessibilityId("title")).Text(); return err },
+		rec.Snapshot,
+		// 页面树是最新的，坐标处的按钮有唯一定位
+		func() error { return session.TapWithLocation(WdaGo.ElementLocation{X: 50, Y: 320}) },
+		// 点击后页面树过期，保留坐标
+		func() error { return session.TapWithLocation(WdaGo.ElementLocation{X: 50, Y: 120}) },
+		func() error { return session.SendKeys("hi") },
+		func() error { return session.AlertAccept("Allow") },
+	}
+	for i, step := range steps {
+		if err := step(); err != nil {
+			t.Fatalf("step %d error = %v", i, err)
+		}
+	}
+	rec.Stop()
+	if err := session.LaunchApp("com.demo.after"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Action{
+		{Action: scenario.ActionLaunch, BundleId: "com.demo"},
+		{Action: scenario.ActionTap, Locator: accessibilityId("login")},
+		{Action: scenario.ActionClear, Locator: accessibilityId("name")},
+		{Action: scenario.ActionType, Locator: accessibilityId("name"), Text: "bob"},
+		{Action: scenario.ActionAssertText, Locator: accessibilityId("title"), Expected: "Welcome"},
+		{Action: scenario.ActionTap, Point: &WdaGo.ElementLocation{X: 50, Y: 320}},
+		{Action: scenario.ActionTap, Point: &WdaGo.ElementLocation{X: 50, Y: 120}},
+		{Action: scenario.ActionKeys, Text: "hi"},
+		{Action: scenario.ActionAlert, Button: "Allow"},
+	}
+	got := rec.Actions()
+	if len(got) != len(want) {
+		t.Fatalf("recorded %d actions, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i].Action != want[i].Action || got[i].BundleId != want[i].BundleId || got[i].Text != want[i].Text ||
+			got[i].Expected != want[i].Expected || got[i].Button != want[i].Button {
+			t.Errorf("action %d = %+v, want %+v", i, got[i], want[i])
+		}
+		if want[i].Locator != nil && (got[i].Locator == nil || *got[i].Locator != *want[i].Locator) {
+			t.Errorf("action %d locator = %v, want %v", i, got[i].Locator, want[i].Locator)
+		}
+		if want[i].Point != nil && (got[i].Point == nil || *got[i].Point != *want[i].Point) {
+			t.Errorf("action %d point = %v, want %v", i, got[i].Point, want[i].Point)
+		}
+		if got[i].Time.IsZero() {
+			t.Errorf("action %d has no time", i)
+		}
+	}
+
+	// 第一次坐标点击转换为页面树中唯一的定位方式，第二次因页面树过期不转换
+	if upgraded := got[5].Locator; upgraded == nil || upgraded.Using == WdaGo.StrategyAccessibilityId {
+		t.Errorf("tap on fresh tree locator = %v, want unique locator", upgraded)
+	}
+	if got[6].Locator != nil {
+		t.Errorf("tap on stale tree locator = %v, want nil", got[6].Locator)
+	}
+
+	rec.Reset()
+	if len(rec.Actions()) != 0 {
+		t.Error("Reset() kept actions")
+	}
+}
+
+func TestRecorderSkipsFailedCommands(t *testing.T) {
+	session := fakeWda(t)
+	rec := New(session)
+	rec.Start()
+
+	tests := []WdaGo.CommandRecord{
+		{Method: "POST", Url: "http://wda/session/s/wda/tap", Payload: []byte(`{"x":1,"y":2}`), Err: http.ErrHandlerTimeout},
+		{Method: "POST", Url: "http://wda/session/s/wda/tap", Payload: []byte(`{"x":1,"y":2}`),
+			Response: []byte(`{"value":{"error":"no such element","message":""}}`)},
+		{Method: "GET", Url: "http://wda/session/s/element/e1/displayed", Response: []byte(`{"value":false}`)},
+		{Method: "GET", Url: "http://wda/status", Response: []byte(`{"value":{}}`)},
+	}
+	for _, record := range tests {
+		rec.observe(record)
+	}
+	if actions := rec.Actions(); len(actions) != 0 {
+		t.Errorf("recorded %+v, want nothing", actions)
+	}
+}
+
+func TestRecorderElements(t *testing.T) {
+	rec := &Recorder{elements: make(map[string]elementInfo), recording: true}
+	records := []WdaGo.CommandRecord{
+		{Method: "POST", Url: "http://wda/session/s/elements", Payload: []byte(`{"using":"class name","value":"XCUIElementTypeCell"}`),
+			Response: []byte(`{"value":[{"ELEMENT":"c0"},{"ELEMENT":"c1"}]}`)},
+		// 同一个元素再次查找时保留序号较小的定位方式
+		{Method: "POST", Url: "http://wda/session/s/element", Payload: []byte(`{"using":"predicate string","value":"name == 'cell'"}`),
+			Response: []byte(`{"value":{"ELEMENT":"c1"}}`)},
+		{Method: "POST", Url: "http://wda/session/s/element/c1/click"},
+		{Method: "GET", Url: "http://wda/session/s/element/c0/attribute/accessibility%20label", Response: []byte(`{"value":"first"}`)},
+		{Method: "GET", Url: "http://wda/session/s/element/unknown/displayed", Response: []byte(`{"value":true}`)},
+		{Method: "POST", Url: "http://wda/session/s/wda/dragfromtoforduration",
+			Payload: []byte(`{"fromX":1,"fromY":2,"toX":3,"toY":4,"duration":0.5}`)},
+	}
+	for _, record := range records {
+		rec.observe(record)
+	}
+
+	actions := rec.Actions()
+	if len(actions) != 4 {
+		t.Fatalf("recorded %d actions, want 4: %+v", len(actions), actions)
+	}
+	if locator := actions[0].Locator; locator == nil || *locator != (WdaGo.Locator{Using: "predicate string", Value: "name == 'cell'"}) {
+		t.Errorf("click locator = %v, want predicate without index", locator)
+	}
+	if a := actions[1]; a.Action != scenario.ActionAssertAttribute || a.Attribute != "accessibility label" || a.Expected != "first" ||
+		a.Locator == nil || a.Locator.Index != 0 || a.Locator.Using != "class name" {
+		t.Errorf("attribute action = %+v", a)
+	}
+	if a := actions[2]; a.Action != scenario.ActionAssertVisible || a.Locator != nil || a.Element != "unknown" {
+		t.Errorf("visible action = %+v", a)
+	}
+	if a := actions[3]; a.Action != scenario.ActionSwipe || *a.To != (WdaGo.ElementLocation{X: 3, Y: 4}) || a.Duration != 500*time.Millisecond {
+		t.Errorf("swipe action = %+v", a)
+	}
+}
+
+func TestExport(t *testing.T) {
+	rec := &Recorder{actions: []Action{
+		{Action: scenario.ActionLaunch, BundleId: "com.demo", Arguments: []string{"-debug"}},
+		{Action: scenario.ActionTap, Locator: accessibilityId("login"), Element: `"login"`},
+		{Action: scenario.ActionTap, Point: &WdaGo.ElementLocation{X: 10, Y: 20.5}},
+		// 没有定位方式的元素操作无法回放
+		{Action: scenario.ActionType, Element: "e-unknown", Text: "lost"},
+		{Action: scenario.ActionLongPress, Locator: accessibilityId("menu"), Point: &WdaGo.ElementLocation{X: 1, Y: 1}, Duration: time.Second},
+		{Action: scenario.ActionSwipe, Point: &WdaGo.ElementLocation{X: 1, Y: 2}, To: &WdaGo.ElementLocation{X: 3, Y: 4}, Duration: 500 * time.Millisecond},
+		{Action: scenario.ActionAssertText, Locator: accessibilityId("title"), Expected: "Welcome"},
+		{Action: scenario.ActionAlert, Button: "dismiss"},
+	}}
+
+	sc := rec.Scenario("login")
+	if len(sc.Steps) != 7 {
+		t.Fatalf("Scenario() got %d steps, want 7", len(sc.Steps))
+	}
+	if step := sc.Steps[2]; len(step.Point) != 2 || step.Point[1] != 20.5 {
+		t.Errorf("coordinate tap point = %v", step.Point)
+	}
+	if step := sc.Steps[4]; step.Point != nil || len(step.From) != 2 || len(step.To) != 2 {
+		t.Errorf("swipe step = %+v", step)
+	}
+
+	data, err := rec.YAML("login")
+	if err != nil {
+		t.Fatalf("YAML() error = %v", err)
+	}
+	parsed, err := scenario.Parse(data)
+	if err != nil {
+		t.Fatalf("exported yaml does not parse: %v\n%s", err, data)
+	}
+	if parsed.Name != "login" || len(parsed.Steps) != len(sc.Steps) {
+		t.Errorf("parsed scenario = %+v", parsed)
+	}
+
+	code, err := rec.GoTest("TestLogin", "http://127.0.0.1:8100")
+	if err != nil {
+		t.Fatalf("GoTest() error = %v", err)
+	}
+	if _, err = parser.ParseFile(token.NewFileSet(), "login_test.go", code, 0); err != nil {
+		t.Fatalf("generated code does not parse: %v\n%s", err, code)
+	}
+	for _, want := range []string{
+		"func TestLogin(t *testing.T)",
+		`session := WdaGo.GetWdaSession("http://127.0.0.1:8100")`,
+		`session.LaunchAppWithOption(WdaGo.AppLaunchOption{BundleId: "com.demo", Arguments: []string{"-debug"}})`,
+		`session.Element(WdaGo.Locator{Using: WdaGo.StrategyAccessibilityId, Value: "login"}).WithTimeout(10 * time.Second).Click()`,
+		"session.TapWithLocation(WdaGo.ElementLocation{X: 10, Y: 20.5})",
+		"// TODO element e-unknown was not found by a recorded locator",
+		"session.TouchAndHoldWithLocation(center.X, center.Y, 1)",
+		"session.SwipeWithLocation(1, 2, 3, 4, 0.5)",
+		`} else if text != "Welcome" {`,
+		"session.AlertDismiss()",
+	} {
+		if !strings.Contains(string(code), want) {
+			t.Errorf("generated code missing %q\n%s", want, code)
+		}
+	}
+}
+
+func TestGoTestImportsTime(t *testing.T) {
+	rec := &Recorder{actions: []Action{{Action: scenario.ActionHome}}}
+	code, err := rec.GoTest("TestHome", "http://wda")
+	if err != nil {
+		t.Fatalf("GoTest() error = %v", err)
+	}
+	// 没有用到time时不能导入，否则生成的代码无法编译
+	if strings.Contains(string(code), `"time"`) {
+		t.Errorf("generated code imports unused time\n%s", code)
+	}
+	if !strings.Contains(string(code), "session.BackToHomePage()") {
+		t.Errorf("generated code missing home action\n%s", code)
+	}
+}
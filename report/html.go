@@ -0,0 +1,180 @@
+package report
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"strings"
+	"time"
+)
+
+type htmlReport struct {
+	Name     string
+	Started  string
+	Tests    int
+	Failures int
+	Duration string
+	Cases    []htmlCase
+}
+
+type htmlCase struct {
+	Name     string
+	Failed   bool
+	Failure  string
+	Duration string
+	Steps    []htmlStep
+	Images   []htmlImage
+	Texts    []htmlText
+}
+
+type htmlStep struct {
+	Step
+	Offset   float64
+	Width    float64
+	Duration string
+	Relative string
+}
+
+type htmlImage struct {
+	Name string
+	Src  template.URL
+}
+
+type htmlText struct {
+	Name    string
+	Content string
+}
+
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Name}}</title>
+<style>
+body { font-family: -apple-system, Helvetica, Arial, sans-serif; margin: 24px; color: #222; }
+h1 { margin-bottom: 4px; }
+.summary { color: #666; margin-bottom: 24px; }
+.case { border: 1px solid #ddd; border-radius: 6px; margin-bottom: 16px; }
+.case > summary { padding: 10px 14px; cursor: pointer; font-weight: bold; }
+.case.passed > summary { background: #eef8ee; }
+.case.failed > summary { background: #fbeaea; }
+.body { padding: 10px 14px; }
+.failure { color: #b00; white-space: pre-wrap; }
+table { border-collapse: collapse; width: 100%; font-size: 13px; }
+td, th { border-bottom: 1px solid #eee; padding: 4px 6px; text-align: left; vertical-align: top; }
+tr.fail td { background: #fff3f3; }
+.timeline { position: relative; width: 240px; height: 10px; background: #f3f3f3; }
+.bar { position: absolute; top: 0; height: 10px; min-width: 2px; background: #4a90d9; }
+tr.fail .bar { background: #d94a4a; }
+.args { font-family: monospace; color: #555; word-break: break-all; }
+.images img { max-width: 320px; border: 1px solid #ccc; margin: 8px 8px 0 0; }
+pre { max-height: 400px; overflow: auto; background: #f7f7f7; padding: 8px; font-size: 12px; }
+</style>
+</head>
+<body>
+<h1>{{.Name}}</h1>
+<div class="summary">{{.Started}} · {{.Tests}} tests · {{.Failures}} failed · {{.Duration}}</div>
+{{range .Cases}}
+<details class="case {{if .Failed}}failed{{else}}passed{{end}}"{{if .Failed}} open{{end}}>
+<summary>{{if .Failed}}✗{{else}}✓{{end}} {{.Name}} <small>({{.Duration}})</small></summary>
+<div class="body">
+{{if .Failed}}<div class="failure">{{.Failure}}</div>{{end}}
+<table>
+<tr><th>Start</th><th>Step</th><th>Command</th><th>Args</th><th>Status</th><th>Duration</th><th>Timeline</th></tr>
+{{range .Steps}}
+<tr class="{{if .Passed}}pass{{else}}fail{{end}}">
+<td>{{.Relative}}</td>
+<td>{{.Group}}</td>
+<td>{{.Name}}</td>
+<td class="args">{{.Args}}</td>
+<td>{{if .Passed}}{{.StatusCode}}{{else}}{{.StatusCode}} {{.WdaError}}{{.Error}}{{end}}</td>
+<td>{{.Duration}}</td>
+<td><div class="timeline"><div class="bar" style="left: {{printf "%.2f" .Offset}}%; width: {{printf "%.2f" .Width}}%"></div></div></td>
+</tr>
+{{end}}
+</table>
+{{if .Images}}<div class="images">{{range .Images}}<a href="{{.Src}}" title="{{.Name}}"><img src="{{.Src}}" alt="{{.Name}}"></a>{{end}}</div>{{end}}
+{{range .Texts}}<details><summary>{{.Name}}</summary><pre>{{.Content}}</pre></details>{{end}}
+</div>
+</details>
+{{end}}
+</body>
+</html>
+`))
+
+// WriteHTML 输出独立的HTML报告，截图以base64内嵌，不依赖其他文件
+func (reporter *Reporter) WriteHTML(path string) error {
+	cases := reporter.Cases()
+	report := htmlReport{
+		Name:    reporter.Name,
+		Started: reporter.started.Format("2006-01-02 15:04:05"),
+		Tests:   len(cases),
+	}
+
+	var total time.Duration
+	for _, testCase := range cases {
+		total += testCase.Duration
+		if testCase.Failed() {
+			report.Failures++
+		}
+		report.Cases = append(report.Cases, newHtmlCase(testCase))
+	}
+	report.Duration = total.Round(time.Millisecond).String()
+
+	var buffer bytes.Buffer
+	if err := htmlTemplate.Execute(&buffer, report); err != nil {
+		return fmt.Errorf(" Format html report failed :%v", err)
+	}
+	return writeFile(path, buffer.Bytes())
+}
+
+func newHtmlCase(testCase *TestCase) htmlCase {
+	result := htmlCase{
+		Name:     testCase.Name,
+		Failed:   testCase.Failed(),
+		Failure:  testCase.Failure,
+		Duration: testCase.Duration.Round(time.Millisecond).String(),
+	}
+
+	total := testCase.Duration
+	if total <= 0 {
+		total = time.Millisecond
+	}
+	for _, step := range testCase.Steps {
+		offset := step.Started.Sub(testCase.Started)
+		result.Steps = append(result.Steps, htmlStep{
+			Step:     step,
+			Offset:   percent(offset, total),
+			Width:    percent(step.Duration, total),
+			Duration: step.Duration.Round(time.Millisecond).String(),
+			Relative: "+" + offset.Round(time.Millisecond).String(),
+		})
+	}
+
+	for _, attachment := range testCase.Attachments {
+		if strings.HasPrefix(attachment.ContentType, "image/") {
+			result.Images = append(result.Images, htmlImage{
+				Name: attachment.Name,
+				Src:  template.URL("data:" + attachment.ContentType + ";base64," + base64.StdEncoding.EncodeToString(attachment.Data)),
+			})
+		} else {
+			result.Texts = append(result.Texts, htmlText{
+				Name:    attachment.Name,
+				Content: string(attachment.Data),
+			})
+		}
+	}
+	return result
+}
+
+func percent(value, total time.Duration) float64 {
+	p := float64(value) / float64(total) * 100
+	if p < 0 {
+		return 0
+	}
+	if p > 100 {
+		return 100
+	}
+	return p
+}
@@ -0,0 +1,168 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Time     string       `xml:"time,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name      string      `xml:"name,attr"`
+	Tests     int         `xml:"tests,attr"`
+	Failures  int         `xml:"failures,attr"`
+	Time      string      `xml:"time,attr"`
+	Timestamp string      `xml:"timestamp,attr"`
+	Cases     []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut *junitText    `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",cdata"`
+}
+
+type junitText struct {
+	Text string `xml:",cdata"`
+}
+
+// WriteJUnit 输出JUnit XML，附件写入同目录下的attachments目录，
+// 并以[[ATTACHMENT|path]]的形式写在system-out中，Jenkins等CI可以直接识别
+func (reporter *Reporter) WriteJUnit(path string) error {
+	cases := reporter.Cases()
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf(" Create report dir failed :%v", err)
+	}
+
+	suite := junitSuite{
+		Name:      reporter.Name,
+		Tests:     len(cases),
+		Timestamp: reporter.started.Format(time.RFC3339),
+	}
+	var total time.Duration
+	for i, testCase := range cases {
+		total += testCase.Duration
+		junit := junitCase{
+			Name:      testCase.Name,
+			ClassName: testCase.ClassName,
+			Time:      seconds(testCase.Duration),
+		}
+
+		var out strings.Builder
+		for _, step := range testCase.Steps {
+			out.WriteString(formatStep(step))
+			out.WriteString("\n")
+		}
+
+		if testCase.Failed() {
+			suite.Failures++
+			junit.Failure = &junitFailure{
+				Message: testCase.Failure,
+				Type:    "failure",
+				Text:    failureDetail(testCase),
+			}
+		}
+
+		for _, attachment := range testCase.Attachments {
+			name := fmt.Sprintf("%03d_%s_%s", i+1, fileName(testCase.Name), attachment.Name)
+			attachmentPath := filepath.Join(dir, "attachments", name)
+			if err := writeFile(attachmentPath, attachment.Data); err != nil {
+				return err
+			}
+			fmt.Fprintf(&out, "[[ATTACHMENT|%s]]\n", attachmentPath)
+		}
+		if out.Len() > 0 {
+			junit.SystemOut = &junitText{Text: out.String()}
+		}
+		suite.Cases = append(suite.Cases, junit)
+	}
+	suite.Time = seconds(total)
+
+	suites := junitSuites{
+		Name:     reporter.Name,
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Time:     suite.Time,
+		Suites:   []junitSuite{suite},
+	}
+
+	data, err := xml.MarshalIndent(suites, "", "  ")
+	if err != nil {
+		return fmt.Errorf(" Format junit report failed :%v", err)
+	}
+	return writeFile(path, append([]byte(xml.Header), data...))
+}
+
+// failureDetail 失败用例中失败的命令，便于在CI中直接看到wda返回的错误
+func failureDetail(testCase *TestCase) string {
+	var detail strings.Builder
+	detail.WriteString(testCase.Failure)
+	for _, step := range testCase.Steps {
+		if !step.Passed {
+			detail.WriteString("\n")
+			detail.WriteString(formatStep(step))
+		}
+	}
+	return detail.String()
+}
+
+func formatStep(step Step) string {
+	status := "PASS"
+	if !step.Passed {
+		status = "FAIL"
+	}
+
+	line := fmt.Sprintf("%s %s %v", status, step.Name, step.Duration.Round(time.Millisecond))
+	if step.Group != "" {
+		line = "[" + step.Group + "] " + line
+	}
+	if step.Args != "" {
+		line += " " + step.Args
+	}
+	if step.WdaError != "" {
+		line += " wda error: " + step.WdaError
+	} else if step.Error != "" {
+		line += " error: " + step.Error
+	}
+	return line
+}
+
+func seconds(duration time.Duration) string {
+	return fmt.Sprintf("%.3f", duration.Seconds())
+}
+
+var unsafeFileChars = strings.NewReplacer("/", "_", "\\", "_", ":", "_", " ", "_", "*", "_", "?", "_", "\"", "_", "<", "_", ">", "_", "|", "_")
+
+func fileName(name string) string {
+	return unsafeFileChars.Replace(name)
+}
+
+func writeFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf(" Create report dir failed :%v", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf(" Write report failed :%v", err)
+	}
+	return nil
+}
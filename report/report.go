@@ -0,0 +1,265 @@
+// Package report 记录WdaSession执行的每条wda命令，测试失败时保存截图和页面树，
+// 并输出JUnit XML和独立的HTML报告
+//
+//	reporter := report.NewReporter("smoke")
+//	reporter.Attach(session)
+//	reporter.Run("login", func() error { ... })
+//	reporter.WriteJUnit("report/junit.xml")
+//	reporter.WriteHTML("report/index.html")
+package report
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	WdaGo "github.com/Ning9527fff/WdaGo"
+	"github.com/tidwall/gjson"
+)
+
+const maxArgsLength = 512
+
+var (
+	sessionPathPattern = regexp.MustCompile(`/session/[^/]+`)
+	elementPathPattern = regexp.MustCompile(`/element/[^/]+`)
+)
+
+// Reporter 测试报告，记录测试用例以及用例中执行的wda命令
+type Reporter struct {
+	Name             string
+	CaptureOnFailure bool
+
+	mu        sync.Mutex
+	started   time.Time
+	session   *WdaGo.WdaSession
+	cases     []*TestCase
+	current   *TestCase
+	group     string
+	capturing bool
+}
+
+// TestCase 测试用例
+type TestCase struct {
+	Name        string
+	ClassName   string
+	Started     time.Time
+	Duration    time.Duration
+	Failure     string
+	Steps       []Step
+	Attachments []Attachment
+}
+
+// Step 一条wda命令，Group为命令所属的场景步骤等分组名
+type Step struct {
+	Name       string
+	Group      string
+	Method     string
+	Endpoint   string
+	Args       string
+	Started    time.Time
+	Duration   time.Duration
+	StatusCode int
+	Passed     bool
+	WdaError   string
+	Error      string
+}
+
+// Attachment 用例附件，如失败时的截图和页面树
+type Attachment struct {
+	Name        string
+	ContentType string
+	Data        []byte
+}
+
+func NewReporter(name string) *Reporter {
+	return &Reporter{
+		Name:             name,
+		CaptureOnFailure: true,
+		started:          time.Now(),
+	}
+}
+
+// Attach 记录session执行的所有wda命令，失败时使用该session截图
+func (reporter *Reporter) Attach(session *WdaGo.WdaSession) {
+	reporter.mu.Lock()
+	reporter.session = session
+	reporter.mu.Unlock()
+
+	session.AddCommandObserver(reporter.record)
+}
+
+// StartCase 开始一个测试用例，之后执行的命令都记录在该用例下
+func (reporter *Reporter) StartCase(name string) *TestCase {
+	reporter.mu.Lock()
+	defer reporter.mu.Unlock()
+
+	if reporter.current != nil {
+		reporter.finishCase(nil)
+	}
+
+	testCase := &TestCase{
+		Name:      name,
+		ClassName: reporter.Name,
+		Started:   time.Now(),
+	}
+	reporter.cases = append(reporter.cases, testCase)
+	reporter.current = testCase
+	reporter.group = ""
+	return testCase
+}
+
+// SetGroup 设置之后命令的分组名，如场景的步骤名
+func (reporter *Reporter) SetGroup(group string) {
+	reporter.mu.Lock()
+	defer reporter.mu.Unlock()
+	reporter.group = group
+}
+
+// EndCase 结束当前用例，err不为nil时用例失败，并根据CaptureOnFailure保存截图和页面树
+func (reporter *Reporter) EndCase(err error) {
+	reporter.mu.Lock()
+	testCase := reporter.current
+	session := reporter.session
+	capture := err != nil && reporter.CaptureOnFailure && session != nil && testCase != nil
+	if capture {
+		reporter.capturing = true
+	}
+	reporter.mu.Unlock()
+
+	var attachments []Attachment
+	if capture {
+		attachments = captureFailure(session)
+	}
+
+	reporter.mu.Lock()
+	defer reporter.mu.Unlock()
+	reporter.capturing = false
+	if reporter.current == testCase && testCase != nil {
+		testCase.Attachments = append(testCase.Attachments, attachments...)
+		reporter.finishCase(err)
+	}
+}
+
+// Run 执行一个测试用例，fn返回错误或者panic时用例失败
+func (reporter *Reporter) Run(name string, fn func() error) (err error) {
+	reporter.StartCase(name)
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+		reporter.EndCase(err)
+	}()
+	return fn()
+}
+
+// AddAttachment 向当前用例添加附件
+func (reporter *Reporter) AddAttachment(attachment Attachment) {
+	reporter.mu.Lock()
+	defer reporter.mu.Unlock()
+	if reporter.current != nil {
+		reporter.current.Attachments = append(reporter.current.Attachments, attachment)
+	}
+}
+
+// Cases 获取全部用例
+func (reporter *Reporter) Cases() []*TestCase {
+	reporter.mu.Lock()
+	defer reporter.mu.Unlock()
+	return append([]*TestCase(nil), reporter.cases...)
+}
+
+// Failures 失败的用例数
+func (reporter *Reporter) Failures() int {
+	failures := 0
+	for _, testCase := range reporter.Cases() {
+		if testCase.Failed() {
+			failures++
+		}
+	}
+	return failures
+}
+
+func (testCase *TestCase) Failed() bool {
+	return testCase.Failure != ""
+}
+
+func (reporter *Reporter) finishCase(err error) {
+	testCase := reporter.current
+	testCase.Duration = time.Since(testCase.Started)
+	if err != nil {
+		testCase.Failure = strings.TrimSpace(err.Error())
+	}
+	reporter.current = nil
+	reporter.group = ""
+}
+
+// record 作为CommandObserver记录每条wda命令，截图过程中的命令不记录
+func (reporter *Reporter) record(record WdaGo.CommandRecord) {
+	reporter.mu.Lock()
+	defer reporter.mu.Unlock()
+
+	if reporter.current == nil || reporter.capturing {
+		return
+	}
+
+	endpoint := normalizeEndpoint(record.Url)
+	step := Step{
+		Name:       record.Method + " " + endpoint,
+		Group:      reporter.group,
+		Method:     record.Method,
+		Endpoint:   endpoint,
+		Args:       truncate(string(record.Payload), maxArgsLength),
+		Started:    record.Started,
+		Duration:   record.Duration,
+		StatusCode: record.StatusCode,
+		Passed:     record.Err == nil,
+	}
+	if record.Err != nil {
+		step.Error = strings.TrimSpace(record.Err.Error())
+	}
+	if value := gjson.GetBytes(record.Response, "value"); value.Get("error").Exists() {
+		step.WdaError = value.Get("error").String() + ": " + value.Get("message").String()
+		step.Passed = false
+	}
+	reporter.current.Steps = append(reporter.current.Steps, step)
+}
+
+// captureFailure 保存失败时的截图和页面树，获取失败时忽略
+func captureFailure(session *WdaGo.WdaSession) []Attachment {
+	var attachments []Attachment
+	if data, err := session.ScreenShotData(); err == nil {
+		attachments = append(attachments, Attachment{
+			Name:        "screenshot.png",
+			ContentType: "image/png",
+			Data:        data,
+		})
+	}
+	if source, err := session.GetSource(WdaGo.SourceFormatXml); err == nil {
+		attachments = append(attachments, Attachment{
+			Name:        "source.xml",
+			ContentType: "application/xml",
+			Data:        []byte(source),
+		})
+	}
+	return attachments
+}
+
+// normalizeEndpoint 去掉wda地址，将sessionId和元素id替换为占位符，便于阅读和统计
+func normalizeEndpoint(rawUrl string) string {
+	path := rawUrl
+	if parsed, err := url.Parse(rawUrl); err == nil {
+		path = parsed.Path
+	}
+	path = sessionPathPattern.ReplaceAllString(path, "/session/{sessionId}")
+	return elementPathPattern.ReplaceAllString(path, "/element/{elementId}")
+}
+
+func truncate(value string, length int) string {
+	if len(value) <= length {
+		return value
+	}
+	return value[:length] + "..."
+}
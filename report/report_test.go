@@ -0,0 +1,226 @@
+package report
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	WdaGo "github.com/Ning9527fff/WdaGo"
+)
+
+const (
+	testSessionId  = "test-session"
+	testScreenshot = "png data"
+	testSource     = `<XCUIElementTypeApplication name="Demo"/>`
+)
+
+// testWda 模拟wda，查找元素返回no such element，截图和页面树返回固定内容
+func testWda(w http.ResponseWriter, r *http.Request) {
+	value := "null"
+	path := strings.TrimPrefix(r.URL.Path, "/session/"+testSessionId)
+	switch path {
+	case "/elements":
+		w.WriteHeader(http.StatusNotFound)
+		value = `{"error":"no such element","message":"unable to find <login>"}`
+	case "/screenshot":
+		value = `"` + base64.StdEncoding.EncodeToString([]byte(testScreenshot)) + `"`
+	case "/source":
+		data, _ := json.Marshal(testSource)
+		value = string(data)
+	}
+	w.Header().Set("Content-Type", WdaGo.ContentTypeJson)
+	w.Write([]byte(`{"value":` + value + `,"sessionId":"` + testSessionId + `"}`))
+}
+
+// newTestReporter 执行一个通过、一个wda命令失败和一个panic的用例
+func newTestReporter(t *testing.T) *Reporter {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(testWda))
+	t.Cleanup(server.Close)
+	session := WdaGo.GetWdaSession(server.URL)
+	session.AttachSession(testSessionId)
+
+	reporter := NewReporter("smoke")
+	reporter.Attach(session)
+	reporter.Run("tap", func() error {
+		return session.TapWithLocation(WdaGo.ElementLocation{X: 1, Y: 2})
+	})
+	reporter.Run("login", func() error {
+		reporter.SetGroup("find login")
+		_, err := session.FindElements(WdaGo.StrategyAccessibilityId, "login")
+		return err
+	})
+	reporter.Run("panic", func() error {
+		panic("boom")
+	})
+	return reporter
+}
+
+func TestReporterCapturesFailure(t *testing.T) {
+	reporter := newTestReporter(t)
+
+	cases := reporter.Cases()
+	if len(cases) != 3 || reporter.Failures() != 2 {
+		t.Fatalf("got %d cases with %d failures, want 3 with 2", len(cases), reporter.Failures())
+	}
+	passed, failed, panicked := cases[0], cases[1], cases[2]
+	if passed.Failed() || len(passed.Attachments) != 0 || len(passed.Steps) != 1 || !passed.Steps[0].Passed {
+		t.Errorf("passed case = %+v", passed)
+	}
+	if panicked.Failure != "panic: boom" {
+		t.Errorf("panic case failure = %q", panicked.Failure)
+	}
+
+	// 失败时截图和获取页面树的命令不记录为步骤
+	if len(failed.Steps) != 1 {
+		t.Fatalf("failed case steps = %+v, want only the failed command", failed.Steps)
+	}
+	step := failed.Steps[0]
+	if step.Passed || step.Group != "find login" || step.Endpoint != "/session/{sessionId}/elements" ||
+		step.StatusCode != http.StatusNotFound || step.WdaError != "no such element: unable to find <login>" {
+		t.Errorf("failed step = %+v", step)
+	}
+	want := map[string]string{"screenshot.png": testScreenshot, "source.xml": testSource}
+	if len(failed.Attachments) != len(want) {
+		t.Fatalf("attachments = %+v, want screenshot and source", failed.Attachments)
+	}
+	for _, attachment := range failed.Attachments {
+		if string(attachment.Data) != want[attachment.Name] {
+			t.Errorf("attachment %s = %q, want %q", attachment.Name, attachment.Data, want[attachment.Name])
+		}
+	}
+}
+
+func TestReporterWithoutCapture(t *testing.T) {
+	reporter := NewReporter("smoke")
+	reporter.CaptureOnFailure = false
+	reporter.Run("failed", func() error { return errors.New(" tap failed ") })
+	reporter.AddAttachment(Attachment{Name: "ignored.txt"})
+
+	cases := reporter.Cases()
+	if len(cases) != 1 || cases[0].Failure != "tap failed" || len(cases[0].Attachments) != 0 {
+		t.Errorf("cases = %+v, want one failed case without attachments", cases)
+	}
+}
+
+func TestWriteJUnit(t *testing.T) {
+	reporter := newTestReporter(t)
+	path := filepath.Join(t.TempDir(), "junit.xml")
+	if err := reporter.WriteJUnit(path); err != nil {
+		t.Fatalf("WriteJUnit() error = %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 整个文件都是合法的xml
+	decoder := xml.NewDecoder(strings.NewReader(string(data)))
+	for {
+		if _, err := decoder.Token(); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("invalid junit xml: %v\n%s", err, data)
+		}
+	}
+
+	var suites junitSuites
+	if err := xml.Unmarshal(data, &suites); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if suites.Tests != 3 || suites.Failures != 2 || len(suites.Suites) != 1 || len(suites.Suites[0].Cases) != 3 {
+		t.Fatalf("suites = %+v, want 3 tests with 2 failures", suites)
+	}
+	testCase := suites.Suites[0].Cases[1]
+	if testCase.Name != "login" || testCase.ClassName != "smoke" || testCase.Failure == nil {
+		t.Fatalf("login case = %+v", testCase)
+	}
+	if !strings.Contains(testCase.Failure.Text, "wda error: no such element: unable to find <login>") {
+		t.Errorf("failure detail = %q, want the failed wda command", testCase.Failure.Text)
+	}
+
+	// 附件写入attachments目录，路径以[[ATTACHMENT|path]]的形式写在system-out中
+	if testCase.SystemOut == nil {
+		t.Fatal("login case without system-out")
+	}
+	var attachments []string
+	for _, line := range strings.Split(testCase.SystemOut.Text, "\n") {
+		if strings.HasPrefix(line, "[[ATTACHMENT|") && strings.HasSuffix(line, "]]") {
+			attachments = append(attachments, strings.TrimSuffix(strings.TrimPrefix(line, "[[ATTACHMENT|"), "]]"))
+		}
+	}
+	if len(attachments) != 2 {
+		t.Fatalf("system-out = %q, want two attachment lines", testCase.SystemOut.Text)
+	}
+	for _, attachment := range attachments {
+		if filepath.Dir(attachment) != filepath.Join(filepath.Dir(path), "attachments") {
+			t.Errorf("attachment %s not in attachments dir", attachment)
+		}
+		content, err := os.ReadFile(attachment)
+		if err != nil {
+			t.Errorf("read attachment: %v", err)
+			continue
+		}
+		want := testSource
+		if strings.HasSuffix(attachment, ".png") {
+			want = testScreenshot
+		}
+		if string(content) != want {
+			t.Errorf("attachment %s = %q, want %q", attachment, content, want)
+		}
+	}
+	if passed := suites.Suites[0].Cases[0]; passed.Failure != nil || strings.Contains(passed.SystemOut.Text, "[[ATTACHMENT|") {
+		t.Errorf("passed case = %+v, want no failure and no attachment", passed)
+	}
+}
+
+func TestWriteHTML(t *testing.T) {
+	reporter := newTestReporter(t)
+
+	// 参数和wda错误可能包含页面中的任意文本，需要转义
+	reporter.StartCase("escape")
+	reporter.record(WdaGo.CommandRecord{
+		Method:     http.MethodPost,
+		Url:        "http://127.0.0.1:8100/session/" + testSessionId + "/wda/keys",
+		Payload:    []byte(`{"value":["<img src=x onerror=alert(1)>"]}`),
+		Response:   []byte(`{"value":{"error":"invalid element state","message":"<script>alert(2)</script>"}}`),
+		StatusCode: http.StatusBadRequest,
+	})
+	reporter.EndCase(errors.New("<b>typing failed</b>"))
+
+	path := filepath.Join(t.TempDir(), "index.html")
+	if err := reporter.WriteHTML(path); err != nil {
+		t.Fatalf("WriteHTML() error = %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	html := string(data)
+
+	for _, raw := range []string{"<img src=x", "<script>alert(2)", "<b>typing failed", "find <login>"} {
+		if strings.Contains(html, raw) {
+			t.Errorf("html contains unescaped %q", raw)
+		}
+	}
+	for _, escaped := range []string{
+		"&lt;img src=x onerror=alert(1)&gt;",
+		"&lt;script&gt;alert(2)&lt;/script&gt;",
+		"&lt;b&gt;typing failed&lt;/b&gt;",
+		"unable to find &lt;login&gt;",
+		"4 tests · 3 failed",
+		"data:image/png;base64," + base64.StdEncoding.EncodeToString([]byte(testScreenshot)),
+	} {
+		if !strings.Contains(html, escaped) {
+			t.Errorf("html does not contain %q", escaped)
+		}
+	}
+}
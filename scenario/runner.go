@@ -0,0 +1,547 @@
+package scenario
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	WdaGo "github.com/Ning9527fff/WdaGo"
+)
+
+// Runner 在WdaSession上执行场景
+//
+//	OutputDir  截图等文件的输出目录，为空时使用当前目录
+//	Variables  额外的变量，优先级高于场景中定义的变量
+//	OnStepStart/OnStepEnd 每个步骤开始和结束时回调，可用于生成报告
+type Runner struct {
+	Session     *WdaGo.WdaSession
+	OutputDir   string
+	Variables   map[string]string
+	OnStepStart func(index int, name string)
+	OnStepEnd   func(result StepResult)
+}
+
+// Result 场景执行结果
+type Result struct {
+	Name     string       `json:"name"`
+	Passed   bool         `json:"passed"`
+	Started  time.Time    `json:"started"`
+	Duration Duration     `json:"duration"`
+	Error    string       `json:"error,omitempty"`
+	Steps    []StepResult `json:"steps"`
+}
+
+// StepResult 步骤执行结果，loop步骤的每次循环结果在Steps中
+type StepResult struct {
+	Index     int          `json:"index"`
+	Name      string       `json:"name"`
+	Action    string       `json:"action"`
+	Passed    bool         `json:"passed"`
+	Attempts  int          `json:"attempts"`
+	Started   time.Time    `json:"started"`
+	Duration  Duration     `json:"duration"`
+	Error     string       `json:"error,omitempty"`
+	Artifacts []string     `json:"artifacts,omitempty"`
+	Steps     []StepResult `json:"steps,omitempty"`
+}
+
+// execution 一次场景执行的状态
+type execution struct {
+	runner   *Runner
+	scenario *Scenario
+	vars     map[string]string
+}
+
+func NewRunner(session *WdaGo.WdaSession) *Runner {
+	return &Runner{Session: session}
+}
+
+// Run 执行场景，步骤失败且未设置continueOnError时停止执行
+func (runner *Runner) Run(ctx context.Context, scenario *Scenario) *Result {
+	exec := &execution{
+		runner:   runner,
+		scenario: scenario,
+		vars:     make(map[string]string),
+	}
+	for key, value := range scenario.Variables {
+		exec.vars[key] = value
+	}
+	for key, value := range runner.Variables {
+		exec.vars[key] = value
+	}
+
+	result := &Result{
+		Name:    scenario.Name,
+		Started: time.Now(),
+	}
+
+	if scenario.BundleId != "" {
+		if err := runner.Session.ActivateApp(scenario.BundleId); err != nil {
+			result.Error = err.Error()
+			result.Duration = Duration(time.Since(result.Started))
+			return result
+		}
+	}
+
+	result.Steps, result.Passed = exec.runSteps(ctx, scenario.Steps)
+	if !result.Passed {
+		for _, step := range result.Steps {
+			if !step.Passed {
+				result.Error = fmt.Sprintf("step %d %s failed: %s", step.Index, step.Name, step.Error)
+				break
+			}
+		}
+	}
+	result.Duration = Duration(time.Since(result.Started))
+	return result
+}
+
+func (exec *execution) runSteps(ctx context.Context, steps []Step) ([]StepResult, bool) {
+	results := make([]StepResult, 0, len(steps))
+	passed := true
+	for i, step := range steps {
+		if ctx.Err() != nil {
+			return results, false
+		}
+
+		result := exec.runStep(ctx, i, step)
+		results = append(results, result)
+		if !result.Passed {
+			passed = false
+			if !step.ContinueOnError {
+				break
+			}
+		}
+	}
+	return results, passed
+}
+
+func (exec *execution) runStep(ctx context.Context, index int, step Step) (result StepResult) {
+	result = StepResult{
+		Index:   index,
+		Name:    exec.expand(step.DisplayName()),
+		Action:  step.Action,
+		Started: time.Now(),
+	}
+	if exec.runner.OnStepStart != nil {
+		exec.runner.OnStepStart(index, result.Name)
+	}
+	defer func() {
+		result.Duration = Duration(time.Since(result.Started))
+		if exec.runner.OnStepEnd != nil {
+			exec.runner.OnStepEnd(result)
+		}
+	}()
+
+	if step.Action == ActionLoop {
+		result.Attempts = 1
+		result.Steps, result.Passed = exec.runLoop(ctx, step)
+		if !result.Passed {
+			result.Error = "loop step failed"
+		}
+		return result
+	}
+
+	retries := exec.scenario.Defaults.Retries
+	if step.Retries != nil {
+		retries = *step.Retries
+	}
+	interval := exec.scenario.Defaults.RetryInterval.Std()
+	if interval <= 0 {
+		interval = DefaultRetryInterval
+	}
+
+	var err error
+	for attempt := 0; attempt <= retries; attempt++ {
+		result.Attempts = attempt + 1
+		var artifacts []string
+		artifacts, err = exec.runWithTimeout(ctx, step)
+		result.Artifacts = append(result.Artifacts, artifacts...)
+		if err == nil {
+			result.Passed = true
+			return result
+		}
+		if attempt < retries {
+			select {
+			case <-ctx.Done():
+				result.Error = ctx.Err().Error()
+				return result
+			case <-time.After(interval):
+			}
+		}
+	}
+	result.Error = strings.TrimSpace(err.Error())
+	return result
+}
+
+func (exec *execution) runLoop(ctx context.Context, step Step) ([]StepResult, bool) {
+	items := step.Items
+	if len(items) == 0 {
+		items = make([]string, step.Times)
+		for i := range items {
+			items[i] = strconv.Itoa(i)
+		}
+	}
+
+	varName := step.Var
+	if varName == "" {
+		varName = "item"
+	}
+
+	var results []StepResult
+	for i, item := range items {
+		exec.vars[varName] = exec.expand(item)
+		exec.vars["index"] = strconv.Itoa(i)
+
+		iteration := StepResult{
+			Index:   i,
+			Name:    fmt.Sprintf("%s=%s", varName, exec.vars[varName]),
+			Action:  ActionLoop,
+			Started: time.Now(),
+		}
+		iteration.Steps, iteration.Passed = exec.runSteps(ctx, step.Steps)
+		iteration.Attempts = 1
+		iteration.Duration = Duration(time.Since(iteration.Started))
+		results = append(results, iteration)
+		if !iteration.Passed {
+			return results, false
+		}
+	}
+	return results, true
+}
+
+// runWithTimeout 在步骤超时时间内执行步骤，步骤在当前协程中执行，等待元素时检查超时，
+// 超时后等待正在发送的wda请求结束再返回，因此不会与重试或下一个步骤同时操作设备
+// sleep步骤只有显式设置timeout时才受超时限制
+func (exec *execution) runWithTimeout(ctx context.Context, step Step) ([]string, error) {
+	timeout := step.Timeout.Std()
+	if timeout <= 0 && step.Action != ActionSleep {
+		timeout = exec.scenario.Defaults.Timeout.Std()
+		if timeout <= 0 {
+			timeout = DefaultStepTimeout
+		}
+	}
+
+	stepCtx, cancel := ctx, context.CancelFunc(func() {})
+	if timeout > 0 {
+		stepCtx, cancel = context.WithTimeout(ctx, timeout)
+	}
+	defer cancel()
+
+	artifacts, err := exec.execute(stepCtx, step)
+	if err != nil && errors.Is(stepCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil {
+		return artifacts, fmt.Errorf("step timeout after %v: %v", timeout, strings.TrimSpace(err.Error()))
+	}
+	if err != nil && ctx.Err() != nil {
+		return artifacts, ctx.Err()
+	}
+	return artifacts, err
+}
+
+// execute 执行单个步骤，ctx的截止时间用于等待元素出现
+func (exec *execution) execute(ctx context.Context, step Step) ([]string, error) {
+	session := exec.runner.Session
+
+	switch step.Action {
+	case ActionLaunch:
+		return nil, session.LaunchAppWithOption(WdaGo.AppLaunchOption{
+			BundleId:    exec.expand(step.BundleId),
+			Arguments:   exec.expandList(step.Arguments),
+			Environment: exec.expandMap(step.Environment),
+		})
+	case ActionTerminate:
+		return nil, session.TerminateApp(exec.expand(step.BundleId))
+	case ActionActivate:
+		return nil, session.ActivateApp(exec.expand(step.BundleId))
+	case ActionHome:
+		return nil, session.BackToHomePage()
+	case ActionTap, ActionDoubleTap, ActionLongPress:
+		return nil, exec.tap(ctx, step)
+	case ActionType:
+		elementId, err := exec.waitElement(ctx, step)
+		if err != nil {
+			return nil, err
+		}
+		return nil, session.TypingText(elementId, exec.expand(step.Text))
+	case ActionKeys:
+		return nil, session.SendKeys(exec.expand(step.Text))
+	case ActionClear:
+		elementId, err := exec.waitElement(ctx, step)
+		if err != nil {
+			return nil, err
+		}
+		return nil, session.ClearText(elementId)
+	case ActionSwipe:
+		return nil, session.SwipeWithLocation(step.From[0], step.From[1], step.To[0], step.To[1], step.Duration.Std().Seconds())
+	case ActionWaitFor:
+		_, err := exec.waitElement(ctx, step)
+		return nil, err
+	case ActionWaitGone:
+		locator := exec.locator(step)
+		return nil, WdaGo.WaitUntilContext(ctx, WdaGo.DefaultPollInterval, func() (bool, error) {
+			elements, err := session.FindElements(locator.Using, locator.Value)
+			return len(elements) <= locator.Index, err
+		})
+	case ActionAssertText, ActionAssertAttribute:
+		return nil, exec.assertValue(ctx, step)
+	case ActionAssertVisible:
+		return nil, exec.assertVisible(ctx, step)
+	case ActionScreenshot:
+		path, err := exec.screenshot(step)
+		if err != nil {
+			return nil, err
+		}
+		return []string{path}, nil
+	case ActionAlert:
+		return nil, exec.alert(ctx, step)
+	case ActionSleep:
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(step.Duration.Std()):
+			return nil, nil
+		}
+	case ActionSet:
+		for key, value := range step.Vars {
+			exec.vars[key] = exec.expand(value)
+		}
+		return nil, nil
+	case ActionStore:
+		value, err := exec.readValue(ctx, step, true)
+		if err != nil {
+			return nil, err
+		}
+		exec.vars[step.Var] = value
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unknown action %q", step.Action)
+	}
+}
+
+func (exec *execution) tap(ctx context.Context, step Step) error {
+	session := exec.runner.Session
+
+	var point WdaGo.ElementLocation
+	if step.Locator != nil {
+		elementId, err := exec.waitElement(ctx, step)
+		if err != nil {
+			return err
+		}
+		if step.Action == ActionTap {
+			return session.ClickElement(elementId)
+		}
+		rect, err := session.GetElementRect(elementId)
+		if err != nil {
+			return err
+		}
+		point = rect.Center()
+	} else {
+		point = WdaGo.ElementLocation{X: step.Point[0], Y: step.Point[1]}
+	}
+
+	switch step.Action {
+	case ActionDoubleTap:
+		return session.DoubleTapWithLocation(point.X, point.Y)
+	case ActionLongPress:
+		duration := step.Duration.Std().Seconds()
+		if duration <= 0 {
+			duration = 1
+		}
+		return session.TouchAndHoldWithLocation(point.X, point.Y, duration)
+	default:
+		return session.TapWithLocation(point)
+	}
+}
+
+func (exec *execution) assertValue(ctx context.Context, step Step) error {
+	expected := exec.expand(step.Expected)
+
+	var actual string
+	err := WdaGo.WaitUntilContext(ctx, WdaGo.DefaultPollInterval, func() (bool, error) {
+		value, err := exec.readValue(ctx, step, false)
+		if err != nil {
+			// 元素暂时不存在时继续等待
+			return false, nil
+		}
+		actual = value
+		return matchValue(step.Match, expected, actual)
+	})
+	if err != nil {
+		what := "text"
+		if step.Action == ActionAssertAttribute {
+			what = "attribute " + step.Attribute
+		}
+		return fmt.Errorf("assert %s failed, expected %s %q, actual %q", what, matchName(step.Match), expected, actual)
+	}
+	return nil
+}
+
+func (exec *execution) assertVisible(ctx context.Context, step Step) error {
+	session := exec.runner.Session
+	locator := exec.locator(step)
+
+	err := WdaGo.WaitUntilContext(ctx, WdaGo.DefaultPollInterval, func() (bool, error) {
+		elementId, err := session.FindElement(locator)
+		if err != nil {
+			return false, nil
+		}
+		return session.IsElementDisplayed(elementId)
+	})
+	if err != nil {
+		return fmt.Errorf("assert visible failed, element %v is not visible", locator)
+	}
+	return nil
+}
+
+// readValue 读取元素的文本或属性，wait为false时不等待元素出现
+func (exec *execution) readValue(ctx context.Context, step Step, wait bool) (string, error) {
+	session := exec.runner.Session
+
+	var elementId string
+	var err error
+	if wait {
+		elementId, err = exec.waitElement(ctx, step)
+	} else {
+		elementId, err = session.FindElement(exec.locator(step))
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if step.Attribute != "" {
+		return session.GetElementAttribute(elementId, exec.expand(step.Attribute))
+	}
+	return session.GetElementText(elementId)
+}
+
+func (exec *execution) screenshot(step Step) (string, error) {
+	name := exec.expand(step.Path)
+	if name == "" {
+		name = fmt.Sprintf("screenshot_%d.png", time.Now().UnixNano())
+	}
+	path := name
+	if !filepath.IsAbs(path) && exec.runner.OutputDir != "" {
+		path = filepath.Join(exec.runner.OutputDir, path)
+	}
+
+	data, err := exec.runner.Session.ScreenShotData()
+	if err != nil {
+		return "", err
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", err
+	}
+	return path, os.WriteFile(path, data, 0644)
+}
+
+// alert 等待弹窗出现后处理，button为accept、dismiss或者按钮名，为空时accept
+func (exec *execution) alert(ctx context.Context, step Step) error {
+	session := exec.runner.Session
+
+	err := WdaGo.WaitUntilContext(ctx, WdaGo.DefaultPollInterval, func() (bool, error) {
+		_, err := session.AlertGet()
+		return err == nil, nil
+	})
+	if err != nil {
+		return fmt.Errorf("no alert shown :%v", err)
+	}
+
+	switch button := exec.expand(step.Button); button {
+	case "", "accept":
+		return session.AlertAccept()
+	case "dismiss":
+		return session.AlertDismiss()
+	default:
+		return session.AlertAccept(button)
+	}
+}
+
+// waitElement 等待元素出现，直到ctx超时
+func (exec *execution) waitElement(ctx context.Context, step Step) (string, error) {
+	session := exec.runner.Session
+	locator := exec.locator(step)
+
+	var elementId string
+	err := WdaGo.WaitUntilContext(ctx, WdaGo.DefaultPollInterval, func() (bool, error) {
+		elements, err := session.FindElements(locator.Using, locator.Value)
+		if err != nil {
+			return false, err
+		}
+		if len(elements) > locator.Index {
+			elementId = elements[locator.Index]
+			return true, nil
+		}
+		return false, nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("wait for element %v failed: %v", locator, err)
+	}
+	return elementId, nil
+}
+
+func (exec *execution) locator(step Step) WdaGo.Locator {
+	return WdaGo.Locator{
+		Using: exec.expand(step.Locator.Using),
+		Value: exec.expand(step.Locator.Value),
+		Index: step.Locator.Index,
+	}
+}
+
+// expand 替换${name}变量，场景中未定义的变量从环境变量中读取
+func (exec *execution) expand(value string) string {
+	return os.Expand(value, func(key string) string {
+		if v, ok := exec.vars[key]; ok {
+			return v
+		}
+		return os.Getenv(key)
+	})
+}
+
+func (exec *execution) expandList(values []string) []string {
+	result := make([]string, len(values))
+	for i, value := range values {
+		result[i] = exec.expand(value)
+	}
+	return result
+}
+
+func (exec *execution) expandMap(values map[string]string) map[string]string {
+	if values == nil {
+		return nil
+	}
+	result := make(map[string]string, len(values))
+	for key, value := range values {
+		result[key] = exec.expand(value)
+	}
+	return result
+}
+
+func matchValue(match, expected, actual string) (bool, error) {
+	switch match {
+	case "", MatchEquals:
+		return actual == expected, nil
+	case MatchContains:
+		return strings.Contains(actual, expected), nil
+	case MatchRegex:
+		re, err := regexp.Compile(expected)
+		if err != nil {
+			return false, err
+		}
+		return re.MatchString(actual), nil
+	default:
+		return false, fmt.Errorf("unknown match %q", match)
+	}
+}
+
+func matchName(match string) string {
+	if match == "" {
+		return MatchEquals
+	}
+	return match
+}
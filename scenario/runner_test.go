@@ -0,0 +1,256 @@
+package scenario
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	WdaGo "github.com/Ning9527fff/WdaGo"
+)
+
+const testSessionId = "test-session"
+
+// fakeWda 模拟wda的元素查找、文本读取、点击和输入
+//
+//	elements 查找值到元素id的映射，不存在的值返回空列表
+//	texts    元素id到文本的映射
+type fakeWda struct {
+	mu       sync.Mutex
+	elements map[string]string
+	texts    map[string]string
+	clicks   []string
+	keys     []string
+}
+
+func (fake *fakeWda) session(t *testing.T) *WdaGo.WdaSession {
+	t.Helper()
+	mux := http.NewServeMux()
+	prefix := "/session/" + testSessionId
+	mux.HandleFunc("POST "+prefix+"/elements", func(w http.ResponseWriter, r *http.Request) {
+		var request WdaGo.ElementSearchRequest
+		json.NewDecoder(r.Body).Decode(&request)
+		fake.mu.Lock()
+		id, ok := fake.elements[request.Value]
+		fake.mu.Unlock()
+		if !ok {
+			writeValue(w, `[]`)
+			return
+		}
+		writeValue(w, `[{"ELEMENT":"`+id+`"}]`)
+	})
+	mux.HandleFunc("GET "+prefix+"/element/{id}/text", func(w http.ResponseWriter, r *http.Request) {
+		fake.mu.Lock()
+		text := fake.texts[r.PathValue("id")]
+		fake.mu.Unlock()
+		data, _ := json.Marshal(text)
+		writeValue(w, string(data))
+	})
+	mux.HandleFunc("POST "+prefix+"/element/{id}/click", func(w http.ResponseWriter, r *http.Request) {
+		fake.mu.Lock()
+		fake.clicks = append(fake.clicks, r.PathValue("id"))
+		fake.mu.Unlock()
+		writeValue(w, `null`)
+	})
+	mux.HandleFunc("POST "+prefix+"/wda/keys", func(w http.ResponseWriter, r *http.Request) {
+		var request WdaGo.TypingRequest
+		json.NewDecoder(r.Body).Decode(&request)
+		fake.mu.Lock()
+		fake.keys = append(fake.keys, strings.Join(request.Value, ""))
+		fake.mu.Unlock()
+		writeValue(w, `null`)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	session := WdaGo.GetWdaSession(server.URL)
+	session.AttachSession(testSessionId)
+	return session
+}
+
+func writeValue(w http.ResponseWriter, value string) {
+	w.Header().Set("Content-Type", WdaGo.ContentTypeJson)
+	w.Write([]byte(`{"value":` + value + `,"sessionId":"` + testSessionId + `"}`))
+}
+
+func mustParse(t *testing.T, data string) *Scenario {
+	t.Helper()
+	scenario, err := Parse([]byte(data))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	return scenario
+}
+
+func TestRunnerVariables(t *testing.T) {
+	t.Setenv("WDAGO_TEST_ENV", "from-env")
+	fake := &fakeWda{
+		elements: map[string]string{"greeting": "e1", "tab-a": "e2", "tab-b": "e3"},
+		texts:    map[string]string{"e1": "Hello Bob"},
+	}
+	scenario := mustParse(t, `
+name: variables
+variables:
+  user: alice
+  prefix: tab
+steps:
+  - action: keys
+    text: ${user}/${WDAGO_TEST_ENV}
+  - action: set
+    vars:
+      greeting: hi ${user}
+  - action: keys
+    text: ${greeting}
+  - action: store
+    locator: {using: accessibility id, value: greeting}
+    var: stored
+  - action: keys
+    text: ${stored}
+  - action: loop
+    items: [a, b]
+    var: tab
+    steps:
+      - action: tap
+        locator: {using: accessibility id, value: "${prefix}-${tab}"}
+      - action: keys
+        text: ${index}
+`)
+
+	runner := NewRunner(fake.session(t))
+	runner.Variables = map[string]string{"user": "bob"}
+	result := runner.Run(context.Background(), scenario)
+	if !result.Passed {
+		t.Fatalf("Run() failed: %s", result.Error)
+	}
+
+	wantKeys := []string{"bob/from-env", "hi bob", "Hello Bob", "0", "1"}
+	if strings.Join(fake.keys, "|") != strings.Join(wantKeys, "|") {
+		t.Errorf("keys = %q, want %q", fake.keys, wantKeys)
+	}
+	if strings.Join(fake.clicks, ",") != "e2,e3" {
+		t.Errorf("clicks = %v, want [e2 e3]", fake.clicks)
+	}
+	if loop := result.Steps[5]; len(loop.Steps) != 2 || loop.Steps[1].Name != "tab=b" {
+		t.Errorf("loop iterations = %+v", loop.Steps)
+	}
+}
+
+func TestRunnerTimeoutAndRetries(t *testing.T) {
+	tests := []struct {
+		name         string
+		scenario     string
+		wantPassed   bool
+		wantAttempts int
+		wantErr      string
+		maxDuration  time.Duration
+	}{
+		{
+			name: "missing element times out on every attempt",
+			scenario: `
+defaults: {timeout: 100ms, retries: 1, retryInterval: 10ms}
+steps:
+  - action: waitFor
+    locator: {using: accessibility id, value: missing}`,
+			wantAttempts: 2,
+			wantErr:      "step timeout after 100ms",
+			maxDuration:  2 * time.Second,
+		},
+		{
+			name: "sleep is not limited by default timeout",
+			scenario: `
+defaults: {timeout: 50ms}
+steps:
+  - action: sleep
+    duration: 200ms`,
+			wantPassed:   true,
+			wantAttempts: 1,
+			maxDuration:  time.Second,
+		},
+		{
+			name: "sleep respects explicit timeout",
+			scenario: `
+steps:
+  - action: sleep
+    duration: 5s
+    timeout: 50ms`,
+			wantAttempts: 1,
+			wantErr:      "step timeout after 50ms",
+			maxDuration:  time.Second,
+		},
+		{
+			name: "assert failure keeps assert message",
+			scenario: `
+defaults: {timeout: 100ms}
+steps:
+  - action: assertText
+    locator: {using: accessibility id, value: greeting}
+    expected: Bye`,
+			wantAttempts: 1,
+			wantErr:      `expected equals "Bye", actual "Hello"`,
+			maxDuration:  2 * time.Second,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeWda{elements: map[string]string{"greeting": "e1"}, texts: map[string]string{"e1": "Hello"}}
+			runner := NewRunner(fake.session(t))
+
+			started := time.Now()
+			result := runner.Run(context.Background(), mustParse(t, tt.scenario))
+			if elapsed := time.Since(started); elapsed > tt.maxDuration {
+				t.Errorf("Run() took %v, want less than %v", elapsed, tt.maxDuration)
+			}
+			if result.Passed != tt.wantPassed {
+				t.Fatalf("Passed = %v, want %v, error %q", result.Passed, tt.wantPassed, result.Error)
+			}
+			step := result.Steps[0]
+			if step.Attempts != tt.wantAttempts {
+				t.Errorf("Attempts = %d, want %d", step.Attempts, tt.wantAttempts)
+			}
+			if !strings.Contains(step.Error, tt.wantErr) {
+				t.Errorf("Error = %q, want containing %q", step.Error, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRunnerContinueOnErrorAndCancel(t *testing.T) {
+	fake := &fakeWda{}
+	scenario := mustParse(t, `
+defaults: {timeout: 50ms}
+steps:
+  - action: waitFor
+    locator: {using: accessibility id, value: missing}
+    continueOnError: true
+  - action: keys
+    text: after
+  - action: waitFor
+    locator: {using: accessibility id, value: missing}
+  - action: keys
+    text: never
+`)
+	var started []string
+	runner := NewRunner(fake.session(t))
+	runner.OnStepStart = func(index int, name string) { started = append(started, name) }
+	result := runner.Run(context.Background(), scenario)
+	if result.Passed || len(result.Steps) != 3 {
+		t.Fatalf("Passed = %v with %d steps, want failed after 3 steps", result.Passed, len(result.Steps))
+	}
+	if strings.Join(fake.keys, ",") != "after" || len(started) != 3 {
+		t.Errorf("keys = %v, started = %v", fake.keys, started)
+	}
+	if !strings.HasPrefix(result.Error, "step 0 ") {
+		t.Errorf("Error = %q, want first failed step", result.Error)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	result = runner.Run(ctx, scenario)
+	if result.Passed || len(result.Steps) != 0 {
+		t.Errorf("cancelled Run() passed = %v with %d steps", result.Passed, len(result.Steps))
+	}
+}
@@ -0,0 +1,272 @@
+// Package scenario 以yaml/json描述的测试场景，以及在WdaSession上执行场景的引擎
+//
+// 场景示例：
+//
+//	name: login
+//	variables:
+//	  user: alice
+//	defaults:
+//	  timeout: 10s
+//	  retries: 1
+//	steps:
+//	  - action: launch
+//	    bundleId: com.example.app
+//	  - action: type
+//	    locator: {using: accessibility id, value: username}
+//	    text: ${user}
+//	  - action: tap
+//	    locator: {using: accessibility id, value: login}
+//	  - action: assertText
+//	    locator: {using: accessibility id, value: welcome}
+//	    expected: Hello ${user}
+//	    match: contains
+package scenario
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	WdaGo "github.com/Ning9527fff/WdaGo"
+	"gopkg.in/yaml.v3"
+)
+
+// 场景支持的步骤类型
+const (
+	ActionLaunch          = "launch"
+	ActionTerminate       = "terminate"
+	ActionActivate        = "activate"
+	ActionHome            = "home"
+	ActionTap             = "tap"
+	ActionDoubleTap       = "doubleTap"
+	ActionLongPress       = "longPress"
+	ActionType            = "type"
+	ActionKeys            = "keys"
+	ActionClear           = "clear"
+	ActionSwipe           = "swipe"
+	ActionWaitFor         = "waitFor"
+	ActionWaitGone        = "waitGone"
+	ActionAssertText      = "assertText"
+	ActionAssertAttribute = "assertAttribute"
+	ActionAssertVisible   = "assertVisible"
+	ActionScreenshot      = "screenshot"
+	ActionAlert           = "alert"
+	ActionSleep           = "sleep"
+	ActionSet             = "set"
+	ActionStore           = "store"
+	ActionLoop            = "loop"
+)
+
+// 断言的匹配方式
+const (
+	MatchEquals   = "equals"
+	MatchContains = "contains"
+	MatchRegex    = "regex"
+)
+
+const (
+	DefaultStepTimeout   = 10 * time.Second
+	DefaultRetryInterval = time.Second
+)
+
+// Duration 支持"10s"、"500ms"格式和以秒为单位的数字
+type Duration time.Duration
+
+// Scenario 测试场景
+type Scenario struct {
+	Name      string            `yaml:"name" json:"name"`
+	BundleId  string            `yaml:"bundleId,omitempty" json:"bundleId,omitempty"`
+	Variables map[string]string `yaml:"variables,omitempty" json:"variables,omitempty"`
+	Defaults  StepDefaults      `yaml:"defaults,omitempty" json:"defaults,omitempty"`
+	Steps     []Step            `yaml:"steps" json:"steps"`
+}
+
+// StepDefaults 步骤未指定时使用的默认超时和重试次数
+type StepDefaults struct {
+	Timeout       Duration `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+	Retries       int      `yaml:"retries,omitempty" json:"retries,omitempty"`
+	RetryInterval Duration `yaml:"retryInterval,omitempty" json:"retryInterval,omitempty"`
+}
+
+// Step 场景中的一个步骤，不同Action使用的字段不同：
+//
+//	launch/terminate/activate  bundleId, arguments, environment
+//	tap/doubleTap/longPress    locator或point，longPress使用duration
+//	type/clear                 locator, text
+//	keys                       text
+//	swipe                      from, to, duration
+//	waitFor/waitGone           locator
+//	assertText                 locator, expected, match
+//	assertAttribute            locator, attribute, expected, match
+//	assertVisible              locator
+//	screenshot                 path
+//	alert                      button为accept、dismiss或者按钮名
+//	sleep                      duration，只有设置timeout时才受超时限制
+//	set                        vars
+//	store                      locator, attribute(为空时取文本), var
+//	loop                       times或items, var, steps
+type Step struct {
+	Name            string            `yaml:"name,omitempty" json:"name,omitempty"`
+	Action          string            `yaml:"action" json:"action"`
+	Locator         *WdaGo.Locator    `yaml:"locator,omitempty" json:"locator,omitempty"`
+	Point           []float64         `yaml:"point,omitempty" json:"point,omitempty"`
+	From            []float64         `yaml:"from,omitempty" json:"from,omitempty"`
+	To              []float64         `yaml:"to,omitempty" json:"to,omitempty"`
+	Duration        Duration          `yaml:"duration,omitempty" json:"duration,omitempty"`
+	Text            string            `yaml:"text,omitempty" json:"text,omitempty"`
+	Expected        string            `yaml:"expected,omitempty" json:"expected,omitempty"`
+	Match           string            `yaml:"match,omitempty" json:"match,omitempty"`
+	Attribute       string            `yaml:"attribute,omitempty" json:"attribute,omitempty"`
+	BundleId        string            `yaml:"bundleId,omitempty" json:"bundleId,omitempty"`
+	Arguments       []string          `yaml:"arguments,omitempty" json:"arguments,omitempty"`
+	Environment     map[string]string `yaml:"environment,omitempty" json:"environment,omitempty"`
+	Path            string            `yaml:"path,omitempty" json:"path,omitempty"`
+	Button          string            `yaml:"button,omitempty" json:"button,omitempty"`
+	Vars            map[string]string `yaml:"vars,omitempty" json:"vars,omitempty"`
+	Var             string            `yaml:"var,omitempty" json:"var,omitempty"`
+	Times           int               `yaml:"times,omitempty" json:"times,omitempty"`
+	Items           []string          `yaml:"items,omitempty" json:"items,omitempty"`
+	Steps           []Step            `yaml:"steps,omitempty" json:"steps,omitempty"`
+	Timeout         Duration          `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+	Retries         *int              `yaml:"retries,omitempty" json:"retries,omitempty"`
+	ContinueOnError bool              `yaml:"continueOnError,omitempty" json:"continueOnError,omitempty"`
+}
+
+// Load 读取场景文件，json是yaml的子集，两种格式都使用yaml解析
+func Load(path string) (*Scenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf(" Read scenario file failed :%v", err)
+	}
+	return Parse(data)
+}
+
+// Parse 解析yaml或json格式的场景
+func Parse(data []byte) (*Scenario, error) {
+	var scenario Scenario
+	if err := yaml.Unmarshal(data, &scenario); err != nil {
+		return nil, fmt.Errorf(" Parse scenario failed :%v", err)
+	}
+	if err := scenario.Validate(); err != nil {
+		return nil, err
+	}
+	return &scenario, nil
+}
+
+// Validate 检查步骤的必填字段
+func (scenario *Scenario) Validate() error {
+	if len(scenario.Steps) == 0 {
+		return fmt.Errorf(" Scenario %v has no steps ", scenario.Name)
+	}
+	return validateSteps(scenario.Steps, "steps")
+}
+
+func validateSteps(steps []Step, path string) error {
+	for i, step := range steps {
+		where := fmt.Sprintf("%s[%d](%s)", path, i, step.Action)
+		var err error
+		switch step.Action {
+		case ActionLaunch, ActionTerminate, ActionActivate:
+			if step.BundleId == "" {
+				err = fmt.Errorf("bundleId is required")
+			}
+		case ActionTap, ActionDoubleTap, ActionLongPress:
+			if step.Locator == nil && len(step.Point) != 2 {
+				err = fmt.Errorf("locator or point [x, y] is required")
+			}
+		case ActionType, ActionClear, ActionWaitFor, ActionWaitGone, ActionAssertVisible:
+			if step.Locator == nil {
+				err = fmt.Errorf("locator is required")
+			}
+		case ActionAssertText, ActionAssertAttribute:
+			if step.Locator == nil {
+				err = fmt.Errorf("locator is required")
+			} else if step.Action == ActionAssertAttribute && step.Attribute == "" {
+				err = fmt.Errorf("attribute is required")
+			}
+		case ActionKeys:
+			if step.Text == "" {
+				err = fmt.Errorf("text is required")
+			}
+		case ActionSwipe:
+			if len(step.From) != 2 || len(step.To) != 2 {
+				err = fmt.Errorf("from [x, y] and to [x, y] are required")
+			}
+		case ActionStore:
+			if step.Locator == nil || step.Var == "" {
+				err = fmt.Errorf("locator and var are required")
+			}
+		case ActionLoop:
+			if step.Times <= 0 && len(step.Items) == 0 {
+				err = fmt.Errorf("times or items is required")
+			} else {
+				err = validateSteps(step.Steps, where+".steps")
+				if err != nil {
+					return err
+				}
+			}
+		case ActionHome, ActionScreenshot, ActionAlert, ActionSleep, ActionSet:
+		default:
+			err = fmt.Errorf("unknown action")
+		}
+		if err != nil {
+			return fmt.Errorf(" Invalid scenario %s: %v ", where, err)
+		}
+	}
+	return nil
+}
+
+// DisplayName 步骤名，未指定name时使用action
+func (step Step) DisplayName() string {
+	if step.Name != "" {
+		return step.Name
+	}
+	if step.Locator != nil {
+		return step.Action + " " + step.Locator.String()
+	}
+	return step.Action
+}
+
+func (d Duration) Std() time.Duration {
+	return time.Duration(d)
+}
+
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	return d.parse(strings.Trim(string(data), `"`))
+}
+
+func (d Duration) MarshalYAML() (interface{}, error) {
+	return time.Duration(d).String(), nil
+}
+
+func (d *Duration) UnmarshalYAML(node *yaml.Node) error {
+	return d.parse(node.Value)
+}
+
+func (d *Duration) parse(value string) error {
+	if value == "" {
+		*d = 0
+		return nil
+	}
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		*d = Duration(seconds * float64(time.Second))
+		return nil
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return fmt.Errorf(" Parse duration %q failed :%v", value, err)
+	}
+	*d = Duration(parsed)
+	return nil
+}
@@ -0,0 +1,124 @@
+package scenario
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	yamlScenario := `
+name: login
+bundleId: com.example.app
+variables:
+  user: alice
+defaults:
+  timeout: 5s
+  retries: 2
+  retryInterval: 0.5
+steps:
+  - action: type
+    locator: {using: accessibility id, value: username}
+    text: ${user}
+  - name: submit
+    action: tap
+    point: [10, 20]
+    retries: 0
+  - action: loop
+    items: [a, b]
+    var: tab
+    steps:
+      - action: sleep
+        duration: 100ms
+`
+	jsonScenario := `{"name":"login","bundleId":"com.example.app","variables":{"user":"alice"},
+		"defaults":{"timeout":"5s","retries":2,"retryInterval":"500ms"},
+		"steps":[
+			{"action":"type","locator":{"using":"accessibility id","value":"username"},"text":"${user}"},
+			{"name":"submit","action":"tap","point":[10,20],"retries":0},
+			{"action":"loop","items":["a","b"],"var":"tab","steps":[{"action":"sleep","duration":0.1}]}]}`
+
+	for name, data := range map[string]string{"yaml": yamlScenario, "json": jsonScenario} {
+		t.Run(name, func(t *testing.T) {
+			scenario, err := Parse([]byte(data))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if scenario.Name != "login" || scenario.BundleId != "com.example.app" || scenario.Variables["user"] != "alice" {
+				t.Errorf("scenario header = %+v", scenario)
+			}
+			if scenario.Defaults.Timeout.Std() != 5*time.Second || scenario.Defaults.Retries != 2 ||
+				scenario.Defaults.RetryInterval.Std() != 500*time.Millisecond {
+				t.Errorf("defaults = %+v", scenario.Defaults)
+			}
+			if len(scenario.Steps) != 3 {
+				t.Fatalf("got %d steps, want 3", len(scenario.Steps))
+			}
+			if locator := scenario.Steps[0].Locator; locator == nil || locator.Using != "accessibility id" || locator.Value != "username" {
+				t.Errorf("step 0 locator = %v", locator)
+			}
+			if step := scenario.Steps[1]; step.Retries == nil || *step.Retries != 0 || step.DisplayName() != "submit" {
+				t.Errorf("step 1 = %+v", step)
+			}
+			if loop := scenario.Steps[2]; len(loop.Items) != 2 || loop.Steps[0].Duration.Std() != 100*time.Millisecond {
+				t.Errorf("loop step = %+v", loop)
+			}
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{"no steps", `name: empty`, "has no steps"},
+		{"unknown action", `steps: [{action: fly}]`, "unknown action"},
+		{"launch without bundle", `steps: [{action: launch}]`, "bundleId is required"},
+		{"tap without target", `steps: [{action: tap}]`, "locator or point"},
+		{"assert attribute without name", `steps: [{action: assertAttribute, locator: {using: id, value: a}}]`, "attribute is required"},
+		{"swipe without points", `steps: [{action: swipe, from: [1, 2]}]`, "from [x, y] and to [x, y]"},
+		{"store without var", `steps: [{action: store, locator: {using: id, value: a}}]`, "locator and var"},
+		{"invalid nested step", `steps: [{action: loop, times: 2, steps: [{action: type}]}]`, "steps[0](loop).steps[0](type)"},
+		{"loop without count", `steps: [{action: loop, steps: [{action: home}]}]`, "times or items"},
+		{"invalid duration", `steps: [{action: sleep, duration: soon}]`, "Parse duration"},
+		{"invalid yaml", `steps: [`, "Parse scenario failed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Parse() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDuration(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{`"1.5s"`, 1500 * time.Millisecond},
+		{`"250ms"`, 250 * time.Millisecond},
+		{`2`, 2 * time.Second},
+		{`"0.25"`, 250 * time.Millisecond},
+		{`""`, 0},
+	}
+	for _, tt := range tests {
+		var d Duration
+		if err := json.Unmarshal([]byte(tt.value), &d); err != nil {
+			t.Fatalf("Unmarshal(%s) error = %v", tt.value, err)
+		}
+		if d.Std() != tt.want {
+			t.Errorf("Unmarshal(%s) = %v, want %v", tt.value, d, tt.want)
+		}
+	}
+
+	data, err := json.Marshal(Duration(90 * time.Second))
+	if err != nil || string(data) != `"1m30s"` {
+		t.Errorf("Marshal() = %s, %v", data, err)
+	}
+}
@@ -0,0 +1,196 @@
+package WdaGo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	log "github.com/Ning9527fff/MyLog"
+	"github.com/tidwall/gjson"
+)
+
+// Settings wda appium settings，字段为nil表示不修改，具体含义参考WebDriverAgent的FBSettings
+type Settings struct {
+	SnapshotMaxDepth             *int     `json:"snapshotMaxDepth,omitempty"`
+	CustomSnapshotTimeout        *float64 `json:"customSnapshotTimeout,omitempty"`
+	UseFirstMatch                *bool    `json:"useFirstMatch,omitempty"`
+	BoundElementsByIndex         *bool    `json:"boundElementsByIndex,omitempty"`
+	ReduceMotion                 *bool    `json:"reduceMotion,omitempty"`
+	DefaultActiveApplication     *string  `json:"defaultActiveApplication,omitempty"`
+	ActiveAppDetectionPoint      *string  `json:"activeAppDetectionPoint,omitempty"`
+	KeyboardAutocorrection       *bool    `json:"keyboardAutocorrection,omitempty"`
+	KeyboardPrediction           *bool    `json:"keyboardPrediction,omitempty"`
+	MjpegServerFramerate         *int     `json:"mjpegServerFramerate,omitempty"`
+	MjpegServerScreenshotQuality *int     `json:"mjpegServerScreenshotQuality,omitempty"`
+	MjpegScalingFactor           *float64 `json:"mjpegScalingFactor,omitempty"`
+	MjpegFixOrientation          *bool    `json:"mjpegFixOrientation,omitempty"`
+	ScreenshotQuality            *int     `json:"screenshotQuality,omitempty"`
+	ShouldUseCompactResponses    *bool    `json:"shouldUseCompactResponses,omitempty"`
+	ElementResponseAttributes    *string  `json:"elementResponseAttributes,omitempty"`
+	IncludeNonModalElements      *bool    `json:"includeNonModalElements,omitempty"`
+	AcceptAlertButtonSelector    *string  `json:"acceptAlertButtonSelector,omitempty"`
+	DismissAlertButtonSelector   *string  `json:"dismissAlertButtonSelector,omitempty"`
+	DefaultAlertAction           *string  `json:"defaultAlertAction,omitempty"`
+	AutoClickAlertSelector       *string  `json:"autoClickAlertSelector,omitempty"`
+	AnimationCoolOffTimeout      *float64 `json:"animationCoolOffTimeout,omitempty"`
+	WaitForIdleTimeout           *float64 `json:"waitForIdleTimeout,omitempty"`
+	PageSourceExcludedAttributes *string  `json:"pageSourceExcludedAttributes,omitempty"`
+	MaxTypingFrequency           *int     `json:"maxTypingFrequency,omitempty"`
+	RespectSystemAlerts          *bool    `json:"respectSystemAlerts,omitempty"`
+	UseClearTextShortcut         *bool    `json:"useClearTextShortcut,omitempty"`
+	LimitXPathContextScope       *bool    `json:"limitXPathContextScope,omitempty"`
+}
+
+// Ptr 返回v的指针，用于构造Settings
+//
+//	Settings{SnapshotMaxDepth: Ptr(30), UseFirstMatch: Ptr(true)}
+func Ptr[T any](v T) *T {
+	return &v
+}
+
+// GetSettings 获取当前session的全部设置
+func (session *WdaSession) GetSettings() (*Settings, error) {
+	session, end := session.begin("WdaSession.GetSettings")
+	defer end()
+
+	body, err := session.getSettingsBody()
+	if err != nil {
+		return nil, err
+	}
+
+	var settings Settings
+	if err = json.Unmarshal([]byte(gjson.Get(string(body), "value").Raw), &settings); err != nil {
+		return nil, fmt.Errorf(" Parse settings failed :%w", err)
+	}
+	return &settings, nil
+}
+
+// GetSettingsMap 以map形式获取当前session的全部设置，包含Settings中未定义的设置项
+func (session *WdaSession) GetSettingsMap() (map[string]interface{}, error) {
+	session, end := session.begin("WdaSession.GetSettingsMap")
+	defer end()
+
+	body, err := session.getSettingsBody()
+	if err != nil {
+		return nil, err
+	}
+	return GetDataFromRespBody(body)
+}
+
+// UpdateSettings 修改设置，只修改非nil的字段，返回修改后的全部设置
+func (session *WdaSession) UpdateSettings(settings Settings) (*Settings, error) {
+	session, end := session.begin("WdaSession.UpdateSettings")
+	defer end()
+
+	body, err := session.postSettings(settings)
+	if err != nil {
+		return nil, err
+	}
+
+	var updated Settings
+	if err = json.Unmarshal([]byte(gjson.Get(string(body), "value").Raw), &updated); err != nil {
+		return nil, fmt.Errorf(" Parse settings failed :%w", err)
+	}
+	return &updated, nil
+}
+
+// UpdateSettingsMap 以map形式修改设置，用于Settings中未定义的设置项
+func (session *WdaSession) UpdateSettingsMap(settings map[string]interface{}) (map[string]interface{}, error) {
+	session, end := session.begin("WdaSession.UpdateSettingsMap")
+	defer end()
+
+	body, err := session.postSettings(settings)
+	if err != nil {
+		return nil, err
+	}
+	return GetDataFromRespBody(body)
+}
+
+// WithSettings 临时应用settings执行fn，执行完成后将修改过的设置项恢复为原来的值
+func (session *WdaSession) WithSettings(settings Settings, fn func() error) error {
+	session, end := session.begin("WdaSession.WithSettings")
+	defer end()
+
+	changed, err := settingsToMap(settings)
+	if err != nil {
+		return err
+	}
+	return session.WithSettingsMap(changed, fn)
+}
+
+// WithSettingsMap 同WithSettings，以map形式传入设置
+// fn返回错误或者panic时同样恢复设置，恢复失败的错误与fn的错误合并返回
+func (session *WdaSession) WithSettingsMap(settings map[string]interface{}, fn func() error) (err error) {
+	session, end := session.begin("WdaSession.WithSettingsMap")
+	defer end()
+
+	previous, err := session.GetSettingsMap()
+	if err != nil {
+		return fmt.Errorf(" Save previous settings failed :%w", err)
+	}
+
+	restore := make(map[string]interface{}, len(settings))
+	for key := range settings {
+		if value, ok := previous[key]; ok {
+			restore[key] = value
+		}
+	}
+
+	if _, err = session.UpdateSettingsMap(settings); err != nil {
+		return err
+	}
+
+	defer func() {
+		if len(restore) == 0 {
+			return
+		}
+		if _, restoreErr := session.UpdateSettingsMap(restore); restoreErr != nil {
+			err = errors.Join(err, fmt.Errorf(" Restore settings failed :%w", restoreErr))
+		}
+	}()
+
+	return fn()
+}
+
+// SetMjpegSettings 设置mjpeg视频流的帧率、图片质量和缩放比例
+func (session *WdaSession) SetMjpegSettings(settings MjpegSettings) error {
+	session, end := session.begin("WdaSession.SetMjpegSettings")
+	defer end()
+
+	_, err := session.postSettings(settings)
+	return err
+}
+
+func (session *WdaSession) getSettingsBody() ([]byte, error) {
+	api := session.url + "/session/" + session.SessionId() + "/appium/settings"
+
+	body, err := session.get(api)
+	if err != nil {
+		return nil, fmt.Errorf(" Get settings failed from api :%w", err)
+	}
+	return body, nil
+}
+
+func (session *WdaSession) postSettings(settings interface{}) ([]byte, error) {
+	api := session.url + "/session/" + session.SessionId() + "/appium/settings"
+
+	body, err := session.post(api, SettingsRequest{Settings: settings})
+	if err != nil {
+		return nil, fmt.Errorf(" Update settings failed from api :%w", err)
+	}
+	log.DebugF("response body is %v ", string(body))
+	return body, nil
+}
+
+func settingsToMap(settings Settings) (map[string]interface{}, error) {
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return nil, fmt.Errorf(" Format settings failed :%w", err)
+	}
+
+	result := make(map[string]interface{})
+	if err = json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf(" Format settings failed :%w", err)
+	}
+	return result, nil
+}
@@ -0,0 +1,109 @@
+package WdaGo
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeSettings 模拟wda的 /appium/settings 接口，failUpdates为true时修改设置返回500
+type fakeSettings struct {
+	mu          sync.Mutex
+	values      map[string]interface{}
+	failUpdates bool
+}
+
+func (fake *fakeSettings) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	if r.Method == http.MethodPost {
+		if fake.failUpdates {
+			w.WriteHeader(http.StatusInternalServerError)
+			writeValue(w, `{"error":"unknown error"}`)
+			return
+		}
+		var request struct {
+			Settings map[string]interface{} `json:"settings"`
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+		for key, value := range request.Settings {
+			fake.values[key] = value
+		}
+	}
+	data, _ := json.Marshal(fake.values)
+	writeValue(w, string(data))
+}
+
+func (fake *fakeSettings) get(key string) interface{} {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	return fake.values[key]
+}
+
+func TestWithSettingsMap(t *testing.T) {
+	fnErr := errors.New("fn failed")
+	tests := []struct {
+		name        string
+		fn          func(fake *fakeSettings) error
+		wantErr     []error
+		wantPanic   bool
+		wantRestore bool
+	}{
+		{"success", func(*fakeSettings) error { return nil }, nil, false, true},
+		{"fn error", func(*fakeSettings) error { return fnErr }, []error{fnErr}, false, true},
+		{"fn panic", func(*fakeSettings) error { panic("boom") }, nil, true, true},
+		{"restore error joined with fn error", func(fake *fakeSettings) error {
+			fake.mu.Lock()
+			fake.failUpdates = true
+			fake.mu.Unlock()
+			return fnErr
+		}, []error{fnErr}, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeSettings{values: map[string]interface{}{"snapshotMaxDepth": 50.0, "useFirstMatch": false}}
+			mux := http.NewServeMux()
+			mux.Handle("/session/"+testSessionId+"/appium/settings", fake)
+			session := newTestSession(t, mux)
+
+			var err error
+			panicked := func() (panicked bool) {
+				defer func() { panicked = recover() != nil }()
+				err = session.WithSettingsMap(map[string]interface{}{"snapshotMaxDepth": 10}, func() error {
+					if got := fake.get("snapshotMaxDepth"); got != 10.0 {
+						t.Errorf("snapshotMaxDepth inside fn = %v, want 10", got)
+					}
+					return tt.fn(fake)
+				})
+				return false
+			}()
+
+			if panicked != tt.wantPanic {
+				t.Fatalf("panicked = %v, want %v", panicked, tt.wantPanic)
+			}
+			for _, want := range tt.wantErr {
+				if !errors.Is(err, want) {
+					t.Errorf("err = %v, want %v", err, want)
+				}
+			}
+			if tt.wantErr == nil && !tt.wantPanic && err != nil {
+				t.Errorf("err = %v, want nil", err)
+			}
+
+			restored := fake.get("snapshotMaxDepth") == 50.0
+			if restored != tt.wantRestore {
+				t.Errorf("snapshotMaxDepth after = %v, restored %v, want %v", fake.get("snapshotMaxDepth"), restored, tt.wantRestore)
+			}
+			if !tt.wantRestore && (err == nil || !strings.Contains(err.Error(), "Restore settings failed")) {
+				t.Errorf("err = %v, want restore error", err)
+			}
+			if got := fake.get("useFirstMatch"); got != false {
+				t.Errorf("untouched setting useFirstMatch = %v, want false", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package WdaGo
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SourceNode 页面树中的一个元素，对应/source返回的xml中的一个节点
+//
+//	Path  节点在树中的位置，如 0.2.1 表示根节点第3个子节点的第2个子节点
+//	Attributes 节点的全部原始属性
+type SourceNode struct {
+	Type       string            `json:"type"`
+	Name       string            `json:"name,omitempty"`
+	Label      string            `json:"label,omitempty"`
+	Value      string            `json:"value,omitempty"`
+	Enabled    bool              `json:"enabled"`
+	Visible    bool              `json:"visible"`
+	Accessible bool              `json:"accessible"`
+	Rect       ElementRect       `json:"rect"`
+	Path       string            `json:"path"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+	Children   []*SourceNode     `json:"children,omitempty"`
+	Parent     *SourceNode       `json:"-"`
+}
+
+type sourceXmlNode struct {
+	XMLName  xml.Name
+	Attrs    []xml.Attr      `xml:",any,attr"`
+	Children []sourceXmlNode `xml:",any"`
+}
+
+// GetSourceTree 获取当前页面树并解析为SourceNode
+func (session *WdaSession) GetSourceTree() (*SourceNode, error) {
+	session, end := session.begin("WdaSession.GetSourceTree")
+	defer end()
+
+	source, err := session.GetSource(SourceFormatXml)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSource(source)
+}
+
+// ParseSource 解析/source接口返回的xml页面树
+func ParseSource(source string) (*SourceNode, error) {
+	var root sourceXmlNode
+	if err := xml.Unmarshal([]byte(source), &root); err != nil {
+		return nil, fmt.Errorf(" Parse page source failed :%w", err)
+	}
+	if root.XMLName.Local == "AppiumAUT" && len(root.Children) > 0 {
+		root = root.Children[0]
+	}
+	return newSourceNode(root, nil, "0"), nil
+}
+
+func newSourceNode(raw sourceXmlNode, parent *SourceNode, path string) *SourceNode {
+	node := &SourceNode{
+		Type:       raw.XMLName.Local,
+		Path:       path,
+		Parent:     parent,
+		Attributes: make(map[string]string, len(raw.Attrs)),
+	}
+	for _, attr := range raw.Attrs {
+		node.Attributes[attr.Name.Local] = attr.Value
+	}
+
+	if t := node.Attributes["type"]; t != "" {
+		node.Type = t
+	}
+	node.Name = node.Attributes["name"]
+	node.Label = node.Attributes["label"]
+	node.Value = node.Attributes["value"]
+	node.Enabled = node.Attributes["enabled"] == "true"
+	node.Visible = node.Attributes["visible"] == "true"
+	node.Accessible = node.Attributes["accessible"] == "true"
+	node.Rect = ElementRect{
+		X:      parseSourceFloat(node.Attributes["x"]),
+		Y:      parseSourceFloat(node.Attributes["y"]),
+		Width:  parseSourceFloat(node.Attributes["width"]),
+		Height: parseSourceFloat(node.Attributes["height"]),
+	}
+
+	for i, child := range raw.Children {
+		node.Children = append(node.Children, newSourceNode(child, node, path+"."+strconv.Itoa(i)))
+	}
+	return node
+}
+
+func parseSourceFloat(value string) float64 {
+	f, _ := strconv.ParseFloat(value, 64)
+	return f
+}
+
+// Walk 深度优先遍历节点及其子节点，fn返回false时不再遍历该节点的子节点
+func (node *SourceNode) Walk(fn func(node *SourceNode) bool) {
+	if !fn(node) {
+		return
+	}
+	for _, child := range node.Children {
+		child.Walk(fn)
+	}
+}
+
+// Find 查找第一个满足条件的节点
+func (node *SourceNode) Find(match func(node *SourceNode) bool) *SourceNode {
+	var found *SourceNode
+	node.Walk(func(n *SourceNode) bool {
+		if found != nil {
+			return false
+		}
+		if match(n) {
+			found = n
+			return false
+		}
+		return true
+	})
+	return found
+}
+
+// FindAll 查找全部满足条件的节点
+func (node *SourceNode) FindAll(match func(node *SourceNode) bool) []*SourceNode {
+	var nodes []*SourceNode
+	node.Walk(func(n *SourceNode) bool {
+		if match(n) {
+			nodes = append(nodes, n)
+		}
+		return true
+	})
+	return nodes
+}
+
+// FindByPath 根据Path查找节点
+func (node *SourceNode) FindByPath(path string) *SourceNode {
+	if node.Path == path {
+		return node
+	}
+	if !strings.HasPrefix(path, node.Path+".") {
+		return nil
+	}
+	for _, child := range node.Children {
+		if found := child.FindByPath(path); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// ElementAt 返回包含该点的最内层可见节点，没有时返回nil
+func (node *SourceNode) ElementAt(x, y float64) *SourceNode {
+	if !node.Rect.Contains(x, y) {
+		return nil
+	}
+	// 后面的兄弟节点在上层，倒序查找
+	for i := len(node.Children) - 1; i >= 0; i-- {
+		child := node.Children[i]
+		if !child.Visible {
+			continue
+		}
+		if found := child.ElementAt(x, y); found != nil {
+			return found
+		}
+	}
+	return node
+}
+
+// Title 用于展示的节点描述，如 XCUIElementTypeButton "登录"
+func (node *SourceNode) Title() string {
+	text := node.Name
+	if text == "" {
+		text = node.Label
+	}
+	if text == "" {
+		return node.Type
+	}
+	return fmt.Sprintf("%s %q", node.Type, text)
+}
@@ -0,0 +1,329 @@
+package WdaGo
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/Ning9527fff/MyLog"
+)
+
+// DefaultTelemetryInterval 默认采样间隔
+const DefaultTelemetryInterval = 30 * time.Second
+
+// 采样项，获取失败的项记录在TelemetrySample.Failed中
+const (
+	TelemetryBattery   = "battery"
+	TelemetryDevice    = "device"
+	TelemetryActiveApp = "activeApp"
+	TelemetryLocked    = "locked"
+)
+
+// TelemetrySample 一次采样的设备状态，获取失败的项保留零值，项名记录在Failed中，原因记录在Errors中
+//
+//	BatteryLevel、BatteryState 对应battery项，BatteryLevel为0-1的电量，无法获取时为-1
+//	ThermalState               对应device项，wda没有返回温度状态时为ThermalStateUnknown，device项记为失败
+//	ActiveApp、ActivePid       对应activeApp项，ActiveApp为前台app的bundleId
+//	Locked                     对应locked项
+type TelemetrySample struct {
+	Time         time.Time    `json:"time"`
+	BatteryLevel float64      `json:"batteryLevel"`
+	BatteryState BatteryState `json:"batteryState"`
+	ThermalState ThermalState `json:"thermalState"`
+	ActiveApp    string       `json:"activeApp"`
+	ActivePid    int          `json:"activePid"`
+	Locked       bool         `json:"locked"`
+	Failed       []string     `json:"failed,omitempty"`
+	Errors       []string     `json:"errors,omitempty"`
+}
+
+// Valid items中的采样项是否都获取成功，items为空时检查全部采样项
+func (sample TelemetrySample) Valid(items ...string) bool {
+	if len(items) == 0 {
+		return len(sample.Failed) == 0
+	}
+	for _, item := range items {
+		for _, failed := range sample.Failed {
+			if item == failed {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// TelemetryThreshold 采样阈值，Condition由不满足变为满足时调用OnTrigger，恢复时调用OnRecover
+//
+// Requires为Condition使用的采样项，其中有获取失败的项时不检查该阈值，阈值保持原来的状态；
+// Requires为空时任何一项获取失败都不检查
+//
+//	TelemetryThreshold{Name: "hot", Condition: ThermalAtLeast(ThermalStateSerious), Requires: []string{TelemetryDevice}}
+type TelemetryThreshold struct {
+	Name      string
+	Condition func(sample TelemetrySample) bool
+	Requires  []string
+	OnTrigger func(sample TelemetrySample)
+	OnRecover func(sample TelemetrySample)
+}
+
+// TelemetryOption 采样参数
+//
+//	Interval   采样间隔
+//	MaxSamples 最多保留的采样数，超过时丢弃最早的采样，0为不限制
+//	Thresholds 每次采样后检查的阈值
+//	OnSample   每次采样后的回调
+type TelemetryOption struct {
+	Interval   time.Duration
+	MaxSamples int
+	Thresholds []TelemetryThreshold
+	OnSample   func(sample TelemetrySample)
+}
+
+// TelemetrySampler 后台定期采样电量、温度、前台app和锁屏状态
+type TelemetrySampler struct {
+	session *WdaSession
+	option  TelemetryOption
+
+	mu      sync.Mutex
+	samples []TelemetrySample
+	active  map[string]bool
+	changed chan struct{}
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+// ThermalAtLeast 温度状态达到state，如 ThermalAtLeast(ThermalStateSerious)，温度状态未知时不满足，依赖TelemetryDevice项
+func ThermalAtLeast(state ThermalState) func(sample TelemetrySample) bool {
+	return func(sample TelemetrySample) bool {
+		return sample.ThermalState != ThermalStateUnknown && sample.ThermalState >= state
+	}
+}
+
+// BatteryBelow 电量低于level，无法获取电量时不满足，依赖TelemetryBattery项
+func BatteryBelow(level float64) func(sample TelemetrySample) bool {
+	return func(sample TelemetrySample) bool {
+		return sample.BatteryLevel >= 0 && sample.BatteryLevel < level
+	}
+}
+
+// StartTelemetry 启动采样，立即采样一次，调用Stop停止
+func (session *WdaSession) StartTelemetry(option TelemetryOption) *TelemetrySampler {
+	if option.Interval <= 0 {
+		option.Interval = DefaultTelemetryInterval
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sampler := &TelemetrySampler{
+		session: session,
+		option:  option,
+		active:  make(map[string]bool),
+		changed: make(chan struct{}),
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+	go sampler.run(ctx)
+	return sampler
+}
+
+// Stop 停止采样，等待正在进行的采样结束
+func (sampler *TelemetrySampler) Stop() {
+	sampler.cancel()
+	<-sampler.done
+}
+
+// Samples 已采集的数据
+func (sampler *TelemetrySampler) Samples() []TelemetrySample {
+	sampler.mu.Lock()
+	defer sampler.mu.Unlock()
+	return append([]TelemetrySample(nil), sampler.samples...)
+}
+
+// Latest 最近一次采样，没有采样时返回false
+func (sampler *TelemetrySampler) Latest() (TelemetrySample, bool) {
+	sampler.mu.Lock()
+	defer sampler.mu.Unlock()
+	if len(sampler.samples) == 0 {
+		return TelemetrySample{}, false
+	}
+	return sampler.samples[len(sampler.samples)-1], true
+}
+
+// Triggered 当前处于触发状态的阈值名
+func (sampler *TelemetrySampler) Triggered() []string {
+	sampler.mu.Lock()
+	defer sampler.mu.Unlock()
+	var names []string
+	for _, threshold := range sampler.option.Thresholds {
+		if sampler.active[threshold.Name] {
+			names = append(names, threshold.Name)
+		}
+	}
+	return names
+}
+
+// WaitClear 等待所有阈值恢复，用于在温度过高时暂停测试，ctx结束时返回错误
+func (sampler *TelemetrySampler) WaitClear(ctx context.Context) error {
+	for {
+		sampler.mu.Lock()
+		active, changed := len(sampler.active), sampler.changed
+		sampler.mu.Unlock()
+
+		if active == 0 {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf(" Wait for telemetry thresholds clear failed, triggered %v :%v", sampler.Triggered(), ctx.Err())
+		case <-changed:
+		}
+	}
+}
+
+// WriteJSON 将采样数据输出为json数组
+func (sampler *TelemetrySampler) WriteJSON(w io.Writer) error {
+	samples := sampler.Samples()
+	if samples == nil {
+		samples = []TelemetrySample{}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(samples); err != nil {
+		return fmt.Errorf(" Write telemetry json failed :%w", err)
+	}
+	return nil
+}
+
+// WriteCSV 将采样数据输出为csv，枚举值使用名称
+func (sampler *TelemetrySampler) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"time", "batteryLevel", "batteryState", "thermalState", "activeApp", "activePid", "locked", "failed", "errors"})
+	for _, sample := range sampler.Samples() {
+		writer.Write([]string{
+			sample.Time.Format(time.RFC3339Nano),
+			strconv.FormatFloat(sample.BatteryLevel, 'f', -1, 64),
+			sample.BatteryState.String(),
+			sample.ThermalState.String(),
+			sample.ActiveApp,
+			strconv.Itoa(sample.ActivePid),
+			strconv.FormatBool(sample.Locked),
+			strings.Join(sample.Failed, ";"),
+			strings.Join(sample.Errors, "; "),
+		})
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf(" Write telemetry csv failed :%w", err)
+	}
+	return nil
+}
+
+func (sampler *TelemetrySampler) run(ctx context.Context) {
+	defer close(sampler.done)
+
+	ticker := time.NewTicker(sampler.option.Interval)
+	defer ticker.Stop()
+
+	for {
+		sampler.add(sampler.sample())
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sample 采集一次设备状态
+func (sampler *TelemetrySampler) sample() TelemetrySample {
+	session := sampler.session
+	sample := TelemetrySample{Time: time.Now(), BatteryLevel: -1}
+	fail := func(item string, err error) {
+		sample.Failed = append(sample.Failed, item)
+		sample.Errors = append(sample.Errors, item+": "+strings.TrimSpace(err.Error()))
+	}
+
+	if battery, err := session.GetBatteryInfo(); err != nil {
+		fail(TelemetryBattery, err)
+	} else {
+		sample.BatteryLevel = battery.Level
+		sample.BatteryState = battery.State
+	}
+	if info, err := session.GetDeviceInfo(); err != nil {
+		fail(TelemetryDevice, err)
+	} else if info.ThermalState == ThermalStateUnknown {
+		// 没有温度状态时不能当作nominal，依赖该项的阈值保持原来的状态
+		sample.ThermalState = ThermalStateUnknown
+		fail(TelemetryDevice, fmt.Errorf(" No thermal state in device info "))
+	} else {
+		sample.ThermalState = info.ThermalState
+	}
+	if app, err := session.GetActiveAppInfo(); err != nil {
+		fail(TelemetryActiveApp, err)
+	} else {
+		sample.ActiveApp = app.Value.BundleId
+		sample.ActivePid = app.Value.Pid
+	}
+	if locked, err := session.IsLocked(); err != nil {
+		fail(TelemetryLocked, err)
+	} else {
+		sample.Locked = locked
+	}
+
+	if len(sample.Errors) > 0 {
+		log.DebugF("Telemetry sample failed: %v", sample.Errors)
+	}
+	return sample
+}
+
+// add 保存采样并检查阈值，回调在锁外执行
+func (sampler *TelemetrySampler) add(sample TelemetrySample) {
+	var triggered, recovered []TelemetryThreshold
+
+	sampler.mu.Lock()
+	sampler.samples = append(sampler.samples, sample)
+	if max := sampler.option.MaxSamples; max > 0 && len(sampler.samples) > max {
+		sampler.samples = append(sampler.samples[:0:0], sampler.samples[len(sampler.samples)-max:]...)
+	}
+	for _, threshold := range sampler.option.Thresholds {
+		// 采样失败的项为零值，不用于判断依赖该项的阈值，阈值保持原来的状态
+		if threshold.Condition == nil || !sample.Valid(threshold.Requires...) {
+			continue
+		}
+		matched := threshold.Condition(sample)
+		switch {
+		case matched && !sampler.active[threshold.Name]:
+			sampler.active[threshold.Name] = true
+			triggered = append(triggered, threshold)
+		case !matched && sampler.active[threshold.Name]:
+			delete(sampler.active, threshold.Name)
+			recovered = append(recovered, threshold)
+		}
+	}
+	if len(triggered) > 0 || len(recovered) > 0 {
+		close(sampler.changed)
+		sampler.changed = make(chan struct{})
+	}
+	sampler.mu.Unlock()
+
+	for _, threshold := range triggered {
+		log.DebugF("Telemetry threshold %v triggered", threshold.Name)
+		if threshold.OnTrigger != nil {
+			threshold.OnTrigger(sample)
+		}
+	}
+	for _, threshold := range recovered {
+		log.DebugF("Telemetry threshold %v recovered", threshold.Name)
+		if threshold.OnRecover != nil {
+			threshold.OnRecover(sample)
+		}
+	}
+	if sampler.option.OnSample != nil {
+		sampler.option.OnSample(sample)
+	}
+}
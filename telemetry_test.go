@@ -0,0 +1,207 @@
+package WdaGo
+
+import (
+	"bytes"
+	"encoding/csv"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTelemetryThresholds(t *testing.T) {
+	type step struct {
+		sample TelemetrySample
+		// active 采样后处于触发状态的阈值
+		active []string
+	}
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "trigger and recover",
+			steps: []step{
+				{TelemetrySample{ThermalState: ThermalStateSerious, BatteryLevel: 0.5}, []string{"hot"}},
+				{TelemetrySample{ThermalState: ThermalStateCritical, BatteryLevel: 0.1}, []string{"hot", "battery"}},
+				{TelemetrySample{ThermalState: ThermalStateFair, BatteryLevel: 0.1}, []string{"battery"}},
+			},
+		},
+		{
+			name: "failed battery does not block thermal threshold",
+			steps: []step{
+				{TelemetrySample{ThermalState: ThermalStateSerious, BatteryLevel: -1, Failed: []string{TelemetryBattery}}, []string{"hot"}},
+				{TelemetrySample{ThermalState: ThermalStateNominal, BatteryLevel: -1, Failed: []string{TelemetryBattery, TelemetryLocked}}, nil},
+			},
+		},
+		{
+			name: "failed item keeps threshold state",
+			steps: []step{
+				{TelemetrySample{ThermalState: ThermalStateSerious, BatteryLevel: 0.1}, []string{"hot", "battery"}},
+				// 获取失败的项为零值，依赖该项的阈值不能因此恢复
+				{TelemetrySample{BatteryLevel: -1, Failed: []string{TelemetryBattery, TelemetryDevice}}, []string{"hot", "battery"}},
+				{TelemetrySample{BatteryLevel: 0.9, Failed: []string{TelemetryDevice}}, []string{"hot"}},
+			},
+		},
+		{
+			name: "threshold without requires skipped on any failure",
+			steps: []step{
+				{TelemetrySample{ThermalState: ThermalStateSerious, BatteryLevel: 0.5, Locked: true}, []string{"hot", "locked"}},
+				{TelemetrySample{ThermalState: ThermalStateSerious, BatteryLevel: 0.5, Failed: []string{TelemetryActiveApp}}, []string{"hot", "locked"}},
+				{TelemetrySample{ThermalState: ThermalStateSerious, BatteryLevel: 0.5}, []string{"hot"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var events []string
+			record := func(event string) func(TelemetrySample) {
+				return func(TelemetrySample) { events = append(events, event) }
+			}
+			sampler := &TelemetrySampler{
+				option: TelemetryOption{Thresholds: []TelemetryThreshold{
+					{Name: "hot", Condition: ThermalAtLeast(ThermalStateSerious), Requires: []string{TelemetryDevice},
+						OnTrigger: record("hot triggered"), OnRecover: record("hot recovered")},
+					{Name: "battery", Condition: BatteryBelow(0.2), Requires: []string{TelemetryBattery}},
+					{Name: "locked", Condition: func(sample TelemetrySample) bool { return sample.Locked }},
+				}},
+				active:  make(map[string]bool),
+				changed: make(chan struct{}),
+			}
+			for i, step := range tt.steps {
+				sampler.add(step.sample)
+				got := sampler.Triggered()
+				if len(got) != len(step.active) {
+					t.Fatalf("step %d triggered = %v, want %v", i, got, step.active)
+				}
+				for j := range got {
+					if got[j] != step.active[j] {
+						t.Fatalf("step %d triggered = %v, want %v", i, got, step.active)
+					}
+				}
+			}
+			if len(events) > 2 {
+				t.Errorf("callbacks = %v, want at most one trigger and one recover", events)
+			}
+		})
+	}
+}
+
+func TestTelemetrySampleValid(t *testing.T) {
+	sample := TelemetrySample{Failed: []string{TelemetryBattery}}
+	tests := []struct {
+		items []string
+		want  bool
+	}{
+		{nil, false},
+		{[]string{TelemetryDevice}, true},
+		{[]string{TelemetryDevice, TelemetryBattery}, false},
+	}
+	for _, tt := range tests {
+		if got := sample.Valid(tt.items...); got != tt.want {
+			t.Errorf("Valid(%v) = %v, want %v", tt.items, got, tt.want)
+		}
+	}
+	if !(TelemetrySample{}).Valid() {
+		t.Error("sample without failures should be valid")
+	}
+}
+
+func TestTelemetrySampler(t *testing.T) {
+	mux := http.NewServeMux()
+	prefix := "/session/" + testSessionId
+	mux.HandleFunc(prefix+"/wda/batteryInfo", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		writeValue(w, `{"error":"unknown error","message":"battery unavailable"}`)
+	})
+	mux.HandleFunc(prefix+"/wda/device/info", func(w http.ResponseWriter, r *http.Request) {
+		writeValue(w, `{"thermalState":"serious"}`)
+	})
+	mux.HandleFunc(prefix+"/wda/activeAppInfo", func(w http.ResponseWriter, r *http.Request) {
+		writeValue(w, `{"bundleId":"com.demo","pid":42,"name":"","processArguments":{}}`)
+	})
+	mux.HandleFunc(prefix+"/wda/locked", func(w http.ResponseWriter, r *http.Request) {
+		writeValue(w, `false`)
+	})
+	session := newTestSession(t, mux)
+
+	var mu sync.Mutex
+	triggered := 0
+	sampled := make(chan TelemetrySample, 10)
+	sampler := session.StartTelemetry(TelemetryOption{
+		Interval: time.Hour,
+		Thresholds: []TelemetryThreshold{{
+			Name:      "hot",
+			Condition: ThermalAtLeast(ThermalStateSerious),
+			Requires:  []string{TelemetryDevice},
+			OnTrigger: func(TelemetrySample) {
+				mu.Lock()
+				defer mu.Unlock()
+				triggered++
+			},
+		}},
+		OnSample: func(sample TelemetrySample) { sampled <- sample },
+	})
+	sample := <-sampled
+	sampler.Stop()
+
+	if len(sample.Failed) != 1 || sample.Failed[0] != TelemetryBattery || len(sample.Errors) != 1 {
+		t.Fatalf("sample failed = %v errors = %v, want only battery", sample.Failed, sample.Errors)
+	}
+	if sample.BatteryLevel != -1 || sample.ThermalState != ThermalStateSerious || sample.ActiveApp != "com.demo" || sample.ActivePid != 42 {
+		t.Errorf("sample = %+v", sample)
+	}
+	mu.Lock()
+	if triggered != 1 {
+		t.Errorf("thermal threshold triggered %d times with battery failure, want 1", triggered)
+	}
+	mu.Unlock()
+
+	var buf bytes.Buffer
+	if err := sampler.WriteCSV(&buf); err != nil {
+		t.Fatalf("WriteCSV() error = %v", err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil || len(rows) != 2 {
+		t.Fatalf("csv rows = %v, %v", rows, err)
+	}
+	if rows[0][7] != "failed" || rows[1][7] != TelemetryBattery || rows[1][3] != "serious" {
+		t.Errorf("csv = %v", rows)
+	}
+}
+
+func TestTelemetrySamplerUnknownThermalState(t *testing.T) {
+	session := newTestSession(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/wda/device/info"):
+			writeValue(w, `{"model":"iPhone"}`)
+		case strings.HasSuffix(r.URL.Path, "/wda/batteryInfo"):
+			writeValue(w, `{"level":0.5,"state":2}`)
+		default:
+			writeValue(w, `false`)
+		}
+	}))
+
+	sampler := &TelemetrySampler{
+		session: session,
+		option: TelemetryOption{Thresholds: []TelemetryThreshold{
+			{Name: "hot", Condition: ThermalAtLeast(ThermalStateNominal), Requires: []string{TelemetryDevice}},
+		}},
+		active:  map[string]bool{"hot": true},
+		changed: make(chan struct{}),
+	}
+	sample := sampler.sample()
+	if sample.ThermalState != ThermalStateUnknown || sample.Valid(TelemetryDevice) || !sample.Valid(TelemetryBattery) {
+		t.Fatalf("sample = %+v, want unknown thermal state and failed device item", sample)
+	}
+
+	// 温度状态未知时阈值保持原来的状态，不会因为读到零值而恢复或者触发
+	sampler.add(sample)
+	if got := sampler.Triggered(); len(got) != 1 || got[0] != "hot" {
+		t.Errorf("Triggered() = %v, want [hot]", got)
+	}
+	if ThermalAtLeast(ThermalStateNominal)(TelemetrySample{ThermalState: ThermalStateUnknown}) {
+		t.Error("ThermalAtLeast() matched unknown thermal state")
+	}
+}
@@ -0,0 +1,443 @@
+package WdaGo
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"sort"
+)
+
+const (
+	DefaultMatchThreshold = 0.9
+	DefaultMaxMatches     = 1
+	// coarseTemplateSide 粗匹配时模板短边缩小到的像素数
+	coarseTemplateSide = 12
+	maxCandidates      = 32
+)
+
+// TemplateMatchOption 模板匹配参数
+//
+//	Threshold  匹配置信度阈值，取值0~1，使用归一化互相关(NCC)计算
+//	Scales     模板的缩放比例列表，用于匹配不同分辨率下截取的模板，默认只匹配原尺寸
+//	MaxMatches 最多返回的匹配个数
+//	Downsample 粗匹配阶段的缩小倍数，0表示根据模板大小自动计算
+type TemplateMatchOption struct {
+	Threshold  float64
+	Scales     []float64
+	MaxMatches int
+	Downsample int
+}
+
+// TemplateMatch 模板匹配结果，Rect为截图中的像素区域，Center为换算后的点坐标
+type TemplateMatch struct {
+	Rect   image.Rectangle
+	Score  float64
+	Scale  float64
+	Center ElementLocation
+}
+
+// grayImage 灰度图，用于计算互相关
+type grayImage struct {
+	width  int
+	height int
+	pix    []float64
+}
+
+// integralImage 积分图，用于快速计算窗口内的像素和与平方和
+type integralImage struct {
+	width int
+	sum   []float64
+	sqSum []float64
+}
+
+func DefaultTemplateMatchOption() TemplateMatchOption {
+	return TemplateMatchOption{
+		Threshold:  DefaultMatchThreshold,
+		Scales:     []float64{1.0},
+		MaxMatches: DefaultMaxMatches,
+	}
+}
+
+// FindImage 在当前页面截图中查找模板图片，返回的Center已按屏幕scale换算为点坐标，
+// 可直接用于TapWithLocation等坐标操作
+func (session *WdaSession) FindImage(template image.Image, option TemplateMatchOption) ([]TemplateMatch, error) {
+	session, end := session.begin("WdaSession.FindImage")
+	defer end()
+
+	screen, err := session.ScreenShotImage()
+	if err != nil {
+		return nil, err
+	}
+
+	scrSize, err := session.GetScreenSize()
+	if err != nil {
+		return nil, err
+	}
+
+	scale := float64(scrSize.Value.Scale)
+	if scale <= 0 {
+		scale = 1
+	}
+
+	matches, err := MatchTemplate(screen, template, option)
+	if err != nil {
+		return nil, err
+	}
+
+	origin := screen.Bounds().Min
+	for i := range matches {
+		matches[i].Center = PixelToPoint(matches[i].Rect.Sub(origin), scale)
+	}
+	return matches, nil
+}
+
+// TapImage 查找模板图片并点击置信度最高的位置
+func (session *WdaSession) TapImage(template image.Image, option TemplateMatchOption) (*TemplateMatch, error) {
+	session, end := session.begin("WdaSession.TapImage")
+	defer end()
+
+	match, err := session.findBestImage(template, option)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = session.TapWithLocation(match.Center); err != nil {
+		return match, err
+	}
+	return match, nil
+}
+
+// TouchAndHoldImage 查找模板图片并在置信度最高的位置长按指定时间(秒)
+func (session *WdaSession) TouchAndHoldImage(template image.Image, duration float64, option TemplateMatchOption) (*TemplateMatch, error) {
+	session, end := session.begin("WdaSession.TouchAndHoldImage")
+	defer end()
+
+	match, err := session.findBestImage(template, option)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = session.TouchAndHoldWithLocation(match.Center.X, match.Center.Y, duration); err != nil {
+		return match, err
+	}
+	return match, nil
+}
+
+func (session *WdaSession) findBestImage(template image.Image, option TemplateMatchOption) (*TemplateMatch, error) {
+	option.MaxMatches = 1
+	matches, err := session.FindImage(template, option)
+	if err != nil {
+		return nil, err
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf(" Image not found in current screen ")
+	}
+	return &matches[0], nil
+}
+
+// PixelToPoint 将截图中的像素区域换算为屏幕点坐标的中心点
+func PixelToPoint(rect image.Rectangle, scale float64) ElementLocation {
+	return ElementLocation{
+		X: (float64(rect.Min.X) + float64(rect.Dx())/2) / scale,
+		Y: (float64(rect.Min.Y) + float64(rect.Dy())/2) / scale,
+	}
+}
+
+// MatchTemplate 在screen中查找template，支持多尺度和多个匹配结果，结果按置信度从高到低排序
+// 先在缩小后的图片上粗匹配得到候选位置，再在原图上对候选位置附近精确匹配
+func MatchTemplate(screen, template image.Image, option TemplateMatchOption) ([]TemplateMatch, error) {
+	if option.Threshold <= 0 {
+		option.Threshold = DefaultMatchThreshold
+	}
+	if option.MaxMatches <= 0 {
+		option.MaxMatches = DefaultMaxMatches
+	}
+	if len(option.Scales) == 0 {
+		option.Scales = []float64{1.0}
+	}
+
+	screenGray := toGrayImage(screen)
+	templateGray := toGrayImage(template)
+	origin := screen.Bounds().Min
+
+	var matches []TemplateMatch
+	for _, scale := range option.Scales {
+		if scale <= 0 {
+			return nil, fmt.Errorf(" Template scale must be positive, got %v ", scale)
+		}
+
+		tpl := templateGray
+		if scale != 1.0 {
+			tpl = templateGray.resize(
+				int(math.Round(float64(templateGray.width)*scale)),
+				int(math.Round(float64(templateGray.height)*scale)))
+		}
+		if tpl.width < 2 || tpl.height < 2 ||
+			tpl.width > screenGray.width || tpl.height > screenGray.height {
+			continue
+		}
+
+		found, err := matchSingleScale(screenGray, tpl, option)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range found {
+			m.Scale = scale
+			m.Rect = m.Rect.Add(origin)
+			matches = append(matches, m)
+		}
+	}
+
+	return suppressOverlaps(matches, option.MaxMatches), nil
+}
+
+func matchSingleScale(screen, tpl *grayImage, option TemplateMatchOption) ([]TemplateMatch, error) {
+	down := option.Downsample
+	if down <= 0 {
+		down = min(tpl.width, tpl.height) / coarseTemplateSide
+	}
+	if down < 1 {
+		down = 1
+	}
+
+	// 粗匹配
+	coarseScreen, coarseTpl := screen, tpl
+	if down > 1 {
+		coarseScreen = screen.shrink(down)
+		coarseTpl = tpl.shrink(down)
+	}
+
+	// 缩小后模板与截图的像素网格不一定对齐，粗匹配分数会偏低，
+	// 因此粗匹配只按分数取前若干个候选位置，由精确匹配判断阈值
+	coarseThreshold := option.Threshold
+	if down > 1 {
+		coarseThreshold = 0
+	}
+
+	candidates, err := nccCandidates(coarseScreen, coarseTpl, coarseThreshold, max(maxCandidates, option.MaxMatches))
+	if err != nil {
+		return nil, err
+	}
+	if down == 1 {
+		var result []TemplateMatch
+		for _, c := range candidates {
+			if c.Score >= option.Threshold {
+				result = append(result, c)
+			}
+		}
+		return result, nil
+	}
+
+	// 在原图上精确匹配
+	integral := newIntegralImage(screen)
+	tplZero, tplNorm := tpl.zeroMean()
+	if tplNorm*tplNorm <= 1e-9 {
+		return nil, fmt.Errorf(" Template image has no contrast, can not match ")
+	}
+
+	var result []TemplateMatch
+	for _, c := range candidates {
+		bestScore, bestX, bestY := -1.0, 0, 0
+		cx, cy := c.Rect.Min.X*down, c.Rect.Min.Y*down
+		for y := max(cy-down, 0); y <= min(cy+down, screen.height-tpl.height); y++ {
+			for x := max(cx-down, 0); x <= min(cx+down, screen.width-tpl.width); x++ {
+				score := nccAt(screen, integral, tplZero, tplNorm, tpl.width, tpl.height, x, y)
+				if score > bestScore {
+					bestScore, bestX, bestY = score, x, y
+				}
+			}
+		}
+		if bestScore >= option.Threshold {
+			result = append(result, TemplateMatch{
+				Rect:  image.Rect(bestX, bestY, bestX+tpl.width, bestY+tpl.height),
+				Score: bestScore,
+			})
+		}
+	}
+	return result, nil
+}
+
+// nccCandidates 计算所有位置的NCC，返回互不重叠且超过阈值的候选位置，最多limit个
+func nccCandidates(screen, tpl *grayImage, threshold float64, limit int) ([]TemplateMatch, error) {
+	tplZero, tplNorm := tpl.zeroMean()
+	if tplNorm*tplNorm <= 1e-9 {
+		return nil, fmt.Errorf(" Template image has no contrast, can not match ")
+	}
+
+	integral := newIntegralImage(screen)
+	var candidates []TemplateMatch
+	for y := 0; y <= screen.height-tpl.height; y++ {
+		for x := 0; x <= screen.width-tpl.width; x++ {
+			score := nccAt(screen, integral, tplZero, tplNorm, tpl.width, tpl.height, x, y)
+			if score >= threshold {
+				candidates = append(candidates, TemplateMatch{
+					Rect:  image.Rect(x, y, x+tpl.width, y+tpl.height),
+					Score: score,
+				})
+			}
+		}
+	}
+
+	return suppressOverlaps(candidates, limit), nil
+}
+
+func nccAt(screen *grayImage, integral *integralImage, tplZero []float64, tplNorm float64, tw, th, x, y int) float64 {
+	n := float64(tw * th)
+	sum, sqSum := integral.window(x, y, tw, th)
+	variance := sqSum - sum*sum/n
+	if variance <= 1e-9 {
+		return 0
+	}
+
+	var cross float64
+	for j := 0; j < th; j++ {
+		row := screen.pix[(y+j)*screen.width+x : (y+j)*screen.width+x+tw]
+		tplRow := tplZero[j*tw : (j+1)*tw]
+		for i, v := range row {
+			cross += v * tplRow[i]
+		}
+	}
+	return cross / (tplNorm * math.Sqrt(variance))
+}
+
+// suppressOverlaps 按置信度排序，去除与更高置信度结果重叠的区域，最多保留limit个
+func suppressOverlaps(matches []TemplateMatch, limit int) []TemplateMatch {
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Score > matches[j].Score
+	})
+
+	var kept []TemplateMatch
+	for _, m := range matches {
+		if len(kept) >= limit {
+			break
+		}
+		overlapped := false
+		for _, k := range kept {
+			inter := m.Rect.Intersect(k.Rect)
+			if inter.Empty() {
+				continue
+			}
+			area := float64(inter.Dx() * inter.Dy())
+			smaller := math.Min(float64(m.Rect.Dx()*m.Rect.Dy()), float64(k.Rect.Dx()*k.Rect.Dy()))
+			if area/smaller > 0.3 {
+				overlapped = true
+				break
+			}
+		}
+		if !overlapped {
+			kept = append(kept, m)
+		}
+	}
+	return kept
+}
+
+func toGrayImage(img image.Image) *grayImage {
+	bounds := img.Bounds()
+	gray := &grayImage{
+		width:  bounds.Dx(),
+		height: bounds.Dy(),
+		pix:    make([]float64, bounds.Dx()*bounds.Dy()),
+	}
+	for y := 0; y < gray.height; y++ {
+		for x := 0; x < gray.width; x++ {
+			r, g, b, _ := img.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+			gray.pix[y*gray.width+x] = (0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b)) / 0xffff
+		}
+	}
+	return gray
+}
+
+// shrink 按factor缩小，每个像素取factor*factor区域的平均值
+func (g *grayImage) shrink(factor int) *grayImage {
+	w, h := g.width/factor, g.height/factor
+	out := &grayImage{width: w, height: h, pix: make([]float64, w*h)}
+	area := float64(factor * factor)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var sum float64
+			for j := 0; j < factor; j++ {
+				row := (y*factor + j) * g.width
+				for i := 0; i < factor; i++ {
+					sum += g.pix[row+x*factor+i]
+				}
+			}
+			out.pix[y*w+x] = sum / area
+		}
+	}
+	return out
+}
+
+// resize 双线性插值缩放
+func (g *grayImage) resize(w, h int) *grayImage {
+	if w < 1 {
+		w = 1
+	}
+	if h < 1 {
+		h = 1
+	}
+	out := &grayImage{width: w, height: h, pix: make([]float64, w*h)}
+	sx := float64(g.width) / float64(w)
+	sy := float64(g.height) / float64(h)
+	for y := 0; y < h; y++ {
+		fy := math.Max((float64(y)+0.5)*sy-0.5, 0)
+		y0 := int(fy)
+		y1 := min(y0+1, g.height-1)
+		wy := fy - float64(y0)
+		for x := 0; x < w; x++ {
+			fx := math.Max((float64(x)+0.5)*sx-0.5, 0)
+			x0 := int(fx)
+			x1 := min(x0+1, g.width-1)
+			wx := fx - float64(x0)
+			top := g.pix[y0*g.width+x0]*(1-wx) + g.pix[y0*g.width+x1]*wx
+			bottom := g.pix[y1*g.width+x0]*(1-wx) + g.pix[y1*g.width+x1]*wx
+			out.pix[y*w+x] = top*(1-wy) + bottom*wy
+		}
+	}
+	return out
+}
+
+// zeroMean 返回减去均值后的像素及其L2范数
+func (g *grayImage) zeroMean() ([]float64, float64) {
+	var mean float64
+	for _, v := range g.pix {
+		mean += v
+	}
+	mean /= float64(len(g.pix))
+
+	zero := make([]float64, len(g.pix))
+	var norm float64
+	for i, v := range g.pix {
+		zero[i] = v - mean
+		norm += zero[i] * zero[i]
+	}
+	return zero, math.Sqrt(norm)
+}
+
+func newIntegralImage(g *grayImage) *integralImage {
+	w := g.width + 1
+	integral := &integralImage{
+		width: w,
+		sum:   make([]float64, w*(g.height+1)),
+		sqSum: make([]float64, w*(g.height+1)),
+	}
+	for y := 0; y < g.height; y++ {
+		var rowSum, rowSqSum float64
+		for x := 0; x < g.width; x++ {
+			v := g.pix[y*g.width+x]
+			rowSum += v
+			rowSqSum += v * v
+			integral.sum[(y+1)*w+x+1] = integral.sum[y*w+x+1] + rowSum
+			integral.sqSum[(y+1)*w+x+1] = integral.sqSum[y*w+x+1] + rowSqSum
+		}
+	}
+	return integral
+}
+
+func (in *integralImage) window(x, y, w, h int) (float64, float64) {
+	a := y*in.width + x
+	b := y*in.width + x + w
+	c := (y+h)*in.width + x
+	d := (y+h)*in.width + x + w
+	return in.sum[d] - in.sum[b] - in.sum[c] + in.sum[a],
+		in.sqSum[d] - in.sqSum[b] - in.sqSum[c] + in.sqSum[a]
+}
@@ -0,0 +1,149 @@
+package WdaGo
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math/rand"
+	"testing"
+)
+
+// noiseImage 生成固定种子的随机灰度图，保证每个窗口都有对比度且位置唯一
+func noiseImage(w, h int, seed int64) *image.Gray {
+	r := rand.New(rand.NewSource(seed))
+	img := image.NewGray(image.Rect(0, 0, w, h))
+	for i := range img.Pix {
+		img.Pix[i] = uint8(r.Intn(256))
+	}
+	return img
+}
+
+func cropImage(src image.Image, rect image.Rectangle) *image.Gray {
+	dst := image.NewGray(image.Rect(0, 0, rect.Dx(), rect.Dy()))
+	draw.Draw(dst, dst.Bounds(), src, rect.Min, draw.Src)
+	return dst
+}
+
+func TestMatchTemplate(t *testing.T) {
+	screen := noiseImage(160, 120, 1)
+
+	tests := []struct {
+		name   string
+		rect   image.Rectangle
+		option TemplateMatchOption
+	}{
+		{"small template without downsample", image.Rect(37, 21, 47, 31), DefaultTemplateMatchOption()},
+		{"large template with auto downsample", image.Rect(90, 50, 130, 90), DefaultTemplateMatchOption()},
+		{"explicit downsample", image.Rect(5, 70, 35, 100), TemplateMatchOption{Threshold: 0.95, Downsample: 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches, err := MatchTemplate(screen, cropImage(screen, tt.rect), tt.option)
+			if err != nil {
+				t.Fatalf("MatchTemplate() error = %v", err)
+			}
+			if len(matches) != 1 {
+				t.Fatalf("MatchTemplate() got %d matches, want 1", len(matches))
+			}
+			if matches[0].Rect != tt.rect {
+				t.Errorf("Rect = %v, want %v", matches[0].Rect, tt.rect)
+			}
+			if matches[0].Score < 0.999 {
+				t.Errorf("Score = %v, want ~1", matches[0].Score)
+			}
+			if matches[0].Scale != 1.0 {
+				t.Errorf("Scale = %v, want 1", matches[0].Scale)
+			}
+		})
+	}
+}
+
+func TestMatchTemplateMultipleMatches(t *testing.T) {
+	screen := noiseImage(160, 120, 2)
+	tpl := noiseImage(16, 16, 3)
+	first, second := image.Pt(10, 10), image.Pt(100, 80)
+	draw.Draw(screen, tpl.Bounds().Add(first), tpl, image.Point{}, draw.Src)
+	draw.Draw(screen, tpl.Bounds().Add(second), tpl, image.Point{}, draw.Src)
+
+	matches, err := MatchTemplate(screen, tpl, TemplateMatchOption{Threshold: 0.95, MaxMatches: 5})
+	if err != nil {
+		t.Fatalf("MatchTemplate() error = %v", err)
+	}
+	if len(matches) != 2 {
+		t.Fatalf("MatchTemplate() got %d matches, want 2", len(matches))
+	}
+	found := map[image.Point]bool{matches[0].Rect.Min: true, matches[1].Rect.Min: true}
+	if !found[first] || !found[second] {
+		t.Errorf("matches at %v and %v, want %v and %v", matches[0].Rect.Min, matches[1].Rect.Min, first, second)
+	}
+}
+
+func TestMatchTemplateScaleAndOrigin(t *testing.T) {
+	// 截图的Bounds不从0开始时，结果需要加上偏移
+	base := noiseImage(120, 100, 4)
+	screen := image.NewGray(image.Rect(50, 50, 170, 150))
+	draw.Draw(screen, screen.Bounds(), base, image.Point{}, draw.Src)
+
+	rect := image.Rect(60, 40, 80, 60)
+	tpl := cropImage(base, rect)
+	// 模板放大一倍，需要按0.5缩放后匹配
+	big := image.NewGray(image.Rect(0, 0, 40, 40))
+	for y := 0; y < 40; y++ {
+		for x := 0; x < 40; x++ {
+			big.Set(x, y, tpl.At(x/2, y/2))
+		}
+	}
+
+	matches, err := MatchTemplate(screen, big, TemplateMatchOption{Threshold: 0.8, Scales: []float64{1.0, 0.5}})
+	if err != nil {
+		t.Fatalf("MatchTemplate() error = %v", err)
+	}
+	if len(matches) != 1 {
+		t.Fatalf("MatchTemplate() got %d matches, want 1", len(matches))
+	}
+	if want := rect.Add(image.Pt(50, 50)); matches[0].Rect != want {
+		t.Errorf("Rect = %v, want %v", matches[0].Rect, want)
+	}
+	if matches[0].Scale != 0.5 {
+		t.Errorf("Scale = %v, want 0.5", matches[0].Scale)
+	}
+}
+
+func TestMatchTemplateErrors(t *testing.T) {
+	screen := noiseImage(64, 64, 5)
+
+	flat := image.NewGray(image.Rect(0, 0, 8, 8))
+	draw.Draw(flat, flat.Bounds(), image.NewUniform(color.Gray{Y: 128}), image.Point{}, draw.Src)
+	if _, err := MatchTemplate(screen, flat, DefaultTemplateMatchOption()); err == nil {
+		t.Error("MatchTemplate() with flat template: want error")
+	}
+
+	tpl := cropImage(screen, image.Rect(0, 0, 8, 8))
+	if _, err := MatchTemplate(screen, tpl, TemplateMatchOption{Scales: []float64{-1}}); err == nil {
+		t.Error("MatchTemplate() with negative scale: want error")
+	}
+
+	matches, err := MatchTemplate(screen, noiseImage(8, 8, 6), DefaultTemplateMatchOption())
+	if err != nil {
+		t.Fatalf("MatchTemplate() error = %v", err)
+	}
+	if len(matches) != 0 {
+		t.Errorf("MatchTemplate() with absent template got %d matches, want 0", len(matches))
+	}
+}
+
+func TestPixelToPoint(t *testing.T) {
+	tests := []struct {
+		rect  image.Rectangle
+		scale float64
+		want  ElementLocation
+	}{
+		{image.Rect(0, 0, 10, 20), 1, ElementLocation{X: 5, Y: 10}},
+		{image.Rect(100, 200, 130, 260), 3, ElementLocation{X: 115.0 / 3, Y: 230.0 / 3}},
+	}
+	for _, tt := range tests {
+		if got := PixelToPoint(tt.rect, tt.scale); got != tt.want {
+			t.Errorf("PixelToPoint(%v, %v) = %v, want %v", tt.rect, tt.scale, got, tt.want)
+		}
+	}
+}
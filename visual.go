@@ -0,0 +1,371 @@
+package WdaGo
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	DefaultPixelThreshold = 0.1
+	DefaultMaxDiffRatio   = 0.001
+	BaselineSuffix        = ".png"
+	DiffSuffix            = "_diff.png"
+	ActualSuffix          = "_actual.png"
+)
+
+// VisualMask 对比时忽略的区域，坐标单位为点(point)，对比时根据屏幕scale换算成像素
+type VisualMask struct {
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+// VisualCompareOption 截图对比参数
+//
+//	PixelThreshold 单个像素的颜色差异阈值，取值0~1，超过则认为该像素不同
+//	MaxDiffRatio   不同像素占总像素的最大比例，超过则认为对比失败
+//	MaxDiffPixels  不同像素的最大个数，大于0时生效，与MaxDiffRatio同时判断
+type VisualCompareOption struct {
+	PixelThreshold float64
+	MaxDiffRatio   float64
+	MaxDiffPixels  int64
+	MaskStatusBar  bool
+	Masks          []VisualMask
+	Scale          float64
+	HighlightColor color.Color
+}
+
+// VisualDiffResult 截图对比结果
+type VisualDiffResult struct {
+	Name         string
+	BaselinePath string
+	ActualPath   string
+	DiffPath     string
+	DiffPixels   int64
+	TotalPixels  int64
+	DiffRatio    float64
+	Passed       bool
+	NewBaseline  bool
+	DiffImage    *image.RGBA
+}
+
+// BaselineKey 基线图片按设备型号和系统版本区分
+type BaselineKey struct {
+	Model     string
+	OsVersion string
+}
+
+// BaselineStore 基线图片存储，目录结构为 root/model/osVersion/name.png
+type BaselineStore struct {
+	Root string
+}
+
+// DefaultVisualCompareOption 默认的对比参数，默认屏蔽状态栏
+func DefaultVisualCompareOption() VisualCompareOption {
+	return VisualCompareOption{
+		PixelThreshold: DefaultPixelThreshold,
+		MaxDiffRatio:   DefaultMaxDiffRatio,
+		MaskStatusBar:  true,
+		HighlightColor: color.RGBA{R: 255, A: 255},
+	}
+}
+
+func NewBaselineStore(root string) *BaselineStore {
+	return &BaselineStore{Root: root}
+}
+
+// Dir 获取指定设备的基线目录
+func (store *BaselineStore) Dir(key BaselineKey) string {
+	return filepath.Join(store.Root, sanitizePathPart(key.Model), sanitizePathPart(key.OsVersion))
+}
+
+// Path 获取指定设备和名称的基线图片路径
+func (store *BaselineStore) Path(key BaselineKey, name string) string {
+	return filepath.Join(store.Dir(key), sanitizePathPart(name)+BaselineSuffix)
+}
+
+// Load 读取基线图片，不存在时返回os.ErrNotExist
+func (store *BaselineStore) Load(key BaselineKey, name string) (image.Image, error) {
+	return LoadImage(store.Path(key, name))
+}
+
+// Save 保存基线图片
+func (store *BaselineStore) Save(key BaselineKey, name string, img image.Image) (string, error) {
+	path := store.Path(key, name)
+	if err := SaveImage(path, img); err != nil {
+		return StringNull, err
+	}
+	return path, nil
+}
+
+// GetBaselineKey 通过GetDeviceInfo和GetStatus获取当前设备的型号和系统版本
+func (session *WdaSession) GetBaselineKey() (*BaselineKey, error) {
+	session, end := session.begin("WdaSession.GetBaselineKey")
+	defer end()
+
+	device, err := session.GetDeviceInfo()
+	if err != nil {
+		return nil, fmt.Errorf(" Get baseline key failed :%w", err)
+	}
+
+	status, err := session.GetStatus()
+	if err != nil {
+		return nil, fmt.Errorf(" Get baseline key failed :%w", err)
+	}
+
+	return &BaselineKey{
+		Model:     device.Model,
+		OsVersion: status.OsVersion,
+	}, nil
+}
+
+// StatusBarMask 获取状态栏区域，用于对比时屏蔽时间、信号等动态内容
+func (session *WdaSession) StatusBarMask() (*VisualMask, float64, error) {
+	session, end := session.begin("WdaSession.StatusBarMask")
+	defer end()
+
+	scrSize, err := session.GetScreenSize()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return &VisualMask{
+		X:      0,
+		Y:      0,
+		Width:  float64(scrSize.Value.StatusBarSize.Width),
+		Height: float64(scrSize.Value.StatusBarSize.Height),
+	}, float64(scrSize.Value.Scale), nil
+}
+
+// VisualCheck 截取当前页面并与基线对比，基线不存在时将当前截图保存为基线
+// 对比失败时在基线目录下保存实际截图和差异图
+func (session *WdaSession) VisualCheck(store *BaselineStore, name string, option VisualCompareOption) (*VisualDiffResult, error) {
+	session, end := session.begin("WdaSession.VisualCheck")
+	defer end()
+
+	key, err := session.GetBaselineKey()
+	if err != nil {
+		return nil, err
+	}
+
+	// 屏蔽区域以点为单位，需要屏幕scale换算成像素
+	if option.MaskStatusBar || (len(option.Masks) > 0 && option.Scale == 0) {
+		mask, scale, err := session.StatusBarMask()
+		if err != nil {
+			return nil, fmt.Errorf(" Get status bar mask failed :%w", err)
+		}
+		if option.MaskStatusBar {
+			option.Masks = append(option.Masks, *mask)
+		}
+		if option.Scale == 0 {
+			option.Scale = scale
+		}
+	}
+
+	current, err := session.ScreenShotImage()
+	if err != nil {
+		return nil, err
+	}
+
+	baseline, err := store.Load(*key, name)
+	if os.IsNotExist(err) {
+		path, err := store.Save(*key, name, current)
+		if err != nil {
+			return nil, err
+		}
+		return &VisualDiffResult{
+			Name:         name,
+			BaselinePath: path,
+			Passed:       true,
+			NewBaseline:  true,
+		}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := CompareImages(baseline, current, option)
+	if err != nil {
+		return nil, err
+	}
+	result.Name = name
+	result.BaselinePath = store.Path(*key, name)
+
+	if !result.Passed {
+		base := strings.TrimSuffix(result.BaselinePath, BaselineSuffix)
+		result.ActualPath = base + ActualSuffix
+		if err = SaveImage(result.ActualPath, current); err != nil {
+			return result, err
+		}
+		result.DiffPath = base + DiffSuffix
+		if err = SaveImage(result.DiffPath, result.DiffImage); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// UpdateBaseline 用当前页面截图覆盖基线
+func (session *WdaSession) UpdateBaseline(store *BaselineStore, name string) (string, error) {
+	session, end := session.begin("WdaSession.UpdateBaseline")
+	defer end()
+
+	key, err := session.GetBaselineKey()
+	if err != nil {
+		return StringNull, err
+	}
+
+	current, err := session.ScreenShotImage()
+	if err != nil {
+		return StringNull, err
+	}
+	return store.Save(*key, name, current)
+}
+
+// CompareImages 逐像素对比两张图片，生成差异图，差异像素使用HighlightColor高亮，
+// 相同像素淡化显示，屏蔽区域以灰色显示
+func CompareImages(baseline, current image.Image, option VisualCompareOption) (*VisualDiffResult, error) {
+	bounds := baseline.Bounds()
+	if bounds.Dx() != current.Bounds().Dx() || bounds.Dy() != current.Bounds().Dy() {
+		return nil, fmt.Errorf(" Compare images failed, size is different: %v vs %v ",
+			bounds.Size(), current.Bounds().Size())
+	}
+
+	if option.HighlightColor == nil {
+		option.HighlightColor = color.RGBA{R: 255, A: 255}
+	}
+	scale := option.Scale
+	if scale <= 0 {
+		scale = 1
+	}
+
+	masks := make([]image.Rectangle, 0, len(option.Masks))
+	for _, mask := range option.Masks {
+		masks = append(masks, mask.PixelRect(scale).Add(bounds.Min))
+	}
+
+	diffImage := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	curMin := current.Bounds().Min
+	maskColor := color.RGBA{R: 128, G: 128, B: 128, A: 255}
+
+	var diffPixels, totalPixels int64
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			dx, dy := x-bounds.Min.X, y-bounds.Min.Y
+			if inMasks(masks, x, y) {
+				diffImage.Set(dx, dy, maskColor)
+				continue
+			}
+			totalPixels++
+
+			base := baseline.At(x, y)
+			cur := current.At(curMin.X+dx, curMin.Y+dy)
+			if pixelDistance(base, cur) > option.PixelThreshold {
+				diffPixels++
+				diffImage.Set(dx, dy, option.HighlightColor)
+			} else {
+				diffImage.Set(dx, dy, fadeColor(base))
+			}
+		}
+	}
+
+	result := &VisualDiffResult{
+		DiffPixels:  diffPixels,
+		TotalPixels: totalPixels,
+		DiffImage:   diffImage,
+	}
+	if totalPixels > 0 {
+		result.DiffRatio = float64(diffPixels) / float64(totalPixels)
+	}
+	result.Passed = result.DiffRatio <= option.MaxDiffRatio &&
+		(option.MaxDiffPixels <= 0 || diffPixels <= option.MaxDiffPixels)
+
+	return result, nil
+}
+
+// PixelRect 将点坐标换算成像素坐标
+func (mask VisualMask) PixelRect(scale float64) image.Rectangle {
+	return image.Rect(
+		int(math.Floor(mask.X*scale)),
+		int(math.Floor(mask.Y*scale)),
+		int(math.Ceil((mask.X+mask.Width)*scale)),
+		int(math.Ceil((mask.Y+mask.Height)*scale)),
+	)
+}
+
+// LoadImage 读取图片文件
+func LoadImage(path string) (image.Image, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return nil, fmt.Errorf(" Decode image %v failed :%w", path, err)
+	}
+	return img, nil
+}
+
+// SaveImage 将图片保存为png，目录不存在时自动创建
+func SaveImage(path string, img image.Image) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf(" Create image dir failed :%w", err)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf(" Create image file failed :%w", err)
+	}
+	defer file.Close()
+
+	if err = png.Encode(file, img); err != nil {
+		return fmt.Errorf(" Encode png image failed :%w", err)
+	}
+	return nil
+}
+
+func inMasks(masks []image.Rectangle, x, y int) bool {
+	point := image.Pt(x, y)
+	for _, mask := range masks {
+		if point.In(mask) {
+			return true
+		}
+	}
+	return false
+}
+
+// pixelDistance 计算两个像素的颜色差异，返回0~1
+func pixelDistance(a, b color.Color) float64 {
+	r1, g1, b1, a1 := a.RGBA()
+	r2, g2, b2, a2 := b.RGBA()
+
+	dr := float64(r1) - float64(r2)
+	dg := float64(g1) - float64(g2)
+	db := float64(b1) - float64(b2)
+	da := float64(a1) - float64(a2)
+
+	return math.Sqrt(dr*dr+dg*dg+db*db+da*da) / (2 * 0xffff)
+}
+
+func fadeColor(c color.Color) color.Color {
+	gray := color.GrayModel.Convert(c).(color.Gray)
+	light := uint8(192 + int(gray.Y)/4)
+	return color.RGBA{R: light, G: light, B: light, A: 255}
+}
+
+func sanitizePathPart(part string) string {
+	if part == "" {
+		return "unknown"
+	}
+	replacer := strings.NewReplacer("/", "_", "\\", "_", ":", "_", " ", "_")
+	return replacer.Replace(part)
+}
@@ -0,0 +1,234 @@
+package WdaGo
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+)
+
+var (
+	visualWhite = color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	visualBlack = color.RGBA{A: 255}
+)
+
+// visualImage 纯色图片，rects中的区域填充为fill
+func visualImage(width, height int, background, fill color.Color, rects ...image.Rectangle) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(background), image.Point{}, draw.Src)
+	for _, rect := range rects {
+		draw.Draw(img, rect, image.NewUniform(fill), image.Point{}, draw.Src)
+	}
+	return img
+}
+
+func TestCompareImages(t *testing.T) {
+	base := visualImage(10, 10, visualWhite, nil)
+	tests := []struct {
+		name       string
+		current    image.Image
+		option     VisualCompareOption
+		diffPixels int64
+		total      int64
+		passed     bool
+	}{
+		{"identical", visualImage(10, 10, visualWhite, nil), VisualCompareOption{PixelThreshold: 0.1}, 0, 100, true},
+		{"below pixel threshold", visualImage(10, 10, color.RGBA{R: 250, G: 250, B: 250, A: 255}, nil), VisualCompareOption{PixelThreshold: 0.1}, 0, 100, true},
+		{"one pixel within ratio", visualImage(10, 10, visualWhite, visualBlack, image.Rect(0, 0, 1, 1)), VisualCompareOption{PixelThreshold: 0.1, MaxDiffRatio: 0.01}, 1, 100, true},
+		{"two pixels over ratio", visualImage(10, 10, visualWhite, visualBlack, image.Rect(0, 0, 2, 1)), VisualCompareOption{PixelThreshold: 0.1, MaxDiffRatio: 0.01}, 2, 100, false},
+		{"over max diff pixels", visualImage(10, 10, visualWhite, visualBlack, image.Rect(0, 0, 2, 1)), VisualCompareOption{PixelThreshold: 0.1, MaxDiffRatio: 1, MaxDiffPixels: 1}, 2, 100, false},
+		{
+			name:    "masked area ignored",
+			current: visualImage(10, 10, visualWhite, visualBlack, image.Rect(0, 0, 10, 2)),
+			// 点坐标按scale换算，5x1点为10x2像素
+			option:     VisualCompareOption{PixelThreshold: 0.1, Masks: []VisualMask{{Width: 5, Height: 1}}, Scale: 2},
+			diffPixels: 0,
+			total:      80,
+			passed:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := CompareImages(base, tt.current, tt.option)
+			if err != nil {
+				t.Fatalf("CompareImages() error = %v", err)
+			}
+			if result.DiffPixels != tt.diffPixels || result.TotalPixels != tt.total || result.Passed != tt.passed {
+				t.Errorf("diff = %d/%d passed = %v, want %d/%d passed = %v",
+					result.DiffPixels, result.TotalPixels, result.Passed, tt.diffPixels, tt.total, tt.passed)
+			}
+		})
+	}
+}
+
+func TestCompareImagesDiffImage(t *testing.T) {
+	base := visualImage(4, 4, visualWhite, visualBlack, image.Rect(1, 1, 2, 2))
+	current := visualImage(4, 4, visualWhite, visualBlack, image.Rect(1, 1, 2, 2), image.Rect(3, 3, 4, 4))
+	highlight := color.RGBA{G: 255, A: 255}
+
+	result, err := CompareImages(base, current, VisualCompareOption{
+		PixelThreshold: 0.1,
+		Masks:          []VisualMask{{Width: 1, Height: 1}},
+		Scale:          1,
+		HighlightColor: highlight,
+	})
+	if err != nil {
+		t.Fatalf("CompareImages() error = %v", err)
+	}
+	if got := result.DiffImage.RGBAAt(3, 3); got != highlight {
+		t.Errorf("diff pixel = %v, want highlight %v", got, highlight)
+	}
+	if got := result.DiffImage.RGBAAt(0, 0); got != (color.RGBA{R: 128, G: 128, B: 128, A: 255}) {
+		t.Errorf("masked pixel = %v, want gray", got)
+	}
+	if got := result.DiffImage.RGBAAt(1, 1); got == highlight || got == visualBlack {
+		t.Errorf("same pixel = %v, want faded", got)
+	}
+
+	// 尺寸不同时不能对比
+	if _, err := CompareImages(base, visualImage(4, 5, visualWhite, nil), VisualCompareOption{}); err == nil {
+		t.Error("CompareImages() with different sizes want error")
+	}
+}
+
+func TestVisualMaskPixelRect(t *testing.T) {
+	mask := VisualMask{X: 1.5, Y: 0, Width: 1, Height: 20.2}
+	if got, want := mask.PixelRect(2), image.Rect(3, 0, 5, 41); got != want {
+		t.Errorf("PixelRect(2) = %v, want %v", got, want)
+	}
+}
+
+// visualWda 模拟wda的设备信息、状态栏和截图接口，状态栏为10x2点，scale为2
+type visualWda struct {
+	mu     sync.Mutex
+	screen image.Image
+}
+
+func (fake *visualWda) setScreen(img image.Image) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	fake.screen = img
+}
+
+func (fake *visualWda) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	value := "null"
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/wda/device/info"):
+		value = `{"model":"iPhone 15","name":"test"}`
+	case r.URL.Path == "/status":
+		value = `{"ready":true,"os":{"name":"iOS","version":"17.2"}}`
+	case strings.HasSuffix(r.URL.Path, "/wda/screen"):
+		value = `{"statusBarSize":{"width":10,"height":2},"scale":2,"screenSize":{"width":10,"height":10}}`
+	case strings.HasSuffix(r.URL.Path, "/screenshot"):
+		var buffer bytes.Buffer
+		png.Encode(&buffer, fake.screen)
+		value = `"` + base64.StdEncoding.EncodeToString(buffer.Bytes()) + `"`
+	}
+	w.Header().Set("Content-Type", ContentTypeJson)
+	w.Write([]byte(`{"value":` + value + `,"sessionId":"visual"}`))
+}
+
+func TestStatusBarMask(t *testing.T) {
+	server := httptest.NewServer(&visualWda{})
+	defer server.Close()
+
+	mask, scale, err := GetWdaSession(server.URL).StatusBarMask()
+	if err != nil {
+		t.Fatalf("StatusBarMask() error = %v", err)
+	}
+	if *mask != (VisualMask{Width: 10, Height: 2}) || scale != 2 {
+		t.Errorf("StatusBarMask() = %+v, %v, want 10x2 with scale 2", *mask, scale)
+	}
+}
+
+func TestVisualCheck(t *testing.T) {
+	fake := &visualWda{screen: visualImage(20, 20, visualWhite, nil)}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	session := GetWdaSession(server.URL)
+	store := NewBaselineStore(t.TempDir())
+	option := DefaultVisualCompareOption()
+
+	// 基线不存在时保存为基线
+	result, err := session.VisualCheck(store, "home", option)
+	if err != nil {
+		t.Fatalf("VisualCheck() error = %v", err)
+	}
+	path := store.Path(BaselineKey{Model: "iPhone 15", OsVersion: "17.2"}, "home")
+	if !result.NewBaseline || !result.Passed || result.BaselinePath != path {
+		t.Fatalf("first VisualCheck() = %+v, want new baseline at %s", result, path)
+	}
+	if _, err := store.Load(BaselineKey{Model: "iPhone 15", OsVersion: "17.2"}, "home"); err != nil {
+		t.Fatalf("baseline not saved: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		screen image.Image
+		option VisualCompareOption
+		passed bool
+	}{
+		{"same screen", visualImage(20, 20, visualWhite, nil), option, true},
+		// 状态栏为10x2点，scale为2时为20x4像素
+		{"status bar masked", visualImage(20, 20, visualWhite, visualBlack, image.Rect(0, 0, 20, 4)), option, true},
+		{"status bar compared", visualImage(20, 20, visualWhite, visualBlack, image.Rect(0, 0, 20, 4)), VisualCompareOption{PixelThreshold: 0.1}, false},
+		{"custom mask uses screen scale", visualImage(20, 20, visualWhite, visualBlack, image.Rect(10, 10, 20, 20)),
+			VisualCompareOption{PixelThreshold: 0.1, Masks: []VisualMask{{X: 5, Y: 5, Width: 5, Height: 5}}}, true},
+		{"content changed", visualImage(20, 20, visualWhite, visualBlack, image.Rect(0, 10, 20, 20)), option, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake.setScreen(tt.screen)
+			result, err := session.VisualCheck(store, "home", tt.option)
+			if err != nil {
+				t.Fatalf("VisualCheck() error = %v", err)
+			}
+			if result.NewBaseline || result.Passed != tt.passed {
+				t.Fatalf("VisualCheck() = %+v, want passed = %v against the baseline", result, tt.passed)
+			}
+
+			// 失败时在基线旁保存实际截图和差异图
+			for _, saved := range []string{result.ActualPath, result.DiffPath} {
+				if tt.passed {
+					if saved != "" {
+						t.Errorf("saved %s for a passed check", saved)
+					}
+					continue
+				}
+				if _, err := os.Stat(saved); err != nil {
+					t.Errorf("stat %q: %v", saved, err)
+				}
+			}
+		})
+	}
+}
+
+func TestUpdateBaseline(t *testing.T) {
+	fake := &visualWda{screen: visualImage(20, 20, visualWhite, nil)}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	session := GetWdaSession(server.URL)
+	store := NewBaselineStore(t.TempDir())
+
+	if _, err := session.VisualCheck(store, "home", DefaultVisualCompareOption()); err != nil {
+		t.Fatalf("VisualCheck() error = %v", err)
+	}
+	fake.setScreen(visualImage(20, 20, visualBlack, nil))
+	if _, err := session.UpdateBaseline(store, "home"); err != nil {
+		t.Fatalf("UpdateBaseline() error = %v", err)
+	}
+	result, err := session.VisualCheck(store, "home", DefaultVisualCompareOption())
+	if err != nil || !result.Passed || result.NewBaseline {
+		t.Errorf("VisualCheck() after update = %+v, %v, want passed against the new baseline", result, err)
+	}
+}
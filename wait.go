@@ -0,0 +1,65 @@
+package WdaGo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const DefaultPollInterval = 500 * time.Millisecond
+
+// ErrWaitTimeout WaitUntil超时，可以用 errors.Is(err, ErrWaitTimeout) 判断
+var ErrWaitTimeout = errors.New("Wait timeout")
+
+// WaitUntil 每隔interval调用一次condition，直到返回true或者超时
+// condition返回error时立即结束等待
+func WaitUntil(timeout, interval time.Duration, condition func() (bool, error)) error {
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		ok, err := condition()
+		if err != nil {
+			return err
+		}
+		if ok {
+			return nil
+		}
+		if time.Now().Add(interval).After(deadline) {
+			return fmt.Errorf(" %w after %v ", ErrWaitTimeout, timeout)
+		}
+		time.Sleep(interval)
+	}
+}
+
+// WaitUntilContext 同WaitUntil，直到condition返回true，或者ctx取消、到达截止时间时返回ctx.Err()
+func WaitUntilContext(ctx context.Context, interval time.Duration, condition func() (bool, error)) error {
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		ok, err := condition()
+		if err != nil {
+			return err
+		}
+		if ok {
+			return nil
+		}
+
+		timer.Reset(interval)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
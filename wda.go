@@ -1,29 +1,66 @@
 package WdaGo
 
 import (
+	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
 	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	log "github.com/Ning9527fff/MyLog"
 	"github.com/tidwall/gjson"
 )
 
 const (
-	UserAgent       = "Go-HTTP-Client/1.0"
-	ContentTypeJson = "application/json"
-	PicturePath     = "screenShot/"
-	LinkText        = 1
-	PartialLinkText = 2
-	ClassName       = 3
-	Path            = 4
-	ClassChain      = 5
+	UserAgent               = "Go-HTTP-Client/1.0"
+	ContentTypeJson         = "application/json"
+	PicturePath             = "screenShot/"
+	SourceFormatXml         = "xml"
+	SourceFormatJson        = "json"
+	SourceFormatDescription = "description"
+	LinkText                = 1
+	PartialLinkText         = 2
+	ClassName               = 3
+	Path                    = 4
+	ClassChain              = 5
+	AccessibilityId         = 6
+	Predicate               = 7
 )
 
+// 元素查找策略，对应wda /elements 接口的using参数
+const (
+	StrategyAccessibilityId = "accessibility id"
+	StrategyId              = "id"
+	StrategyName            = "name"
+	StrategyClassName       = "class name"
+	StrategyClassChain      = "class chain"
+	StrategyPredicate       = "predicate string"
+	StrategyXpath           = "xpath"
+	StrategyLinkText        = "link text"
+	StrategyPartialLinkText = "partial link text"
+)
+
+// Strategies 支持的全部查找策略
+var Strategies = []string{
+	StrategyAccessibilityId,
+	StrategyId,
+	StrategyName,
+	StrategyClassName,
+	StrategyClassChain,
+	StrategyPredicate,
+	StrategyXpath,
+	StrategyLinkText,
+	StrategyPartialLinkText,
+}
+
 func SetDebugLog() {
 	log.SetLogLevel(log.DebugLevel)
 }
@@ -35,20 +72,26 @@ func GetWdaSession(url string) *WdaSession {
 		"content-type": ContentTypeJson,
 	}
 
-	session := &WdaSession{
+	session := &WdaSession{sessionState: &sessionState{
 		url:     url,
 		headers: header,
 		client:  NewHTTPClient(0),
-	}
+		events:  NewEventBus(),
+	}}
+	session.client.AddStartObserver(session.events.commandStarted)
+	session.client.AddRetryObserver(session.events.commandRetried)
+	session.client.AddObserver(session.events.commandFinished)
 	return session
 }
 
 // GetStatus 获取当前iphone上的wda状态
 func (session *WdaSession) GetStatus() (*PhoneStatus, error) {
+	session, end := session.begin("WdaSession.GetStatus")
+	defer end()
 
 	api := session.url + "/status"
 
-	body, err := session.client.GetRequest(api, session.headers)
+	body, err := session.get(api)
 
 	if err != nil {
 		return nil, err
@@ -63,12 +106,15 @@ func (session *WdaSession) GetStatus() (*PhoneStatus, error) {
 		SdkVersion:   gjson.Get(string(body), "value.os.sdkVersion").String(),
 		State:        gjson.Get(string(body), "value.os.state").String(),
 		IsReady:      gjson.Get(string(body), "value.ready").Bool(),
+		SessionId:    gjson.Get(string(body), "sessionId").String(),
 	}
 
 	return deviceStatus, nil
 }
 
 func (session *WdaSession) GetSession(bundleId string) error {
+	session, end := session.begin("WdaSession.GetSession")
+	defer end()
 
 	api := session.url + "/session"
 
@@ -78,42 +124,123 @@ func (session *WdaSession) GetSession(bundleId string) error {
 		},
 	}
 
-	body, err := session.client.PostRequest(api, data, session.headers)
+	body, err := session.post(api, data)
 	log.DebugF("Response body: %v", string(body))
 
 	if err != nil {
 		return err
 	}
 
+	session.mu.Lock()
 	session.sessionId = gjson.Get(string(body), "value.sessionId").String()
+	session.capabilities = &data.Capabilities
+	session.mu.Unlock()
 	return nil
 }
 
+// RecreateSession 使用创建session时的参数重新创建session，用于wda重启后恢复
+// 使用AttachSession的session没有创建参数，使用空参数创建
+// 创建前先尝试删除旧的session，wda重启后旧session已不存在，删除失败不影响重新创建
+func (session *WdaSession) RecreateSession() error {
+	session, end := session.begin("WdaSession.RecreateSession")
+	defer end()
+
+	session.mu.RLock()
+	previous := session.sessionId
+	bundleId := ""
+	if session.capabilities != nil {
+		bundleId = session.capabilities.BundleId
+	}
+	session.mu.RUnlock()
+
+	if previous != "" {
+		if _, err := session.delete(session.url + "/session/" + previous); err != nil {
+			log.DebugF("Delete previous session %v failed: %v", previous, err)
+		}
+	}
+	if err := session.GetSession(bundleId); err != nil {
+		return fmt.Errorf(" Recreate session failed :%w", err)
+	}
+	if session.SessionId() == "" {
+		return fmt.Errorf(" Recreate session failed, no session id in response ")
+	}
+	return nil
+}
+
+// AttachSession 使用已存在的sessionId，不会向wda创建新的session
+func (session *WdaSession) AttachSession(sessionId string) {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	session.sessionId = sessionId
+}
+
+// SessionId 获取当前的sessionId，健康检查恢复session时会在其他goroutine中替换，拼接接口地址时都需要通过该方法读取
+func (session *WdaSession) SessionId() string {
+	session.mu.RLock()
+	defer session.mu.RUnlock()
+	return session.sessionId
+}
+
+// AddCommandObserver 添加wda请求观察者，该session的每次请求完成后回调
+func (session *WdaSession) AddCommandObserver(observer CommandObserver) {
+	session.client.AddObserver(observer)
+}
+
+// Forward 转发wda请求，path为wda地址之后的路径，如 /session/xxx/wda/tap，返回wda的状态码和响应
+func (session *WdaSession) Forward(method, path string, payload []byte) (int, []byte, error) {
+	return session.ForwardContext(context.Background(), method, path, payload)
+}
+
+// ForwardContext 与Forward相同，ctx结束时取消请求，如转发的http请求被客户端断开
+func (session *WdaSession) ForwardContext(ctx context.Context, method, path string, payload []byte) (int, []byte, error) {
+	return session.client.RequestContext(ctx, method, session.url+path, payload, session.headers)
+}
+
+// SetRetry 设置wda请求失败时的重试，默认不重试
+func (session *WdaSession) SetRetry(option RetryOption) {
+	session.client.SetRetry(option)
+}
+
+// Events 获取session的事件总线，用于订阅session创建删除、命令执行、弹窗、app状态、截图和错误事件
+func (session *WdaSession) Events() *EventBus {
+	return session.events
+}
+
+// Url 获取wda地址
+func (session *WdaSession) Url() string {
+	return session.url
+}
+
 // CloseSession 关闭session
 func (session *WdaSession) CloseSession() error {
-	if session.sessionId == "" {
+	session, end := session.begin("WdaSession.CloseSession")
+	defer end()
+
+	if session.SessionId() == "" {
 		return fmt.Errorf(" No session can be closed.")
 	}
 
-	err := session.CloseSession()
+	err := session.DeleteSession()
 	if err != nil {
-		return fmt.Errorf(" Close session failed:  %v", err)
+		return fmt.Errorf(" Close session failed:  %w", err)
 	} else {
-		session.sessionId = ""
+		session.AttachSession("")
 		return nil
 	}
 }
 
 func (session *WdaSession) CheckSession() (bool, error) {
+	session, end := session.begin("WdaSession.CheckSession")
+	defer end()
 
-	api := session.url + "/session/" + session.sessionId
+	api := session.url + "/session/" + session.SessionId()
 
-	body, err := session.client.GetRequest(api, session.headers)
+	body, err := session.get(api)
 	if err != nil {
 		return false, err
 	}
 
-	if gjson.Get(string(body), "sessionId").String() == session.sessionId {
+	if gjson.Get(string(body), "sessionId").String() == session.SessionId() {
 		return true, nil
 	} else {
 		return false, nil
@@ -122,10 +249,19 @@ func (session *WdaSession) CheckSession() (bool, error) {
 }
 
 func (session *WdaSession) DeleteSession() error {
+	session, end := session.begin("WdaSession.DeleteSession")
+	defer end()
+
+	// session关闭时同时停止该session下的录屏
+	if session.recorder != nil {
+		if _, err := session.StopRecording(); err != nil {
+			log.ErrorF("Stop recording failed when delete session: %v", err)
+		}
+	}
 
-	api := session.url + "/session/" + session.sessionId
+	api := session.url + "/session/" + session.SessionId()
 
-	body, err := session.client.DeleteRequest(api, session.headers)
+	body, err := session.delete(api)
 	if err != nil {
 		return err
 	}
@@ -141,9 +277,11 @@ func (session *WdaSession) DeleteSession() error {
 
 // GetDeviceInfo 获取设备当前的状态
 func (session *WdaSession) GetDeviceInfo() (*DeviceInfo, error) {
+	session, end := session.begin("WdaSession.GetDeviceInfo")
+	defer end()
 
-	api := session.url + "/session" + session.sessionId + "/wda/device/info"
-	body, err := session.client.GetRequest(api, session.headers)
+	api := session.url + "/session/" + session.SessionId() + "/wda/device/info"
+	body, err := session.get(api)
 	if err != nil {
 		return nil, err
 	}
@@ -160,9 +298,9 @@ func (session *WdaSession) GetDeviceInfo() (*DeviceInfo, error) {
 		CurrentLocale:      GetStringFromValueInterface(result, "currentLocale"),
 		Model:              GetStringFromValueInterface(result, "model"),
 		Uuid:               GetStringFromValueInterface(result, "uuid"),
-		ThermalState:       GetStringFromValueInterface(result, "thermalState"),
-		UserInterfaceIdiom: GetNumFromValueInterface(result, "userInterfaceIdiom"),
-		UserInterfaceStyle: GetStringFromValueInterface(result, "userInterfaceStyle"),
+		ThermalState:       getEnumFromValueInterface(result, "thermalState", thermalStateNames, ThermalStateUnknown),
+		UserInterfaceIdiom: getEnumFromValueInterface(result, "userInterfaceIdiom", interfaceIdiomNames, InterfaceIdiomUnspecified),
+		UserInterfaceStyle: getEnumFromValueInterface(result, "userInterfaceStyle", interfaceStyleNames, InterfaceStyleUnsupported),
 		Name:               GetStringFromValueInterface(result, "name"),
 		IsSimulator:        GetBoolFromValueInterface(result, "isSimulator"),
 	}
@@ -170,58 +308,67 @@ func (session *WdaSession) GetDeviceInfo() (*DeviceInfo, error) {
 }
 
 // GetLocation 用于获取iphone的经纬度，授权状态等数据
-func (session *WdaSession) GetLocation() (error, *Location) {
-	api := session.url + "/session/" + session.sessionId + "/wda/location"
+func (session *WdaSession) GetLocation() (*Location, error) {
+	session, end := session.begin("WdaSession.GetLocation")
+	defer end()
 
-	body, err := session.client.GetRequest(api, session.headers)
+	api := session.url + "/session/" + session.SessionId() + "/wda/location"
+
+	body, err := session.get(api)
 	if err != nil {
-		return fmt.Errorf(" Get location from api failed: %v ", err), nil
+		return nil, fmt.Errorf(" Get location from api failed: %w ", err)
 	}
 
 	data, err := GetDataFromRespBody(body)
 	if err != nil {
-		return fmt.Errorf(" Get location failed %v ", err), nil
+		return nil, fmt.Errorf(" Get location failed %w ", err)
 	}
 
-	return nil, &Location{
-		Latitude:            GetNumFromValueInterface(data, "latitude"),
-		AuthorizationStatus: GetNumFromValueInterface(data, "authorizationStatus"),
-		Longitude:           GetNumFromValueInterface(data, "longitude"),
-		Altitude:            GetNumFromValueInterface(data, "altitude"),
-	}
+	return &Location{
+		Latitude:            GetFloatFromValueInterface(data, "latitude"),
+		AuthorizationStatus: getEnumFromValueInterface(data, "authorizationStatus", locationAuthNames, LocationAuthNotDetermined),
+		Longitude:           GetFloatFromValueInterface(data, "longitude"),
+		Altitude:            GetFloatFromValueInterface(data, "altitude"),
+		Accuracy:            GetFloatFromValueInterface(data, "accuracy"),
+	}, nil
 }
 
 // GetBatteryInfo 获取电池信息
 func (session *WdaSession) GetBatteryInfo() (*BatteryInfo, error) {
+	session, end := session.begin("WdaSession.GetBatteryInfo")
+	defer end()
 
-	api := session.url + "/session/" + session.sessionId + "/wda/batteryInfo"
-	body, err := session.client.GetRequest(api, session.headers)
+	api := session.url + "/session/" + session.SessionId() + "/wda/batteryInfo"
+	body, err := session.get(api)
 	if err != nil {
-		return nil, fmt.Errorf(" Get battery info failed from api : %v ", err)
+		return nil, fmt.Errorf(" Get battery info failed from api : %w ", err)
 	}
 
 	data, err := GetDataFromRespBody(body)
 	if err != nil {
-		return nil, fmt.Errorf(" Get battery info failed : %v ", err)
+		return nil, fmt.Errorf(" Get battery info failed : %w ", err)
 	}
 
 	return &BatteryInfo{
-		Level: GetNumFromValueInterface(data, "level"),
-		State: GetNumFromValueInterface(data, "state"),
+		Level: GetFloatFromValueInterface(data, "level"),
+		State: BatteryState(GetNumFromValueInterface(data, "state")),
 	}, nil
 
 }
 
 // BackToHomePage 返回home页
 func (session *WdaSession) BackToHomePage() error {
+	session, end := session.begin("WdaSession.BackToHomePage")
+	defer end()
+
 	api := session.url + "/wda/homescreen"
 
-	body, err := session.client.PostRequest(api, nil, session.headers)
+	body, err := session.post(api, nil)
 	if err != nil {
 		return err
 	}
 
-	if gjson.Get(string(body), "sessionId").String() == session.sessionId &&
+	if gjson.Get(string(body), "sessionId").String() == session.SessionId() &&
 		gjson.Get(string(body), "value").String() == "" {
 		return nil
 	} else {
@@ -229,25 +376,58 @@ func (session *WdaSession) BackToHomePage() error {
 	}
 }
 
-// CurrentScreenShot 当前页面截屏, 不置顶文件后缀，默认为.png
-func (session *WdaSession) CurrentScreenShot(picturePath, pictureName string) (string, error) {
+// ScreenShotData 当前页面截屏，返回解码后的png原始数据
+func (session *WdaSession) ScreenShotData() ([]byte, error) {
+	session, end := session.begin("WdaSession.ScreenShotData")
+	defer end()
+
 	api := session.url + "/screenshot"
 
-	body, err := session.client.GetRequest(api, session.headers)
+	body, err := session.get(api)
 	if err != nil {
-		return StringNull, err
+		return nil, err
 	}
 
 	pictureData := gjson.Get(string(body), "value")
 
 	if !pictureData.Exists() {
-		return StringNull, fmt.Errorf(" Current screenshot failed, there is no vaild data ")
+		return nil, fmt.Errorf(" Current screenshot failed, there is no vaild data ")
 	}
 
 	//base64 decode
 	imageDataByte, err := base64.StdEncoding.DecodeString(pictureData.String())
 	if err != nil {
-		return StringNull, fmt.Errorf(" Decode picture data with base64 failed ")
+		return nil, fmt.Errorf(" Decode picture data with base64 failed ")
+	}
+
+	return imageDataByte, nil
+}
+
+// ScreenShotImage 当前页面截屏，返回解码后的image.Image
+func (session *WdaSession) ScreenShotImage() (image.Image, error) {
+	session, end := session.begin("WdaSession.ScreenShotImage")
+	defer end()
+
+	imageDataByte, err := session.ScreenShotData()
+	if err != nil {
+		return nil, err
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(imageDataByte))
+	if err != nil {
+		return nil, fmt.Errorf(" Decode screenshot image failed :%w", err)
+	}
+	return img, nil
+}
+
+// CurrentScreenShot 当前页面截屏, 不置顶文件后缀，默认为.png
+func (session *WdaSession) CurrentScreenShot(picturePath, pictureName string) (string, error) {
+	session, end := session.begin("WdaSession.CurrentScreenShot")
+	defer end()
+
+	imageDataByte, err := session.ScreenShotData()
+	if err != nil {
+		return StringNull, err
 	}
 
 	// 如果传入未指定文件后缀，则默认为png
@@ -257,11 +437,11 @@ func (session *WdaSession) CurrentScreenShot(picturePath, pictureName string) (s
 		pictureName = pictureName + ".png"
 	}
 
-	// err = os.WriteFile(PicturePath+session.sessionId + ".png", imageDataByte, 0644)
+	// err = os.WriteFile(PicturePath+session.SessionId() + ".png", imageDataByte, 0644)
 	imagePath := filepath.Join(picturePath, pictureName)
 	err = os.WriteFile(imagePath, imageDataByte, 0644)
 	if err != nil {
-		return StringNull, fmt.Errorf(" Write image file failed %v", err)
+		return StringNull, fmt.Errorf(" Write image file failed %w", err)
 	} else {
 		return imagePath, nil
 	}
@@ -269,17 +449,14 @@ func (session *WdaSession) CurrentScreenShot(picturePath, pictureName string) (s
 
 // GetAkaTree 获取当前页面树🌲
 func (session *WdaSession) GetAkaTree() error {
+	session, end := session.begin("WdaSession.GetAkaTree")
+	defer end()
 
-	api := session.url + "/source"
-
-	body, err := session.client.GetRequest(api, session.headers)
+	xmlFlow, err := session.GetSource(SourceFormatXml)
 	if err != nil {
-		return fmt.Errorf(" Get Aka Tree failed %v", err)
+		return fmt.Errorf(" Get Aka Tree failed %w", err)
 	}
 
-	xmlFlow := gjson.Get(string(body), "value").String()
-
-	log.DebugF("%v", string(body))
 	log.DebugF("%v", xmlFlow)
 
 	err = os.WriteFile("test.xml", []byte(xmlFlow), 0644)
@@ -287,48 +464,104 @@ func (session *WdaSession) GetAkaTree() error {
 	return nil
 }
 
+// GetSource 获取当前页面树，format为xml、json或description，为空时默认为xml
+// json格式返回的是json字符串
+func (session *WdaSession) GetSource(format string) (string, error) {
+	session, end := session.begin("WdaSession.GetSource")
+	defer end()
+
+	api := session.url + "/source"
+	if format != "" {
+		api += "?format=" + url.QueryEscape(format)
+	}
+
+	body, err := session.get(api)
+	if err != nil {
+		return StringNull, fmt.Errorf(" Get source failed %w", err)
+	}
+
+	value := gjson.Get(string(body), "value")
+	if !value.Exists() {
+		return StringNull, fmt.Errorf(" Get source failed, there is no vaild data ")
+	}
+
+	if value.IsObject() || value.IsArray() {
+		return value.Raw, nil
+	}
+	return value.String(), nil
+}
+
 // SearchElement 以不同方式搜索元素
 func (session *WdaSession) SearchElement(searchType int, Parms string) (string, error) {
+	session, end := session.begin("WdaSession.SearchElement")
+	defer end()
 
-	api := session.url + "/session/" + session.sessionId + "/elements"
-
-	eleReq := ElementSearchRequest{}
+	var using string
 	switch searchType {
 	case LinkText:
-		eleReq.Using = "link text"
+		using = StrategyLinkText
 	case PartialLinkText:
-		eleReq.Using = "partial link text"
+		using = StrategyPartialLinkText
 	case ClassName:
-		eleReq.Using = "class name"
+		using = StrategyClassName
 	case ClassChain:
-		eleReq.Using = "xpath "
+		using = StrategyClassChain
 	case Path:
-		eleReq.Using = "xpath"
+		using = StrategyXpath
+	case AccessibilityId:
+		using = StrategyAccessibilityId
+	case Predicate:
+		using = StrategyPredicate
 	default:
 		return "", fmt.Errorf(" Not supported search type now ")
 	}
 
-	eleReq.Value = Parms
+	elements, err := session.FindElements(using, Parms)
+	if err != nil {
+		return "", err
+	}
+	if len(elements) == 0 {
+		return "", nil
+	}
+	return elements[0], nil
+
+}
+
+// FindElements 使用指定策略查找全部匹配的元素，返回元素id列表，没有匹配时返回空列表
+func (session *WdaSession) FindElements(using, value string) ([]string, error) {
+	session, end := session.begin("WdaSession.FindElements")
+	defer end()
+
+	api := session.url + "/session/" + session.SessionId() + "/elements"
 
-	body, err := session.client.PostRequest(api, eleReq, session.headers)
+	body, err := session.post(api, ElementSearchRequest{
+		Using: using,
+		Value: value,
+	})
 	if err != nil {
-		return "", fmt.Errorf(" Search element failed %v", err)
+		return nil, fmt.Errorf(" Search element failed %w", err)
 	}
-	//这里要加一个返回多个element的逻辑
-	return gjson.Get(string(body), "value.0.ELEMENT").String(), nil
 
+	var elements []string
+	for _, element := range gjson.Get(string(body), "value").Array() {
+		elements = append(elements, element.Get("ELEMENT").String())
+	}
+	return elements, nil
 }
 
 func (session *WdaSession) ClickElement(elementId string) error {
-	api := session.url + "/session/" + session.sessionId + "/element/" + elementId + "/click"
+	session, end := session.begin("WdaSession.ClickElement")
+	defer end()
+
+	api := session.url + "/session/" + session.SessionId() + "/element/" + elementId + "/click"
 
-	body, err := session.client.PostRequest(api, nil, session.headers)
+	body, err := session.post(api, nil)
 	if err != nil {
-		return fmt.Errorf(" Click element failed %v", err)
+		return fmt.Errorf(" Click element failed %w", err)
 	}
 
 	if gjson.Get(string(body), "value").String() == "" &&
-		gjson.Get(string(body), "sessionId").String() == session.sessionId {
+		gjson.Get(string(body), "sessionId").String() == session.SessionId() {
 		return nil
 	} else {
 		return fmt.Errorf(" Click element failed ")
@@ -337,19 +570,31 @@ func (session *WdaSession) ClickElement(elementId string) error {
 }
 
 func (session *WdaSession) TypingText(elementId string, Text string) error {
-	api := session.url + "/session/" + session.sessionId + "/element/" + elementId + "/value"
+	session, end := session.begin("WdaSession.TypingText")
+	defer end()
+
+	return session.TypingTextWithFrequency(elementId, Text, 0)
+}
+
+// TypingTextWithFrequency 向元素输入文本，frequency为每分钟输入的字符数
+func (session *WdaSession) TypingTextWithFrequency(elementId string, Text string, frequency int) error {
+	session, end := session.begin("WdaSession.TypingTextWithFrequency")
+	defer end()
+
+	api := session.url + "/session/" + session.SessionId() + "/element/" + elementId + "/value"
 
 	typingReq := TypingRequest{
-		Value: []byte(Text),
+		Value:     SplitKeys(Text),
+		Frequency: frequency,
 	}
 
-	body, err := session.client.PostRequest(api, typingReq, session.headers)
+	body, err := session.post(api, typingReq)
 	if err != nil {
-		return fmt.Errorf(" Typing text failed %v", err)
+		return fmt.Errorf(" Typing text failed %w", err)
 	}
 
 	if gjson.Get(string(body), "value").String() == "" &&
-		gjson.Get(string(body), "sessionId").String() == session.sessionId {
+		gjson.Get(string(body), "sessionId").String() == session.SessionId() {
 		return nil
 	} else {
 		return fmt.Errorf(" Typing text failed ")
@@ -357,49 +602,115 @@ func (session *WdaSession) TypingText(elementId string, Text string) error {
 }
 
 func (session *WdaSession) ClearText(elementId string) error {
-	api := session.url + "/session/" + session.sessionId + "/element/" + elementId + "/clear"
+	session, end := session.begin("WdaSession.ClearText")
+	defer end()
 
-	body, err := session.client.PostRequest(api, nil, session.headers)
+	api := session.url + "/session/" + session.SessionId() + "/element/" + elementId + "/clear"
+
+	body, err := session.post(api, nil)
 	if err != nil {
-		return fmt.Errorf(" Clear text failed %v", err)
+		return fmt.Errorf(" Clear text failed %w", err)
 	}
 
 	if gjson.Get(string(body), "value").String() == "" &&
-		gjson.Get(string(body), "sessionId").String() == session.sessionId {
+		gjson.Get(string(body), "sessionId").String() == session.SessionId() {
 		return nil
 	} else {
 		return fmt.Errorf(" Click element failed ")
 	}
 }
 
-func (session *WdaSession) AlertGet(client *HTTPClient) error {
-	//curl -X GET $JSON_HEADER $DEVICE_URL/session/$SESSION_ID/alert/text
-	return nil
+// AlertGet 获取当前弹窗的文本，没有弹窗时返回错误
+func (session *WdaSession) AlertGet() (string, error) {
+	session, end := session.begin("WdaSession.AlertGet")
+	defer end()
+
+	api := session.url + "/session/" + session.SessionId() + "/alert/text"
+
+	body, err := session.get(api)
+	if err != nil {
+		return StringNull, fmt.Errorf(" Get alert text failed from api :%w", err)
+	}
+
+	if err = JudgeResponseError(body); err != nil {
+		return StringNull, fmt.Errorf(" Get alert text failed :%w", err)
+	}
+	return gjson.Get(string(body), "value").String(), nil
 }
 
-func (session *WdaSession) AlertAccept(client *HTTPClient) error {
-	//curl -X POST $JSON_HEADER -d "" $DEVICE_URL/session/$SESSION_ID/alert/accept
-	return nil
+// AlertButtons 获取当前弹窗的按钮名
+func (session *WdaSession) AlertButtons() ([]string, error) {
+	session, end := session.begin("WdaSession.AlertButtons")
+	defer end()
+
+	api := session.url + "/session/" + session.SessionId() + "/wda/alert/buttons"
+
+	body, err := session.get(api)
+	if err != nil {
+		return nil, fmt.Errorf(" Get alert buttons failed from api :%w", err)
+	}
+
+	if err = JudgeResponseError(body); err != nil {
+		return nil, fmt.Errorf(" Get alert buttons failed :%w", err)
+	}
+
+	var buttons []string
+	for _, button := range gjson.Get(string(body), "value").Array() {
+		buttons = append(buttons, button.String())
+	}
+	return buttons, nil
+}
+
+// AlertAccept 接受弹窗，buttonName不为空时点击指定按钮
+func (session *WdaSession) AlertAccept(buttonName ...string) error {
+	session, end := session.begin("WdaSession.AlertAccept")
+	defer end()
+
+	return session.alertAction("accept", buttonName)
+}
+
+// AlertDismiss 取消弹窗，buttonName不为空时点击指定按钮
+func (session *WdaSession) AlertDismiss(buttonName ...string) error {
+	session, end := session.begin("WdaSession.AlertDismiss")
+	defer end()
+
+	return session.alertAction("dismiss", buttonName)
 }
 
-func (session *WdaSession) AlertDismiss(client *HTTPClient) error {
-	//curl -X POST $JSON_HEADER -d "" $DEVICE_URL/session/$SESSION_ID/alert/dismiss
+func (session *WdaSession) alertAction(action string, buttonName []string) error {
+	api := session.url + "/session/" + session.SessionId() + "/alert/" + action
+
+	var data interface{}
+	if len(buttonName) > 0 && buttonName[0] != "" {
+		data = ButtonName{Name: buttonName[0]}
+	}
+
+	body, err := session.post(api, data)
+	if err != nil {
+		return fmt.Errorf(" Alert %v failed from api :%w", action, err)
+	}
+
+	if err = JudgeResponseError(body); err != nil {
+		return fmt.Errorf(" Alert %v failed :%w", action, err)
+	}
 	return nil
 }
 
 // GetWindowSize 获取当前窗口大小
 func (session *WdaSession) GetWindowSize() (*WindowSize, error) {
+	session, end := session.begin("WdaSession.GetWindowSize")
+	defer end()
 
-	api := session.url + "/session/" + session.sessionId + "/windows/size"
+	api := session.url + "/session/" + session.SessionId() + "/window/size"
 
-	body, err := session.client.GetRequest(api, session.headers)
+	body, err := session.get(api)
 	if err != nil {
-		return nil, fmt.Errorf(" Get WindowSize failed from api :%v", err)
+		return nil, fmt.Errorf(" Get WindowSize failed from api :%w", err)
 	}
 
 	data, err := GetDataFromRespBody(body)
 	if err != nil {
-		return nil, fmt.Errorf(" Get WindowSize failed :%v", err)
+		return nil, fmt.Errorf(" Get WindowSize failed :%w", err)
 	}
 
 	return &WindowSize{
@@ -410,74 +721,88 @@ func (session *WdaSession) GetWindowSize() (*WindowSize, error) {
 
 // GetScreenSize 获取设备屏幕的点长和点宽，返回换算系数和ScreenSize
 func (session *WdaSession) GetScreenSize() (*ScreenSizeResponse, error) {
-	api := session.url + "/session/" + session.sessionId + "/wda/screen"
-	body, err := session.client.GetRequest(api, session.headers)
+	session, end := session.begin("WdaSession.GetScreenSize")
+	defer end()
+
+	api := session.url + "/session/" + session.SessionId() + "/wda/screen"
+	body, err := session.get(api)
 	if err != nil {
-		return nil, fmt.Errorf(" Get Screen Size failed from api :%v", err)
+		return nil, fmt.Errorf(" Get Screen Size failed from api :%w", err)
 	}
 
 	scrSize := &ScreenSizeResponse{}
 
 	err = json.Unmarshal(body, &scrSize)
 	if err != nil {
-		return nil, fmt.Errorf(" Parse Screen Size failed :%v", err)
+		return nil, fmt.Errorf(" Parse Screen Size failed :%w", err)
 	}
 	return scrSize, nil
 }
 
 func (session *WdaSession) GetActiveAppInfo() (*AppInfo, error) {
-	api := session.url + "/session/" + session.sessionId + "/wda/activeAppInfo"
+	session, end := session.begin("WdaSession.GetActiveAppInfo")
+	defer end()
+
+	api := session.url + "/session/" + session.SessionId() + "/wda/activeAppInfo"
 
-	body, err := session.client.GetRequest(api, session.headers)
+	body, err := session.get(api)
 	if err != nil {
-		return nil, fmt.Errorf(" Get Active App info failed from api :%v", err)
+		return nil, fmt.Errorf(" Get Active App info failed from api :%w", err)
 	}
 
 	var appInfo AppInfo
 	if err = json.Unmarshal(body, &appInfo); err != nil {
-		return nil, fmt.Errorf(" Get Active App info failed from response body :%v", err)
+		return nil, fmt.Errorf(" Get Active App info failed from response body :%w", err)
 	}
 
 	return &appInfo, nil
 }
 
 func (session *WdaSession) GetAppList() (*[]AppBaseInfo, error) {
+	session, end := session.begin("WdaSession.GetAppList")
+	defer end()
 
-	api := session.url + "/session/" + session.sessionId + "/wda/apps/list"
+	api := session.url + "/session/" + session.SessionId() + "/wda/apps/list"
 
-	body, err := session.client.GetRequest(api, session.headers)
+	body, err := session.get(api)
 	if err != nil {
-		return nil, fmt.Errorf(" Get App list failed from api :%v", err)
+		return nil, fmt.Errorf(" Get App list failed from api :%w", err)
 	}
 
 	var appList AppList
 	if err = json.Unmarshal(body, &appList); err != nil {
-		return nil, fmt.Errorf(" Get App list failed from response body :%v", err)
+		return nil, fmt.Errorf(" Get App list failed from response body :%w", err)
 	}
 
 	return &appList.Value, nil
 }
 
-func (session *WdaSession) GetAppState(bundleIdString string) (int64, error) {
-	api := session.url + "/session/" + session.sessionId + "/wda/apps/state"
+func (session *WdaSession) GetAppState(bundleIdString string) (AppState, error) {
+	session, end := session.begin("WdaSession.GetAppState")
+	defer end()
+
+	api := session.url + "/session/" + session.SessionId() + "/wda/apps/state"
 
 	bundleId := BundleIdRequest{BundleId: bundleIdString}
 
-	body, err := session.client.PostRequest(api, bundleId, session.headers)
+	body, err := session.post(api, bundleId)
 	if err != nil {
-		return 0, fmt.Errorf(" Get App state failed from api :%v", err)
+		return AppStateUnknown, fmt.Errorf(" Get App state failed from api :%w", err)
 	}
 
-	return gjson.Get(string(body), "value").Int(), nil
+	return AppState(gjson.Get(string(body), "value").Int()), nil
 }
 
 // IsLocked 是否锁屏
 func (session *WdaSession) IsLocked() (bool, error) {
-	api := session.url + "/session/" + session.sessionId + "/wda/locked"
+	session, end := session.begin("WdaSession.IsLocked")
+	defer end()
 
-	body, err := session.client.GetRequest(api, session.headers)
+	api := session.url + "/session/" + session.SessionId() + "/wda/locked"
+
+	body, err := session.get(api)
 	if err != nil {
-		return false, fmt.Errorf(" Get Locked status failed from api :%v", err)
+		return false, fmt.Errorf(" Get Locked status failed from api :%w", err)
 	}
 
 	return gjson.Get(string(body), "value").Bool(), nil
@@ -485,15 +810,18 @@ func (session *WdaSession) IsLocked() (bool, error) {
 
 // UnlockedDevice 解锁设备
 func (session *WdaSession) UnlockedDevice() error {
-	api := session.url + "/session/" + session.sessionId + "/wda/unlock"
+	session, end := session.begin("WdaSession.UnlockedDevice")
+	defer end()
+
+	api := session.url + "/session/" + session.SessionId() + "/wda/unlock"
 
-	body, err := session.client.PostRequest(api, nil, session.headers)
+	body, err := session.post(api, nil)
 	if err != nil {
-		return fmt.Errorf(" Unlocked device failed from api :%v", err)
+		return fmt.Errorf(" Unlocked device failed from api :%w", err)
 	}
 
 	if gjson.Get(string(body), "value").String() == "" &&
-		gjson.Get(string(body), "sessionId").String() == session.sessionId {
+		gjson.Get(string(body), "sessionId").String() == session.SessionId() {
 		return nil
 	} else {
 		return fmt.Errorf(" Unlocked device failed ")
@@ -501,110 +829,129 @@ func (session *WdaSession) UnlockedDevice() error {
 }
 
 func (session *WdaSession) LockedDevice() error {
-	api := session.url + "/session/" + session.sessionId + "/wda/lock"
+	session, end := session.begin("WdaSession.LockedDevice")
+	defer end()
+
+	api := session.url + "/session/" + session.SessionId() + "/wda/lock"
 
-	body, err := session.client.PostRequest(api, nil, session.headers)
+	body, err := session.post(api, nil)
 	if err != nil {
-		return fmt.Errorf(" Lock device failed from api :%v", err)
+		return fmt.Errorf(" Lock device failed from api :%w", err)
 	}
 
 	if gjson.Get(string(body), "value").String() == "" &&
-		gjson.Get(string(body), "sessionId").String() == session.sessionId {
+		gjson.Get(string(body), "sessionId").String() == session.SessionId() {
 		return nil
 	} else {
 		return fmt.Errorf(" Lock device failed ")
 	}
 }
 
+// LaunchApp 启动app
 func (session *WdaSession) LaunchApp(bundleId string) error {
+	session, end := session.begin("WdaSession.LaunchApp")
+	defer end()
 
-	api := session.url + "/session/" + session.sessionId + "/wda/apps/launch"
-	bundleIdReq := BundleIdRequest{
-		BundleId: bundleId,
-	}
+	return session.LaunchAppWithOption(AppLaunchOption{BundleId: bundleId})
+}
+
+// LaunchAppWithOption 启动app，可指定启动参数、环境变量以及是否等待app空闲
+func (session *WdaSession) LaunchAppWithOption(option AppLaunchOption) error {
+	session, end := session.begin("WdaSession.LaunchAppWithOption")
+	defer end()
 
-	body, err := session.client.PostRequest(api, bundleIdReq, session.headers)
+	api := session.url + "/session/" + session.SessionId() + "/wda/apps/launch"
+
+	body, err := session.post(api, option)
 	if err != nil {
-		return fmt.Errorf(" Launch App failed from api :%v", err)
+		return fmt.Errorf(" Launch App failed from api :%w", err)
 	}
 
-	if JudgeResponseCorrect(body, session.sessionId) {
-		return nil
-	} else {
-		return fmt.Errorf(" Launch App failed ")
+	if err = JudgeResponseError(body); err != nil {
+		return fmt.Errorf(" Launch App failed :%w", err)
 	}
-
+	return nil
 }
 
 // LaunchAppWithoutSession 不需要指定session来启动app
 func (session *WdaSession) LaunchAppWithoutSession(bundleId string) error {
+	session, end := session.begin("WdaSession.LaunchAppWithoutSession")
+	defer end()
+
 	api := session.url + "/wda/apps/launchUnattached"
 	bundleIdReq := BundleIdRequest{
 		BundleId: bundleId,
 	}
 
-	body, err := session.client.PostRequest(api, bundleIdReq, session.headers)
+	body, err := session.post(api, bundleIdReq)
 	if err != nil {
-		return fmt.Errorf(" Launch App without session failed from api :%v", err)
+		return fmt.Errorf(" Launch App without session failed from api :%w", err)
 	}
 
-	if JudgeResponseCorrect(body, session.sessionId) {
-		return nil
-	} else {
-		return fmt.Errorf(" Launch App without session failed ")
+	if err = JudgeResponseError(body); err != nil {
+		return fmt.Errorf(" Launch App without session failed :%w", err)
 	}
+	return nil
 }
 
-// TerminateApp 关闭app
+// TerminateApp 关闭app，app未运行时wda返回false，此时返回错误
 func (session *WdaSession) TerminateApp(bundleId string) error {
-	api := session.url + "/session/" + session.sessionId + "/wda/apps/terminate"
+	session, end := session.begin("WdaSession.TerminateApp")
+	defer end()
+
+	api := session.url + "/session/" + session.SessionId() + "/wda/apps/terminate"
 	bundleIdReq := BundleIdRequest{
 		BundleId: bundleId,
 	}
 
-	body, err := session.client.PostRequest(api, bundleIdReq, session.headers)
+	body, err := session.post(api, bundleIdReq)
 	if err != nil {
-		return fmt.Errorf(" Terminate App failed from api :%v", err)
+		return fmt.Errorf(" Terminate App failed from api :%w", err)
 	}
 
-	if gjson.Get(string(body), "value").Bool() {
-		return nil
-	} else {
-		return fmt.Errorf(" Terminate App failed ")
+	if err = JudgeResponseError(body); err != nil {
+		return fmt.Errorf(" Terminate App failed :%w", err)
 	}
+	return nil
 }
 
-// ActivateApp 激活app？与启动有何区别暂时没搞清楚
+// ActivateApp 激活app，app未运行时启动，已在后台时切换到前台
 func (session *WdaSession) ActivateApp(bundleId string) error {
-	api := session.url + "/session/" + session.sessionId + "/wda/apps/activate"
+	session, end := session.begin("WdaSession.ActivateApp")
+	defer end()
+
+	api := session.url + "/session/" + session.SessionId() + "/wda/apps/activate"
 	bundleIdReq := BundleIdRequest{
 		BundleId: bundleId,
 	}
-	body, err := session.client.PostRequest(api, bundleIdReq, session.headers)
+	body, err := session.post(api, bundleIdReq)
 	if err != nil {
-		return fmt.Errorf(" Activate App failed from api :%v", err)
+		return fmt.Errorf(" Activate App failed from api :%w", err)
 	}
-	if JudgeResponseCorrect(body, session.sessionId) {
-		return nil
-	} else {
-		return fmt.Errorf(" Activate App failed ")
+
+	if err = JudgeResponseError(body); err != nil {
+		return fmt.Errorf(" Activate App failed :%w", err)
 	}
+	return nil
 }
 
 // DeactivateApp 让app处于后台状态指定时间
 func (session *WdaSession) DeactivateApp(time int) error {
-	api := session.url + "/session/" + session.sessionId + "/wda/deactivateApp"
+	session, end := session.begin("WdaSession.DeactivateApp")
+	defer end()
+
+	api := session.url + "/session/" + session.SessionId() + "/wda/deactivateApp"
 
 	dura := PauseTime{
 		Duration: time,
 	}
 
-	body, err := session.client.PostRequest(api, dura, session.headers)
+	body, err := session.post(api, dura)
 	if err != nil {
-		return fmt.Errorf(" Deactivate app failed %v", err)
+		return fmt.Errorf(" Deactivate app failed %w", err)
 	}
 
-	if JudgeResponseCorrect(body, session.sessionId) {
+	if JudgeResponseCorrect(body, session.SessionId()) {
 		return nil
 	} else {
 		return fmt.Errorf(" Deactivate app failed ")
@@ -613,16 +960,19 @@ func (session *WdaSession) DeactivateApp(time int) error {
 
 // ResetAppAuth 重置app auth，暂时不清楚如何使用，先实现
 func (session *WdaSession) ResetAppAuth(resource string) error {
-	api := session.url + "/session/" + session.sessionId + "/wda/resetAppAuth"
+	session, end := session.begin("WdaSession.ResetAppAuth")
+	defer end()
+
+	api := session.url + "/session/" + session.SessionId() + "/wda/resetAppAuth"
 	sourceReq := SourceRequest{
 		Resource: resource,
 	}
 
-	body, err := session.client.PostRequest(api, sourceReq, session.headers)
+	body, err := session.post(api, sourceReq)
 	if err != nil {
-		return fmt.Errorf(" Reset App Auth failed from api :%v", err)
+		return fmt.Errorf(" Reset App Auth failed from api :%w", err)
 	}
-	if JudgeResponseCorrect(body, session.sessionId) {
+	if JudgeResponseCorrect(body, session.SessionId()) {
 		return nil
 	} else {
 		return fmt.Errorf(" Reset App Auth failed ")
@@ -631,17 +981,20 @@ func (session *WdaSession) ResetAppAuth(resource string) error {
 
 // TapWithLocation  使用坐标点击
 func (session *WdaSession) TapWithLocation(location ElementLocation) error {
-	api := session.url + "/session/" + session.sessionId + "/wda/tap"
+	session, end := session.begin("WdaSession.TapWithLocation")
+	defer end()
+
+	api := session.url + "/session/" + session.SessionId() + "/wda/tap"
 
-	body, err := session.client.PostRequest(api, ElementLocation{
+	body, err := session.post(api, ElementLocation{
 		X: location.X,
 		Y: location.Y,
-	}, session.headers)
+	})
 	if err != nil {
-		return fmt.Errorf(" Tap With Location failed from api :%v", err)
+		return fmt.Errorf(" Tap With Location failed from api :%w", err)
 	}
 
-	if JudgeResponseCorrect(body, session.sessionId) {
+	if JudgeResponseCorrect(body, session.SessionId()) {
 		return nil
 	} else {
 		return fmt.Errorf(" Tap With Location failed ")
@@ -651,17 +1004,20 @@ func (session *WdaSession) TapWithLocation(location ElementLocation) error {
 
 // DoubleTapWithLocation 使用坐标双击
 func (session *WdaSession) DoubleTapWithLocation(x, y float64) error {
-	api := session.url + "/session/" + session.sessionId + "/wda/doubleTap"
+	session, end := session.begin("WdaSession.DoubleTapWithLocation")
+	defer end()
 
-	body, err := session.client.PostRequest(api, ElementLocation{
+	api := session.url + "/session/" + session.SessionId() + "/wda/doubleTap"
+
+	body, err := session.post(api, ElementLocation{
 		X: x,
 		Y: y,
-	}, session.headers)
+	})
 	if err != nil {
-		return fmt.Errorf(" Tap With Location failed from api :%v", err)
+		return fmt.Errorf(" Tap With Location failed from api :%w", err)
 	}
 
-	if JudgeResponseCorrect(body, session.sessionId) {
+	if JudgeResponseCorrect(body, session.SessionId()) {
 		return nil
 	} else {
 		return fmt.Errorf(" Double Tap With Location failed ")
@@ -670,20 +1026,23 @@ func (session *WdaSession) DoubleTapWithLocation(x, y float64) error {
 
 // TouchAndHoldWithLocation 对指定坐标长按
 func (session *WdaSession) TouchAndHoldWithLocation(x, y, duration float64) error {
-	api := session.url + "/session/" + session.sessionId + "/wda/touchAndHold"
+	session, end := session.begin("WdaSession.TouchAndHoldWithLocation")
+	defer end()
+
+	api := session.url + "/session/" + session.SessionId() + "/wda/touchAndHold"
 
-	body, err := session.client.PostRequest(api, HoldRequest{
-		elementLocation: ElementLocation{
+	body, err := session.post(api, HoldRequest{
+		ElementLocation: ElementLocation{
 			X: x,
 			Y: y,
 		},
 		Duration: duration,
-	}, session.headers)
+	})
 	if err != nil {
-		return fmt.Errorf(" TouchAndHold With Location failed from api :%v", err)
+		return fmt.Errorf(" TouchAndHold With Location failed from api :%w", err)
 	}
 
-	if JudgeResponseCorrect(body, session.sessionId) {
+	if JudgeResponseCorrect(body, session.SessionId()) {
 		return nil
 	} else {
 		return fmt.Errorf(" TouchAndHold With Location failed ")
@@ -692,19 +1051,31 @@ func (session *WdaSession) TouchAndHoldWithLocation(x, y, duration float64) erro
 
 // DragWithLocation 拖动操作 swipe操作与该操作本纸上为同一个
 func (session *WdaSession) DragWithLocation(xBefore, yBefore, xLater, yLater float64) error {
-	api := session.url + "/session/" + session.sessionId + "/wda/dragfromtoforduration"
+	session, end := session.begin("WdaSession.DragWithLocation")
+	defer end()
 
-	body, err := session.client.PostRequest(api, DragOption{
-		FromX: xBefore,
-		FromY: yBefore,
-		ToX:   xLater,
-		ToY:   yLater,
-	}, session.headers)
+	return session.SwipeWithLocation(xBefore, yBefore, xLater, yLater, 0)
+}
+
+// SwipeWithLocation 从起点按下duration秒后滑动到终点
+func (session *WdaSession) SwipeWithLocation(xBefore, yBefore, xLater, yLater, duration float64) error {
+	session, end := session.begin("WdaSession.SwipeWithLocation")
+	defer end()
+
+	api := session.url + "/session/" + session.SessionId() + "/wda/dragfromtoforduration"
+
+	body, err := session.post(api, DragOption{
+		FromX:    xBefore,
+		FromY:    yBefore,
+		ToX:      xLater,
+		ToY:      yLater,
+		Duration: duration,
+	})
 	if err != nil {
-		return fmt.Errorf(" Drag With Location failed from api :%v", err)
+		return fmt.Errorf(" Drag With Location failed from api :%w", err)
 	}
 
-	if JudgeResponseCorrect(body, session.sessionId) {
+	if JudgeResponseCorrect(body, session.SessionId()) {
 		return nil
 	} else {
 		return fmt.Errorf(" Drag With Location failed ")
@@ -717,6 +1088,8 @@ func (session *WdaSession) DragWithLocation(xBefore, yBefore, xLater, yLater flo
 //
 //	home,volumeUp,volumeDown
 func (session *WdaSession) PressButton(buttonType int) error {
+	session, end := session.begin("WdaSession.PressButton")
+	defer end()
 
 	var button ButtonName
 	switch buttonType {
@@ -730,14 +1103,14 @@ func (session *WdaSession) PressButton(buttonType int) error {
 		return fmt.Errorf(" Error: UnKnown Button ")
 	}
 
-	api := session.url + "/session/" + session.sessionId + "/wda/pressButton"
+	api := session.url + "/session/" + session.SessionId() + "/wda/pressButton"
 
-	body, err := session.client.PostRequest(api, button, session.headers)
+	body, err := session.post(api, button)
 	if err != nil {
-		return fmt.Errorf(" PressButton failed from api :%v", err)
+		return fmt.Errorf(" PressButton failed from api :%w", err)
 	}
 
-	if JudgeResponseCorrect(body, session.sessionId) {
+	if JudgeResponseCorrect(body, session.SessionId()) {
 		return nil
 	} else {
 		return fmt.Errorf(" PressButton failed ")
@@ -746,17 +1119,20 @@ func (session *WdaSession) PressButton(buttonType int) error {
 
 // ExpectedNotification 判断是否出现一个预期中的notification
 func (session *WdaSession) ExpectedNotification(notificationName string, notificationType string, timeOut int64) error {
-	api := session.url + "/session/" + session.sessionId + "/wda/expectedNotification"
+	session, end := session.begin("WdaSession.ExpectedNotification")
+	defer end()
 
-	body, err := session.client.PostRequest(api, NotificationExpect{
+	api := session.url + "/session/" + session.SessionId() + "/wda/expectedNotification"
+
+	body, err := session.post(api, NotificationExpect{
 		Name:    notificationName,
 		Type:    notificationType,
 		Timeout: timeOut,
-	}, session.headers)
+	})
 	if err != nil {
-		return fmt.Errorf(" Get Expected Notification failed from api :%v", err)
+		return fmt.Errorf(" Get Expected Notification failed from api :%w", err)
 	}
-	if JudgeResponseCorrect(body, session.sessionId) {
+	if JudgeResponseCorrect(body, session.SessionId()) {
 		return nil
 	} else {
 		return fmt.Errorf(" No Expected Notification found ")
@@ -765,16 +1141,19 @@ func (session *WdaSession) ExpectedNotification(notificationName string, notific
 
 // ActiveSiri 启动siri,输入指定文本
 func (session *WdaSession) ActiveSiri(text string) error {
-	api := session.url + "/session/" + session.sessionId + "/wda/siri/activate"
+	session, end := session.begin("WdaSession.ActiveSiri")
+	defer end()
+
+	api := session.url + "/session/" + session.SessionId() + "/wda/siri/activate"
 
-	body, err := session.client.PostRequest(api, TextRequest{
+	body, err := session.post(api, TextRequest{
 		Text: text,
-	}, session.headers)
+	})
 	if err != nil {
-		return fmt.Errorf(" Active Siri failed from api :%v", err)
+		return fmt.Errorf(" Active Siri failed from api :%w", err)
 	}
 
-	if JudgeResponseCorrect(body, session.sessionId) {
+	if JudgeResponseCorrect(body, session.SessionId()) {
 		return nil
 	} else {
 		return fmt.Errorf(" Active Siri failed ")
@@ -785,53 +1164,164 @@ func (session *WdaSession) ActiveSiri(text string) error {
 // LetSiriOpenUrl 让siri打开一个指定的url
 // 传入的url必须是绝对url，即带https或者http
 func (session *WdaSession) LetSiriOpenUrl(RawUrl string) error {
-	api := session.url + "/session/" + session.sessionId + "/url"
+	session, end := session.begin("WdaSession.LetSiriOpenUrl")
+	defer end()
+
+	api := session.url + "/session/" + session.SessionId() + "/url"
 
 	realUrl, err := url.Parse(RawUrl)
 	if err != nil {
-		return fmt.Errorf("Parse Url Failed  :%v", err)
+		return fmt.Errorf("Parse Url Failed  :%w", err)
 	}
 
 	if !realUrl.IsAbs() {
 		return fmt.Errorf(" Url is not a absolutly url  ")
 	}
 
-	body, err := session.client.PostRequest(api, UrlBody{Url: realUrl.String()}, session.headers)
+	body, err := session.post(api, UrlBody{Url: realUrl.String()})
 	if err != nil {
-		return fmt.Errorf(" Siri Open Url failed from api :%v", err)
+		return fmt.Errorf(" Siri Open Url failed from api :%w", err)
 	}
-	if JudgeResponseCorrect(body, session.sessionId) {
+	if JudgeResponseCorrect(body, session.SessionId()) {
 		return nil
 	} else {
-		return fmt.Errorf(" Siri Open Url failed : %v ", err)
+		return fmt.Errorf(" Siri Open Url failed ")
 	}
 }
 
 // GetOrientation 获取当前屏幕方向
-func (session *WdaSession) GetOrientation() (string, error) {
-	api := session.url + "/session/" + session.sessionId + "/orientation"
+func (session *WdaSession) GetOrientation() (Orientation, error) {
+	session, end := session.begin("WdaSession.GetOrientation")
+	defer end()
 
-	body, err := session.client.GetRequest(api, session.headers)
+	api := session.url + "/session/" + session.SessionId() + "/orientation"
+
+	body, err := session.get(api)
 	if err != nil {
-		return "", fmt.Errorf(" Get Orientation failed from api :%v", err)
+		return OrientationUnknown, fmt.Errorf(" Get Orientation failed from api :%w", err)
 	}
 
-	return gjson.Get(string(body), "value").String(), nil
+	return ParseOrientation(gjson.Get(string(body), "value").String())
+}
+
+// SetOrientation 设置屏幕方向，OrientationUnknown、OrientationFaceUp和OrientationFaceDown无法设置
+func (session *WdaSession) SetOrientation(orientation Orientation) error {
+	session, end := session.begin("WdaSession.SetOrientation")
+	defer end()
+
+	if _, ok := orientationNames[orientation]; !ok || orientation == OrientationUnknown {
+		return fmt.Errorf(" Orientation %v can not be set, use portrait, portrait upside down, landscape left or landscape right ", orientation)
+	}
+
+	api := session.url + "/session/" + session.SessionId() + "/orientation"
+
+	body, err := session.post(api, OrientationRequest{
+		Orientation: orientation.String(),
+	})
+	if err != nil {
+		return fmt.Errorf(" Set Orientation failed from api :%w", err)
+	}
+
+	if JudgeResponseCorrect(body, session.SessionId()) {
+		return nil
+	} else {
+		return fmt.Errorf(" Set Orientation failed ")
+	}
 }
 
-// GetRotation 获取当前设备的旋转坐标，暂时没用，先不实现
-func GetRotation() error {
+// SetOrientationAndWait 设置屏幕方向，并等待GetOrientation返回目标方向
+// 横竖屏切换时还会等待GetWindowSize返回的宽高相对旋转前发生变化且与目标方向一致
+func (session *WdaSession) SetOrientationAndWait(orientation Orientation, timeout time.Duration) error {
+	session, end := session.begin("WdaSession.SetOrientationAndWait")
+	defer end()
+
+	before, err := session.GetWindowSize()
+	if err != nil {
+		return fmt.Errorf(" Get window size before rotation failed :%w", err)
+	}
+	if err = session.SetOrientation(orientation); err != nil {
+		return err
+	}
+
+	axisChanged := orientation.IsLandscape() != (before.Width > before.Height)
+	err = WaitUntil(timeout, DefaultPollInterval, func() (bool, error) {
+		current, err := session.GetOrientation()
+		if err != nil {
+			return false, err
+		}
+		if current != orientation {
+			return false, nil
+		}
+		if !axisChanged {
+			return true, nil
+		}
+
+		size, err := session.GetWindowSize()
+		if err != nil {
+			return false, err
+		}
+		return *size != *before && orientation.IsLandscape() == (size.Width > size.Height), nil
+	})
+	if err != nil {
+		return fmt.Errorf(" Wait window size change to %v failed :%w", orientation, err)
+	}
 	return nil
 }
 
+// GetRotation 获取当前设备的旋转角度
+func (session *WdaSession) GetRotation() (*Rotation, error) {
+	session, end := session.begin("WdaSession.GetRotation")
+	defer end()
+
+	api := session.url + "/session/" + session.SessionId() + "/rotation"
+
+	body, err := session.get(api)
+	if err != nil {
+		return nil, fmt.Errorf(" Get Rotation failed from api :%w", err)
+	}
+
+	data, err := GetDataFromRespBody(body)
+	if err != nil {
+		return nil, fmt.Errorf(" Get Rotation failed :%w", err)
+	}
+
+	return &Rotation{
+		X: GetNumFromValueInterface(data, "x"),
+		Y: GetNumFromValueInterface(data, "y"),
+		Z: GetNumFromValueInterface(data, "z"),
+	}, nil
+}
+
+// SetRotation 设置设备的旋转角度，wda目前只支持x=0,y=0,z为0/90/180/270
+func (session *WdaSession) SetRotation(rotation Rotation) error {
+	session, end := session.begin("WdaSession.SetRotation")
+	defer end()
+
+	api := session.url + "/session/" + session.SessionId() + "/rotation"
+
+	body, err := session.post(api, rotation)
+	if err != nil {
+		return fmt.Errorf(" Set Rotation failed from api :%w", err)
+	}
+
+	if JudgeResponseCorrect(body, session.SessionId()) {
+		return nil
+	} else {
+		return fmt.Errorf(" Set Rotation failed ")
+	}
+}
+
 // ShutDownWda 关闭wda
 func (session *WdaSession) ShutDownWda() error {
+	session, end := session.begin("WdaSession.ShutDownWda")
+	defer end()
+
 	api := session.url + "wda/shutDown"
-	body, err := session.client.GetRequest(api, session.headers)
+	body, err := session.get(api)
 	if err != nil {
-		return fmt.Errorf(" ShutDownWda failed from api :%v", err)
+		return fmt.Errorf(" ShutDownWda failed from api :%w", err)
 	}
-	if JudgeResponseCorrect(body, session.sessionId) {
+	if JudgeResponseCorrect(body, session.SessionId()) {
 		return nil
 	} else {
 		return fmt.Errorf(" ShutDown Wda failed ")
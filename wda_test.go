@@ -0,0 +1,245 @@
+package WdaGo
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const testSessionId = "test-session"
+
+// newTestSession 启动模拟wda，返回已绑定testSessionId的session
+func newTestSession(t *testing.T, handler http.Handler) *WdaSession {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	session := GetWdaSession(server.URL)
+	session.AttachSession(testSessionId)
+	return session
+}
+
+// writeValue 按wda的格式返回value
+func writeValue(w http.ResponseWriter, value string) {
+	w.Header().Set("Content-Type", ContentTypeJson)
+	w.Write([]byte(`{"value":` + value + `,"sessionId":"` + testSessionId + `"}`))
+}
+
+// fakeRotation 模拟设备旋转，设置方向后前lag次查询仍返回旋转前的方向和窗口大小
+type fakeRotation struct {
+	mu          sync.Mutex
+	orientation Orientation
+	size        WindowSize
+	target      Orientation
+	lag         int
+	posts       int
+}
+
+func (fake *fakeRotation) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/session/"+testSessionId+"/orientation", func(w http.ResponseWriter, r *http.Request) {
+		fake.mu.Lock()
+		defer fake.mu.Unlock()
+		if r.Method == http.MethodPost {
+			var request OrientationRequest
+			json.NewDecoder(r.Body).Decode(&request)
+			fake.target, _ = ParseOrientation(request.Orientation)
+			fake.posts++
+			writeValue(w, "null")
+			return
+		}
+		fake.tick()
+		writeValue(w, `"`+fake.orientation.String()+`"`)
+	})
+	mux.HandleFunc("/session/"+testSessionId+"/window/size", func(w http.ResponseWriter, r *http.Request) {
+		fake.mu.Lock()
+		defer fake.mu.Unlock()
+		data, _ := json.Marshal(fake.size)
+		writeValue(w, string(data))
+	})
+	return mux
+}
+
+// tick 每次查询方向时推进旋转，lag用完后方向和窗口大小变为目标值
+func (fake *fakeRotation) tick() {
+	if fake.target == fake.orientation || fake.target == OrientationUnknown {
+		return
+	}
+	if fake.lag > 0 {
+		fake.lag--
+		return
+	}
+	if fake.target.IsLandscape() != fake.orientation.IsLandscape() {
+		fake.size = WindowSize{Width: fake.size.Height, Height: fake.size.Width}
+	}
+	fake.orientation = fake.target
+}
+
+func TestGetDeviceInfoThermalState(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  ThermalState
+	}{
+		{"name", `{"thermalState":"serious"}`, ThermalStateSerious},
+		{"number", `{"thermalState":1}`, ThermalStateFair},
+		{"nominal", `{"thermalState":0}`, ThermalStateNominal},
+		{"missing", `{"model":"iPhone"}`, ThermalStateUnknown},
+		{"null", `{"thermalState":null}`, ThermalStateUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := newTestSession(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				writeValue(w, tt.value)
+			}))
+			info, err := session.GetDeviceInfo()
+			if err != nil {
+				t.Fatalf("GetDeviceInfo() error = %v", err)
+			}
+			if info.ThermalState != tt.want {
+				t.Errorf("ThermalState = %v, want %v", info.ThermalState, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetOrientationAndWait(t *testing.T) {
+	portrait := WindowSize{Width: 390, Height: 844}
+	landscape := WindowSize{Width: 844, Height: 390}
+	tests := []struct {
+		name    string
+		from    Orientation
+		size    WindowSize
+		to      Orientation
+		lag     int
+		timeout time.Duration
+		wantErr bool
+	}{
+		{"portrait to landscape", OrientationPortrait, portrait, OrientationLandscapeLeft, 1, 5 * time.Second, false},
+		{"landscape left to right waits for orientation", OrientationLandscapeLeft, landscape, OrientationLandscapeRight, 1, 5 * time.Second, false},
+		{"landscape to portrait upside down", OrientationLandscapeRight, landscape, OrientationPortraitUpsideDown, 0, 5 * time.Second, false},
+		{"device never rotates", OrientationPortrait, portrait, OrientationLandscapeLeft, 100, time.Second, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeRotation{orientation: tt.from, size: tt.size, lag: tt.lag}
+			session := newTestSession(t, fake.handler())
+
+			err := session.SetOrientationAndWait(tt.to, tt.timeout)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SetOrientationAndWait() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			fake.mu.Lock()
+			defer fake.mu.Unlock()
+			if fake.orientation != tt.to {
+				t.Errorf("returned before rotation finished, orientation = %v", fake.orientation)
+			}
+			if tt.to.IsLandscape() != (fake.size.Width > fake.size.Height) {
+				t.Errorf("window size %v does not match %v", fake.size, tt.to)
+			}
+		})
+	}
+}
+
+func TestSetOrientationRejectsUnsupported(t *testing.T) {
+	fake := &fakeRotation{orientation: OrientationPortrait}
+	session := newTestSession(t, fake.handler())
+
+	for _, orientation := range []Orientation{OrientationUnknown, OrientationFaceUp, OrientationFaceDown, Orientation(42)} {
+		if err := session.SetOrientation(orientation); err == nil {
+			t.Errorf("SetOrientation(%v) want error", orientation)
+		}
+	}
+	if fake.posts != 0 {
+		t.Errorf("unsupported orientation sent to wda %d times", fake.posts)
+	}
+	if err := session.SetOrientation(OrientationLandscapeRight); err != nil {
+		t.Errorf("SetOrientation(LandscapeRight) error = %v", err)
+	}
+}
+
+// fakeSessions 模拟wda的session创建和删除，每次创建返回新的sessionId
+type fakeSessions struct {
+	mu      sync.Mutex
+	created int
+	deleted []string
+}
+
+func (fake *fakeSessions) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	w.Header().Set("Content-Type", ContentTypeJson)
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/session":
+		fake.created++
+		w.Write([]byte(`{"value":{"sessionId":"session-` + strconv.Itoa(fake.created) + `","capabilities":{}},"sessionId":"session-` + strconv.Itoa(fake.created) + `"}`))
+	case r.Method == http.MethodDelete:
+		fake.deleted = append(fake.deleted, strings.TrimPrefix(r.URL.Path, "/session/"))
+		w.Write([]byte(`{"value":null,"sessionId":null}`))
+	default:
+		sessionId := strings.TrimPrefix(r.URL.Path, "/session/")
+		sessionId, _, _ = strings.Cut(sessionId, "/")
+		w.Write([]byte(`{"value":null,"sessionId":"` + sessionId + `"}`))
+	}
+}
+
+func TestRecreateSession(t *testing.T) {
+	fake := &fakeSessions{}
+	session := newTestSession(t, fake)
+	session.AttachSession("")
+
+	if err := session.GetSession("com.demo"); err != nil {
+		t.Fatalf("GetSession() error = %v", err)
+	}
+	if err := session.RecreateSession(); err != nil {
+		t.Fatalf("RecreateSession() error = %v", err)
+	}
+	if got := session.SessionId(); got != "session-2" {
+		t.Errorf("SessionId() = %q, want session-2", got)
+	}
+	if len(fake.deleted) != 1 || fake.deleted[0] != "session-1" {
+		t.Errorf("deleted sessions = %v, want [session-1]", fake.deleted)
+	}
+
+	if err := session.CloseSession(); err != nil {
+		t.Fatalf("CloseSession() error = %v", err)
+	}
+	if session.SessionId() != "" {
+		t.Errorf("SessionId() after close = %q, want empty", session.SessionId())
+	}
+	if err := session.CloseSession(); err == nil {
+		t.Error("CloseSession() without session: want error")
+	}
+}
+
+func TestRecreateSessionConcurrentCommands(t *testing.T) {
+	session := newTestSession(t, &fakeSessions{})
+
+	// 恢复session的同时执行命令，使用 go test -race 检查sessionId的读写
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 20; i++ {
+			if err := session.RecreateSession(); err != nil {
+				t.Errorf("RecreateSession() error = %v", err)
+				return
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 20; i++ {
+			session.TapWithLocation(ElementLocation{X: 1, Y: 1})
+		}
+	}()
+	wg.Wait()
+}